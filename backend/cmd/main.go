@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"event-manager/internal/api"
+	"event-manager/internal/migrate"
 	"event-manager/internal/repository"
 	"event-manager/internal/service"
+	"event-manager/migrations"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -22,6 +27,20 @@ func main() {
 	// Load database configuration from environment
 	dbConfig := repository.LoadConfigFromEnv()
 
+	// `main migrate up|down [n]|status` manages the PostgreSQL schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(dbConfig, os.Args[2:]))
+	}
+
+	// DB_AUTO_MIGRATE=true applies pending migrations before serving
+	if dbConfig.Type == repository.DBTypePostgres && os.Getenv("DB_AUTO_MIGRATE") == "true" {
+		runner, client := newMigrateRunner(dbConfig)
+		if _, err := runner.Up(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		client.Close()
+	}
+
 	// Initialize repositories based on configuration
 	repos, err := repository.NewRepositories(dbConfig)
 	if err != nil {
@@ -29,20 +48,54 @@ func main() {
 	}
 	defer repos.Close()
 
-	// Initialize cache service with 30-second TTL
-	cacheService := service.NewCacheService(30 * time.Second)
+	// Pub/sub for the status stream
+	statusHub := service.NewStatusHub()
+
+	// Initialize cache service with 30-second TTL.
+	// CACHE_TYPE=postgres shares invalidations between replicas via LISTEN/NOTIFY.
+	var cacheService service.CacheService
+	switch cacheType := os.Getenv("CACHE_TYPE"); cacheType {
+	case "", "memory":
+		cacheService = service.NewMemoryCacheService(30 * time.Second)
+	case "postgres":
+		if dbConfig.Type != repository.DBTypePostgres {
+			log.Fatalf("CACHE_TYPE=postgres requires DB_TYPE=postgres")
+		}
+		pgCache, err := service.NewPostgresCacheService(dbConfig.Postgres.ConnString(), 30*time.Second, statusHub.Publish)
+		if err != nil {
+			log.Fatalf("Failed to initialize cache: %v", err)
+		}
+		defer pgCache.Close()
+		cacheService = pgCache
+	default:
+		log.Fatalf("Unknown CACHE_TYPE: %s", cacheType)
+	}
+	log.Printf("[Cache] Using %T", cacheService)
 
 	// Initialize services
-	eventService := service.NewEventService(repos.Events)
-	authService := service.NewAuthService(repos.Users)
-	interactionService := service.NewInteractionService(repos.Interactions, repos.Events, repos.Users, cacheService)
+	auditService := service.NewAuditService(repos.Audit, repos.Events)
+	eventService := service.NewEventService(repos.Events, repos.Interactions, cacheService, statusHub, auditService)
+	keyManager := newKeyManager(repos.SigningKeys)
+	go keyManager.Run(context.Background(), time.Minute)
+	authService := service.NewAuthService(repos.Users, repos.Sessions, keyManager, identityProviders()...)
+	profileService := service.NewProfileService(repos.Users, repos.Events)
+	interactionService := service.NewInteractionService(repos.Interactions, repos.Events, repos.Users, cacheService, statusHub, auditService)
+	interactionService.PseudonymKey = service.DerivePseudonymKey(os.Getenv("JWT_SECRET"))
+
+	// Open and close events at their configured StartTime / EndTime
+	scheduler := service.NewEventScheduler(repos.Events, auditService, 30*time.Second)
+	go scheduler.Run(context.Background())
 
 	// Initialize Handlers
 	authHandler := api.NewAuthHandler(authService)
 	eventHandler := api.NewEventHandler(eventService)
 	interactionHandler := api.NewInteractionHandler(interactionService)
+	auditHandler := api.NewAuditHandler(auditService)
+	adminHandler := api.NewAdminHandler(authService)
+	profileHandler := api.NewProfileHandler(profileService)
 
-	r := gin.Default()
+	r := gin.New()
+	r.Use(api.RequestLogger(), gin.Recovery())
 
 	// CORS Middleware
 	r.Use(func(c *gin.Context) {
@@ -61,34 +114,74 @@ func main() {
 
 	// Health Check
 	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "db_type": "postgres"})
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "db_type": dbConfig.Type})
 	})
 
+	// Public keys for verifying access tokens
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	// API Routes Group
 	apiGroup := r.Group("/api")
 
 	// Auth Routes (no authentication required)
 	apiGroup.POST("/auth/login", authHandler.Login)
+	apiGroup.POST("/auth/refresh", authHandler.Refresh)
+	apiGroup.POST("/auth/logout", authHandler.Logout)
 
 	// Protected Routes (require authentication)
 	protectedGroup := apiGroup.Group("")
-	protectedGroup.Use(api.AuthMiddleware())
+	protectedGroup.Use(api.AuthMiddleware(authService), api.RoleMiddleware(authService))
 	{
-		// Events
-		protectedGroup.POST("/events", eventHandler.CreateEvent)
+		// Current user's profile (custom name and per-event nicknames)
+		protectedGroup.GET("/me", profileHandler.GetProfile)
+		protectedGroup.PATCH("/me", profileHandler.UpdateProfile)
+
+		// Events (creating requires admin; managing an event is checked per event in the handlers)
+		protectedGroup.POST("/events", api.AdminMiddleware(), eventHandler.CreateEvent)
 		protectedGroup.GET("/events", eventHandler.ListEvents)
 		protectedGroup.GET("/events/by-tag", eventHandler.GetEventByTag)
 		protectedGroup.GET("/events/:id", eventHandler.GetEvent)
 		protectedGroup.GET("/events/:id/status", interactionHandler.GetEventStatus)
+		protectedGroup.POST("/events/:id/stream-ticket", authHandler.StreamTicket)
+		protectedGroup.GET("/events/:id/results", interactionHandler.GetVoteResults)
 		protectedGroup.POST("/events/:id/action", interactionHandler.HandleAction)
 		protectedGroup.PUT("/events/:id/status", eventHandler.UpdateEventStatus)
 		protectedGroup.PUT("/events/:id", eventHandler.UpdateEvent)
+		protectedGroup.POST("/events/:id/preview", eventHandler.PreviewEventUpdate)
 		protectedGroup.PUT("/events/:id/archive", eventHandler.ArchiveEvent)
 
+		// Co-organizers
+		protectedGroup.GET("/events/:id/organizers", eventHandler.ListOrganizers)
+		protectedGroup.POST("/events/:id/organizers", eventHandler.AddOrganizer)
+		protectedGroup.DELETE("/events/:id/organizers/:userId", eventHandler.RemoveOrganizer)
+		protectedGroup.PUT("/events/:id/owner", eventHandler.TransferOwnership)
+
 		// Interaction updates
 		protectedGroup.PATCH("/events/:id/records/:recordId/note", interactionHandler.UpdateRegistrationNote)
 		protectedGroup.PATCH("/events/:id/records/:recordId/content", interactionHandler.UpdateMemoContent)
 		protectedGroup.POST("/events/:id/records/:recordId/clap", interactionHandler.IncrementClapCount)
+
+		// Roster management (LINEUP, organizers only)
+		protectedGroup.POST("/events/:id/guests", interactionHandler.RegisterGuest)
+		protectedGroup.POST("/events/:id/records/:recordId/cancel", interactionHandler.CancelRegistration)
+		protectedGroup.POST("/events/:id/records/:recordId/promote", interactionHandler.PromoteRegistration)
+		protectedGroup.PUT("/events/:id/roster", interactionHandler.ReorderRoster)
+
+		// Audit log (organizers only)
+		protectedGroup.GET("/events/:id/audit", auditHandler.ListAudit)
+
+		// Admin roles (ADMIN_LIST only bootstraps the first admin)
+		protectedGroup.GET("/admins", api.AdminMiddleware(), adminHandler.ListAdmins)
+		protectedGroup.PUT("/admins/:userId", api.AdminMiddleware(), adminHandler.GrantAdmin)
+		protectedGroup.DELETE("/admins/:userId", api.AdminMiddleware(), adminHandler.RevokeAdmin)
+	}
+
+	// Server-Sent Events (EventSource cannot set headers, so a ticket from /stream-ticket
+	// comes in the query instead of the access token)
+	streamGroup := apiGroup.Group("")
+	streamGroup.Use(api.StreamTicketMiddleware(authService), api.RoleMiddleware(authService))
+	{
+		streamGroup.GET("/events/:id/stream", interactionHandler.StreamEventStatus)
 	}
 
 	port := os.Getenv("PORT")
@@ -98,3 +191,118 @@ func main() {
 	log.Printf("Server starting on port %s", port)
 	r.Run(":" + port)
 }
+
+// newKeyManager loads the access token signing keys, creating the first one if needed.
+// A missing or placeholder JWT_SECRET stops the server.
+func newKeyManager(repo repository.SigningKeyRepository) *service.KeyManager {
+	cfg, err := service.LoadKeyManagerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+	keys, err := service.NewKeyManager(repo, cfg)
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+	if err := keys.Load(context.Background()); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	log.Printf("[Keys] Signing access tokens with %s, rotating every %s", cfg.Algorithm, cfg.Rotation)
+	return keys
+}
+
+// identityProviders returns LINE login plus, when DEV_LOGIN=true, a development login that
+// trusts any user ID. The development login is refused when GO_ENV=production.
+func identityProviders() []service.IdentityProvider {
+	providers := []service.IdentityProvider{service.NewLineIdentityProvider(os.Getenv("LINE_CHANNEL_ID"))}
+	if os.Getenv("DEV_LOGIN") != "true" {
+		return providers
+	}
+	if os.Getenv("GO_ENV") == "production" {
+		log.Fatalf("DEV_LOGIN must not be enabled when GO_ENV=production")
+	}
+	log.Printf("[Auth] WARNING: development login enabled; anyone can log in as any dev: user")
+	return append(providers, service.NewDevIdentityProvider())
+}
+
+// newMigrateRunner opens the PostgreSQL database with the migrations embedded in the binary.
+// Only PostgreSQL is migrated; the other backends create their own schema.
+func newMigrateRunner(dbConfig *repository.Config) (*migrate.Runner, *repository.PostgresClient) {
+	if dbConfig.Type != repository.DBTypePostgres {
+		log.Fatalf("Migrations apply to DB_TYPE=postgres only; %s manages its own schema", dbConfig.Type)
+	}
+	client, err := repository.NewPostgresClient(&dbConfig.Postgres)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	runner, err := migrate.New(client.DB, migrations.FS)
+	if err != nil {
+		log.Fatalf("Invalid migrations: %v", err)
+	}
+	return runner, client
+}
+
+// runMigrate runs the migrate subcommand and returns the exit code. status exits 1 when
+// an applied migration was edited or is missing from this build.
+func runMigrate(dbConfig *repository.Config, args []string) int {
+	usage := "Usage: main migrate up | down [n] | status"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	ctx := context.Background()
+	runner, client := newMigrateRunner(dbConfig)
+	defer client.Close()
+	switch args[0] {
+	case "up":
+		ran, err := runner.Up(ctx)
+		if err != nil {
+			log.Printf("[Migrate] %v", err)
+			return 1
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(ran))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				fmt.Fprintln(os.Stderr, usage)
+				return 2
+			}
+			steps = n
+		}
+		ran, err := runner.Down(ctx, steps)
+		if err != nil {
+			log.Printf("[Migrate] %v", err)
+			return 1
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", len(ran))
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Printf("[Migrate] %v", err)
+			return 1
+		}
+		code := 0
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Local().Format(time.RFC3339)
+			}
+			switch {
+			case s.Unknown:
+				state += " (UNKNOWN to this build)"
+				code = 1
+			case s.Modified:
+				state += " (MODIFIED since applied)"
+				code = 1
+			}
+			fmt.Printf("%s  %s\n", s.Migration, state)
+		}
+		return code
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	return 0
+}
@@ -124,7 +124,7 @@ func main() {
 }
 
 func printUsage() {
-	fmt.Println(`
+	fmt.Print(`
 Firestore to PostgreSQL Migration Tool
 
 Usage: go run migrate.go <command>
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"event-manager/internal/repository"
+	"event-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	Service *service.AuthService
+}
+
+func NewAdminHandler(s *service.AuthService) *AdminHandler {
+	return &AdminHandler{Service: s}
+}
+
+func (h *AdminHandler) ListAdmins(c *gin.Context) {
+	admins, err := h.Service.ListAdmins(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"admins": admins})
+}
+
+func (h *AdminHandler) GrantAdmin(c *gin.Context) {
+	user, err := h.Service.GrantAdmin(c.Request.Context(), c.Param("userId"), c.GetString("uid"))
+	if err != nil {
+		if errors.Is(err, service.ErrUserIDRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) RevokeAdmin(c *gin.Context) {
+	userID := c.Param("userId")
+	if err := h.Service.RevokeAdmin(c.Request.Context(), userID, c.GetString("uid")); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotAdmin):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrLastAdmin):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "revoked", "userId": userID})
+}
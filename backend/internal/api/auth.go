@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -18,7 +19,17 @@ func NewAuthHandler(s *service.AuthService) *AuthHandler {
 }
 
 type LoginRequest struct {
-	IDToken string `json:"idToken" binding:"required"`
+	// Provider selects the identity provider: "line" (default) or "dev" when DEV_LOGIN is enabled
+	Provider string `json:"provider"`
+
+	IDToken string `json:"idToken"`
+	// Nonce is optional; when sent it must match the nonce claim of the ID token
+	Nonce string `json:"nonce"`
+
+	// Development login only
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	PictureURL  string `json:"pictureUrl"`
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -35,15 +46,92 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 	log.Printf("Login request received, token length: %d, preview: %s", len(req.IDToken), tokenPreview)
 
-	token, user, err := h.Service.Login(c.Request.Context(), req.IDToken)
+	tokens, user, err := h.Service.Login(c.Request.Context(), req.Provider, service.LoginCredentials{
+		IDToken:     req.IDToken,
+		Nonce:       req.Nonce,
+		UserID:      req.UserID,
+		DisplayName: req.DisplayName,
+		PictureURL:  req.PictureURL,
+	})
 	if err != nil {
 		log.Printf("Login failed: %v", err)
+		status := http.StatusUnauthorized
+		if errors.Is(err, service.ErrUnknownProvider) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":        tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"user":         user,
+	})
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, user, err := h.Service.Refresh(c.Request.Context(), req.RefreshToken)
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	if err != nil {
+		log.Printf("Refresh failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"user":  user,
+		"token":        tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"user":         user,
 	})
 }
+
+// Logout revokes the session of the given refresh token
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		log.Printf("Logout failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// JWKS publishes the public keys that verify access tokens
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Service.Keys.JWKS())
+}
+
+// StreamTicket issues a single-use ticket for opening the event's status stream, so the
+// access token never has to go in a URL
+func (h *AuthHandler) StreamTicket(c *gin.Context) {
+	ticket, err := h.Service.IssueStreamTicket(c.GetString("uid"), c.GetString("sid"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ticket)
+}
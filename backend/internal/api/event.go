@@ -1,10 +1,13 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 
 	"event-manager/internal/models"
+	"event-manager/internal/repository"
 	"event-manager/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +21,37 @@ func NewEventHandler(s *service.EventService) *EventHandler {
 	return &EventHandler{Service: s}
 }
 
+// authorize responds with 403 (or 404) and returns false unless the caller is an admin,
+// the event's creator or a co-organizer
+func (h *EventHandler) authorize(c *gin.Context, eventID string) bool {
+	if err := h.Service.AuthorizeManage(c.Request.Context(), eventID, viewerFromContext(c)); err != nil {
+		respondManageError(c, err)
+		return false
+	}
+	return true
+}
+
+// respondManageError maps errors from event management calls to HTTP statuses
+func respondManageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden), errors.Is(err, service.ErrOwnerOnly):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, repository.ErrOrganizerNotFound), errors.Is(err, repository.ErrNoActiveRegistration):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrRosterMismatch):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOrganizerIDRequired), errors.Is(err, service.ErrAlreadyOwner),
+		errors.Is(err, service.ErrNotLineUpEvent), errors.Is(err, service.ErrGuestNameRequired),
+		errors.Is(err, service.ErrGuestNameTooLong), errors.Is(err, service.ErrNotWaitlisted),
+		errors.Is(err, service.ErrNoSeatsConfigured), errors.Is(err, repository.ErrWaitlistFull):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *EventHandler) CreateEvent(c *gin.Context) {
 	var event models.Event
 	if err := c.ShouldBindJSON(&event); err != nil {
@@ -56,13 +90,17 @@ type UpdateStatusRequest struct {
 
 func (h *EventHandler) UpdateEventStatus(c *gin.Context) {
 	eventID := c.Param("id")
+	if !h.authorize(c, eventID) {
+		return
+	}
+
 	var req UpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.Service.UpdateEventStatus(c.Request.Context(), eventID, req.IsActive); err != nil {
+	if err := h.Service.UpdateEventStatus(c.Request.Context(), eventID, req.IsActive, c.GetString("uid")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -72,6 +110,10 @@ func (h *EventHandler) UpdateEventStatus(c *gin.Context) {
 
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	eventID := c.Param("id")
+	if !h.authorize(c, eventID) {
+		return
+	}
+
 	var event models.Event
 	if err := c.ShouldBindJSON(&event); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -81,7 +123,7 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	// Ensure eventID matches
 	event.EventID = eventID
 
-	updatedEvent, err := h.Service.UpdateEvent(c.Request.Context(), &event)
+	updatedEvent, err := h.Service.UpdateEvent(c.Request.Context(), &event, c.GetString("uid"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -90,6 +132,29 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedEvent)
 }
 
+func (h *EventHandler) PreviewEventUpdate(c *gin.Context) {
+	eventID := c.Param("id")
+	if !h.authorize(c, eventID) {
+		return
+	}
+
+	var event models.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event.EventID = eventID
+
+	changes, err := h.Service.PreviewEventUpdate(c.Request.Context(), &event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changes": changes})
+}
+
 func (h *EventHandler) ListEvents(c *gin.Context) {
 	events, err := h.Service.ListEvents(c.Request.Context(), 20)
 	if err != nil {
@@ -107,13 +172,17 @@ type ArchiveEventRequest struct {
 
 func (h *EventHandler) ArchiveEvent(c *gin.Context) {
 	eventID := c.Param("id")
+	if !h.authorize(c, eventID) {
+		return
+	}
+
 	var req ArchiveEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.Service.ArchiveEvent(c.Request.Context(), eventID, req.IsArchived); err != nil {
+	if err := h.Service.ArchiveEvent(c.Request.Context(), eventID, req.IsArchived, c.GetString("uid")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -136,3 +205,61 @@ func (h *EventHandler) GetEventByTag(c *gin.Context) {
 
 	c.JSON(http.StatusOK, event)
 }
+
+func (h *EventHandler) ListOrganizers(c *gin.Context) {
+	eventID := c.Param("id")
+	organizers, err := h.Service.ListOrganizers(c.Request.Context(), eventID, viewerFromContext(c))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, organizers)
+}
+
+type OrganizerRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
+func (h *EventHandler) AddOrganizer(c *gin.Context) {
+	eventID := c.Param("id")
+	var req OrganizerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.AddOrganizer(c.Request.Context(), eventID, req.UserID, viewerFromContext(c)); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "added", "userId": req.UserID})
+}
+
+func (h *EventHandler) RemoveOrganizer(c *gin.Context) {
+	eventID := c.Param("id")
+	userID := c.Param("userId")
+
+	if err := h.Service.RemoveOrganizer(c.Request.Context(), eventID, userID, viewerFromContext(c)); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "removed", "userId": userID})
+}
+
+func (h *EventHandler) TransferOwnership(c *gin.Context) {
+	eventID := c.Param("id")
+	var req OrganizerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.TransferOwnership(c.Request.Context(), eventID, req.UserID, viewerFromContext(c)); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "transferred", "owner": req.UserID})
+}
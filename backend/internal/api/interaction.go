@@ -1,9 +1,14 @@
 package api
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"event-manager/internal/models"
 	"event-manager/internal/service"
@@ -41,13 +46,7 @@ func (h *InteractionHandler) HandleAction(c *gin.Context) {
 		Type:   req.Type,
 	}
 
-	// Extract payload
-	if val, ok := req.Payload["userDisplayName"].(string); ok {
-		interaction.UserDisplayName = val
-	}
-	if val, ok := req.Payload["userPictureUrl"].(string); ok {
-		interaction.UserPictureUrl = val
-	}
+	// Extract payload (the display name and picture are taken from the user's profile)
 	if val, ok := req.Payload["selectedOptions"].([]interface{}); ok {
 		for _, v := range val {
 			switch opt := v.(type) {
@@ -74,14 +73,43 @@ func (h *InteractionHandler) HandleAction(c *gin.Context) {
 
 	log.Printf("[ACTION] Constructed interaction: %+v", interaction)
 
-	if err := h.Service.HandleAction(c.Request.Context(), eventID, &interaction); err != nil {
+	result, err := h.Service.HandleAction(c.Request.Context(), eventID, &interaction)
+	if err != nil {
 		log.Printf("[ACTION] HandleAction failed: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, actionErrorResponse(err))
 		return
 	}
 
-	log.Printf("[ACTION] Action successful")
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	log.Printf("[ACTION] Action successful, record: %s, promoted: %d", result.RecordID, len(result.Promoted))
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"recordId":     result.RecordID,
+		"recordStatus": result.Status,
+		"promoted":     promotedResponse(result.Promoted),
+	})
+}
+
+// promotedResponse lists promoted registrations by record ID and status only; they belong
+// to other users, whose identities the caller may not be allowed to see
+func promotedResponse(promoted []*models.Interaction) []gin.H {
+	result := make([]gin.H, 0, len(promoted))
+	for _, rec := range promoted {
+		result = append(result, gin.H{"recordId": rec.ID, "status": rec.Status})
+	}
+	return result
+}
+
+// actionErrorResponse adds the error code for actions rejected by event rules
+func actionErrorResponse(err error) gin.H {
+	var actionErr *service.ActionError
+	if errors.As(err, &actionErr) {
+		resp := gin.H{"error": actionErr.Message, "code": actionErr.Code}
+		if actionErr.Details != nil {
+			resp["details"] = actionErr.Details
+		}
+		return resp
+	}
+	return gin.H{"error": err.Error()}
 }
 
 func (h *InteractionHandler) UpdateRegistrationNote(c *gin.Context) {
@@ -149,7 +177,7 @@ func (h *InteractionHandler) IncrementClapCount(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.IncrementClapCount(c.Request.Context(), eventID, recordID); err != nil {
+	if err := h.Service.IncrementClapCount(c.Request.Context(), eventID, recordID, uid); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -157,12 +185,206 @@ func (h *InteractionHandler) IncrementClapCount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "success"})
 }
 
+type RegisterGuestRequest struct {
+	Name string `json:"name" binding:"required"`
+	Note string `json:"note"`
+}
+
+func (h *InteractionHandler) RegisterGuest(c *gin.Context) {
+	eventID := c.Param("id")
+	var req RegisterGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.Service.RegisterGuest(c.Request.Context(), eventID, req.Name, req.Note, viewerFromContext(c))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"recordId":     result.RecordID,
+		"recordStatus": result.Status,
+	})
+}
+
+func (h *InteractionHandler) CancelRegistration(c *gin.Context) {
+	eventID := c.Param("id")
+	recordID := c.Param("recordId")
+
+	result, err := h.Service.CancelRegistration(c.Request.Context(), eventID, recordID, viewerFromContext(c))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"recordId":     result.RecordID,
+		"recordStatus": result.Status,
+		"promoted":     promotedResponse(result.Promoted),
+	})
+}
+
+func (h *InteractionHandler) PromoteRegistration(c *gin.Context) {
+	eventID := c.Param("id")
+	recordID := c.Param("recordId")
+
+	changes, err := h.Service.PromoteRegistration(c.Request.Context(), eventID, recordID, viewerFromContext(c))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "changes": changes})
+}
+
+type ReorderRosterRequest struct {
+	RecordIDs []string `json:"recordIds" binding:"required"`
+}
+
+func (h *InteractionHandler) ReorderRoster(c *gin.Context) {
+	eventID := c.Param("id")
+	var req ReorderRosterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes, err := h.Service.ReorderRoster(c.Request.Context(), eventID, req.RecordIDs, viewerFromContext(c))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "changes": changes})
+}
+
+func viewerFromContext(c *gin.Context) service.Viewer {
+	return service.Viewer{
+		UserID:  c.GetString("uid"),
+		IsAdmin: c.GetString("role") == "admin",
+	}
+}
+
 func (h *InteractionHandler) GetEventStatus(c *gin.Context) {
 	eventID := c.Param("id")
-	status, err := h.Service.GetEventStatus(c.Request.Context(), eventID)
+
+	// ?since=<revision> returns only records changed after that revision
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		since, err := strconv.ParseInt(sinceParam, 10, 64)
+		if err != nil || since < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a non-negative revision number"})
+			return
+		}
+		status, err := h.Service.GetEventStatusSince(c.Request.Context(), eventID, viewerFromContext(c), since)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, status)
+		return
+	}
+
+	status, err := h.Service.GetEventStatus(c.Request.Context(), eventID, viewerFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, status)
 }
+
+func (h *InteractionHandler) GetVoteResults(c *gin.Context) {
+	eventID := c.Param("id")
+	results, err := h.Service.GetVoteResults(c.Request.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotVoteEvent) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamEventStatus pushes the viewer's event status over Server-Sent Events every time
+// the event changes. A reconnecting client that sends a current Last-Event-ID gets no
+// initial snapshot.
+func (h *InteractionHandler) StreamEventStatus(c *gin.Context) {
+	eventID := c.Param("id")
+	viewer := viewerFromContext(c)
+	ctx := c.Request.Context()
+
+	// Fail early with a normal JSON error if the event cannot be read
+	if _, err := h.Service.GetEventStatus(ctx, eventID, viewer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates, unsubscribe := h.Service.Hub.Subscribe(eventID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	sendStatus := func() bool {
+		// Read the revision first so the data sent is at least as new as its ID
+		revision := h.Service.Hub.Revision(eventID)
+		status, err := h.Service.GetEventStatus(ctx, eventID, viewer)
+		if err != nil {
+			log.Printf("[STREAM] Failed to load status for %s: %v", eventID, err)
+			return true
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			log.Printf("[STREAM] Failed to encode status for %s: %v", eventID, err)
+			return true
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: status\ndata: %s\n\n", revision, data); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	if !h.Service.Hub.IsCurrent(eventID, c.GetHeader("Last-Event-ID")) {
+		if !sendStatus() {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-updates:
+			if !sendStatus() {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -1,15 +1,19 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 
+	"event-manager/internal/service"
+
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates the access token and rejects it once its session is revoked
+func AuthMiddleware(auth *service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -23,31 +27,105 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			jwtSecret = "default-secret-do-not-use-in-prod"
+		claims, err := auth.Keys.Parse(tokenString)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
 		}
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Stream tickets are signed with the same keys but only open the event stream
+		if aud, _ := claims.GetAudience(); len(aud) > 0 {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+		sessionID, _ := claims["sid"].(string)
+		active, err := auth.SessionActive(c.Request.Context(), sessionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session revoked"})
 			return
 		}
 
 		c.Set("uid", claims["uid"])
 		c.Set("role", claims["role"])
+		c.Set("sid", sessionID)
+		c.Next()
+	}
+}
+
+// StreamTicketMiddleware authenticates the event stream with a ticket from the query
+// string, since EventSource cannot send headers. RoleMiddleware must follow it.
+func StreamTicketMiddleware(auth *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, err := auth.RedeemStreamTicket(c.Request.Context(), c.Query("ticket"), c.Param("id"))
+		if errors.Is(err, service.ErrInvalidStreamTicket) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session"})
+			return
+		}
+		c.Set("uid", userID)
+		c.Set("sid", sessionID)
+		c.Next()
+	}
+}
+
+// redactedQueryParams are query parameters that carry credentials
+var redactedQueryParams = []string{"ticket", "access_token"}
+
+// RequestLogger logs requests like gin.Logger but with credential query parameters
+// redacted, so stream tickets do not end up in the logs
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			p.StatusCode,
+			p.Latency,
+			p.ClientIP,
+			p.Method,
+			redactQuery(p.Path),
+			p.ErrorMessage,
+		)
+	})
+}
+
+func redactQuery(path string) string {
+	base, rawQuery, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base + "?REDACTED"
+	}
+	redacted := false
+	for _, name := range redactedQueryParams {
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return path
+	}
+	return base + "?" + query.Encode()
+}
+
+// RoleMiddleware replaces the role claim with the role currently stored in the database,
+// so granting or revoking admin takes effect without waiting for the token to expire
+func RoleMiddleware(auth *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := auth.CurrentRole(c.Request.Context(), c.GetString("uid"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user role"})
+			return
+		}
+		c.Set("role", role)
 		c.Next()
 	}
 }
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"event-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	Service *service.ProfileService
+}
+
+func NewProfileHandler(s *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{Service: s}
+}
+
+// UpdateProfileRequest is the body of PATCH /me. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	CustomName     *string           `json:"customName"`
+	EventNicknames map[string]string `json:"eventNicknames"` // eventID -> nickname, "" removes
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	profile, err := h.Service.GetProfile(c.Request.Context(), c.GetString("uid"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.Service.UpdateProfile(c.Request.Context(), c.GetString("uid"), service.ProfileUpdate{
+		CustomName:     req.CustomName,
+		EventNicknames: req.EventNicknames,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNameTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User or event not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,304 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrChecksumMismatch means an applied migration's file was edited afterwards
+	ErrChecksumMismatch = errors.New("applied migration has changed")
+	// ErrUnknownVersion means the database has a migration this build does not include,
+	// typically because it was migrated by a newer build
+	ErrUnknownVersion = errors.New("applied migration is unknown to this build")
+)
+
+// lockKey identifies the Postgres advisory lock held while migrating, so replicas
+// starting together apply each migration once
+const lockKey = 7_146_571_903
+
+// fileName matches NNN_name.up.sql and NNN_name.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one schema version
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of Up, recorded when the migration is applied
+}
+
+// String returns the migration's file name prefix, e.g. "003_add_event_organizers"
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+// Status describes one migration, known to this build or recorded in the database
+type Status struct {
+	Migration
+	AppliedAt *time.Time // nil while pending
+	Modified  bool       // applied, but the file's checksum has changed since
+	Unknown   bool       // applied, but missing from this build; only Version and Name are set
+}
+
+// Load reads the *.sql migrations in fsys, ordered by version. Every version needs both
+// an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must be NNN_name.up.sql or NNN_name.down.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %s: version %d is also used by %s", entry.Name(), version, m)
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+			sum := sha256.Sum256(data)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s: needs both an up and a down file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Runner applies migrations to a PostgreSQL database and records them in schema_migrations
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates a Runner for the migrations in fsys
+func New(db *sql.DB, fsys fs.FS) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// appliedMigration is a schema_migrations row
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// withLock runs fn on one connection holding the migration lock, after making sure
+// schema_migrations exists
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version     INTEGER PRIMARY KEY,
+			name        VARCHAR(100) NOT NULL,
+			checksum    CHAR(64) NOT NULL,
+			applied_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// applied returns the schema_migrations rows by version
+func applied(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var m appliedMigration
+		if err := rows.Scan(&version, &m.name, &m.checksum, &m.appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = m
+	}
+	return done, rows.Err()
+}
+
+// verify checks that every applied migration is known and unchanged
+func (r *Runner) verify(done map[int]appliedMigration) error {
+	known := make(map[int]Migration, len(r.migrations))
+	for _, m := range r.migrations {
+		known[m.Version] = m
+	}
+	for version, row := range done {
+		m, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: %03d_%s", ErrUnknownVersion, version, row.name)
+		}
+		if m.Checksum != row.checksum {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, m)
+		}
+	}
+	return nil
+}
+
+// Up applies every pending migration in version order, each in its own transaction,
+// and returns the ones it applied
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	ran := make([]Migration, 0)
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := r.verify(done); err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			record := `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`
+			if err := runInTx(ctx, conn, m.Up, record, m.Version, m.Name, m.Checksum); err != nil {
+				return fmt.Errorf("migration %s: %w", m, err)
+			}
+			log.Printf("[Migrate] Applied %s", m)
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down rolls back the latest steps applied migrations, newest first, and returns the
+// ones it rolled back
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	ran := make([]Migration, 0)
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := r.verify(done); err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			record := `DELETE FROM schema_migrations WHERE version = $1`
+			if err := runInTx(ctx, conn, m.Down, record, m.Version); err != nil {
+				return fmt.Errorf("rollback %s: %w", m, err)
+			}
+			log.Printf("[Migrate] Rolled back %s", m)
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Status lists every known migration plus any applied ones this build lacks, by version
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			status := Status{Migration: m}
+			if row, ok := done[m.Version]; ok {
+				appliedAt := row.appliedAt
+				status.AppliedAt = &appliedAt
+				status.Modified = row.checksum != m.Checksum
+				delete(done, m.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for version, row := range done {
+			appliedAt := row.appliedAt
+			statuses = append(statuses, Status{
+				Migration: Migration{Version: version, Name: row.name},
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+		return nil
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, err
+}
+
+// runInTx runs a migration script and its schema_migrations bookkeeping in one
+// transaction, so a failed migration leaves no trace
+func runInTx(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,211 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"event-manager/migrations"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+func TestLoadEmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range loaded {
+		if m.Version != i {
+			t.Errorf("migration %d has version %d; versions must have no gaps", i, m.Version)
+		}
+		if len(m.Checksum) != 64 {
+			t.Errorf("%s: checksum %q", m, m.Checksum)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"README.md":           {Data: []byte("ignored")},
+	}
+	loaded, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].String() != "001_first" || loaded[1].String() != "002_second" {
+		t.Fatalf("loaded %v, want 001_first then 002_second", loaded)
+	}
+	if loaded[0].Up != "CREATE TABLE a ();" || loaded[0].Down != "DROP TABLE a;" {
+		t.Errorf("001_first = %+v", loaded[0])
+	}
+
+	// The checksum covers the up script only
+	fsys["001_first.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE IF EXISTS a;")}
+	again, _ := Load(fsys)
+	if again[0].Checksum != loaded[0].Checksum {
+		t.Error("editing the down script changed the checksum")
+	}
+	fsys["001_first.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INT);")}
+	again, _ = Load(fsys)
+	if again[0].Checksum == loaded[0].Checksum {
+		t.Error("editing the up script kept the checksum")
+	}
+}
+
+func TestLoadRejectsBadSets(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing down": {
+			"001_first.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"bad name": {
+			"first.up.sql":   {Data: []byte("SELECT 1;")},
+			"first.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"duplicate version": {
+			"001_first.up.sql":   {Data: []byte("SELECT 1;")},
+			"001_first.down.sql": {Data: []byte("SELECT 1;")},
+			"001_other.up.sql":   {Data: []byte("SELECT 1;")},
+			"001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		}
+	}
+}
+
+// newTestDB connects to the database named by TEST_POSTGRES_DSN with a fresh schema as the
+// search path, so migrations run against an empty database. Tests are skipped when the
+// variable is not set.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	schema := "migrate_" + uuid.New().String()[:8]
+	if _, err := db.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		db.Close()
+	})
+
+	// One pooled connection, so the search path set here is the one the runner uses
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	if _, err := db.Exec(`SET search_path TO ` + schema); err != nil {
+		t.Fatalf("set search_path: %v", err)
+	}
+
+	return db
+}
+
+func TestRunnerUpDownStatus(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	runner, err := New(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, _ := Load(migrations.FS)
+
+	ran, err := runner.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(ran) != len(all) {
+		t.Fatalf("Up applied %d migrations, want %d", len(ran), len(all))
+	}
+	if ran, err := runner.Up(ctx); err != nil || len(ran) != 0 {
+		t.Fatalf("second Up applied %d, err %v; want nothing", len(ran), err)
+	}
+
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil || s.Modified || s.Unknown {
+			t.Errorf("%s: %+v, want applied and unchanged", s.Migration, s)
+		}
+	}
+
+	// Roll everything back and forward again; every down must undo its up
+	if ran, err := runner.Down(ctx, len(all)); err != nil || len(ran) != len(all) {
+		t.Fatalf("Down rolled back %d, err %v; want %d", len(ran), err, len(all))
+	}
+	var tables int
+	db.QueryRow(`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'`).Scan(&tables)
+	if tables != 0 {
+		t.Errorf("%d tables left after rolling everything back", tables)
+	}
+	if _, err := runner.Up(ctx); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+
+	// One step back leaves the latest migration pending
+	if ran, err := runner.Down(ctx, 1); err != nil || len(ran) != 1 || ran[0].Version != all[len(all)-1].Version {
+		t.Fatalf("Down(1) = %v, %v", ran, err)
+	}
+	statuses, _ = runner.Status(ctx)
+	if last := statuses[len(statuses)-1]; last.AppliedAt != nil {
+		t.Errorf("%s still applied after Down(1)", last.Migration)
+	}
+}
+
+func TestRunnerRejectsChangedAndUnknownMigrations(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	original := fstest.MapFS{
+		"001_widgets.up.sql":   {Data: []byte("CREATE TABLE widgets (id INT);")},
+		"001_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")},
+	}
+	runner, _ := New(db, original)
+	if _, err := runner.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	edited := fstest.MapFS{
+		"001_widgets.up.sql":   {Data: []byte("CREATE TABLE widgets (id BIGINT);")},
+		"001_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")},
+	}
+	runner, _ = New(db, edited)
+	if _, err := runner.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Up with an edited migration: err = %v, want ErrChecksumMismatch", err)
+	}
+	if statuses, _ := runner.Status(ctx); len(statuses) != 1 || !statuses[0].Modified {
+		t.Errorf("Status = %+v, want 001 modified", statuses)
+	}
+
+	runner, _ = New(db, fstest.MapFS{})
+	if _, err := runner.Up(ctx); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Up without the applied migration: err = %v, want ErrUnknownVersion", err)
+	}
+	if statuses, _ := runner.Status(ctx); len(statuses) != 1 || !statuses[0].Unknown {
+		t.Errorf("Status = %+v, want 001 unknown", statuses)
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one mutating action on an event or one of its records
+type AuditEntry struct {
+	ID        int64           `json:"id" firestore:"-"`
+	EventID   string          `json:"eventId" firestore:"eventId"`
+	RecordID  string          `json:"recordId,omitempty" firestore:"recordId,omitempty"`
+	Actor     string          `json:"actor" firestore:"actor"`   // LINE user ID, or "system:<component>"
+	Action    string          `json:"action" firestore:"action"` // e.g. EVENT_UPDATE, LINEUP_CANCEL
+	Before    json.RawMessage `json:"before,omitempty" firestore:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty" firestore:"after,omitempty"`
+	CreatedAt time.Time       `json:"createdAt" firestore:"createdAt"`
+}
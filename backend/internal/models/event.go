@@ -12,9 +12,10 @@ const (
 
 type EventConfig struct {
 	// VOTE
-	MaxVotes   int      `json:"maxVotes,omitempty" firestore:"maxVotes,omitempty"`     // 1 = single select, >1 = multi-select
-	ShowVoters *bool    `json:"showVoters,omitempty" firestore:"showVoters,omitempty"` // true = show voters, false = anonymous (pointer to distinguish unset from false)
-	Options    []string `json:"options,omitempty" firestore:"options,omitempty"`
+	MaxVotes    int          `json:"maxVotes,omitempty" firestore:"maxVotes,omitempty"`     // 1 = single select, >1 = multi-select
+	ShowVoters  *bool        `json:"showVoters,omitempty" firestore:"showVoters,omitempty"` // true = show voters, false = anonymous (pointer to distinguish unset from false)
+	Options     []string     `json:"options,omitempty" firestore:"options,omitempty"`
+	VoteOptions []VoteOption `json:"voteOptions,omitempty" firestore:"voteOptions,omitempty"` // Stable IDs for Options, kept in sync by EventService
 
 	// LINEUP
 	MaxParticipants int       `json:"maxParticipants,omitempty" firestore:"maxParticipants,omitempty"`
@@ -29,6 +30,11 @@ type EventConfig struct {
 	AllowReaction      bool `json:"allowReaction,omitempty" firestore:"allowReaction,omitempty"`
 }
 
+type VoteOption struct {
+	ID    string `json:"id" firestore:"id"`
+	Label string `json:"label" firestore:"label"`
+}
+
 type Event struct {
 	EventID    string      `json:"eventId" firestore:"eventId"`
 	Type       EventType   `json:"type" firestore:"type"`
@@ -39,4 +45,17 @@ type Event struct {
 	CreatedBy  string      `json:"createdBy" firestore:"createdBy"`
 	CreatedAt  time.Time   `json:"createdAt" firestore:"createdAt"`
 	Config     EventConfig `json:"config" firestore:"config"`
+
+	// StatusChangedAt is when IsActive was last set by an admin or the scheduler; nil if
+	// never since creation
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty" firestore:"statusChangedAt,omitempty"`
+}
+
+// EventOrganizer grants a user the same management rights on an event as its creator
+type EventOrganizer struct {
+	EventID         string    `json:"eventId" firestore:"eventId"`
+	UserID          string    `json:"userId" firestore:"userId"`
+	UserDisplayName string    `json:"userDisplayName,omitempty" firestore:"-"` // From users, empty if the user never logged in
+	AddedBy         string    `json:"addedBy" firestore:"addedBy"`
+	AddedAt         time.Time `json:"addedAt" firestore:"addedAt"`
 }
@@ -17,6 +17,7 @@ type Interaction struct {
 	UserPictureUrl  string          `json:"userPictureUrl,omitempty" firestore:"userPictureUrl,omitempty"`
 	Type            InteractionType `json:"type" firestore:"type"`
 	Timestamp       time.Time       `json:"timestamp" firestore:"timestamp"`
+	Revision        int64           `json:"revision,omitempty" firestore:"revision,omitempty"` // Event revision at the last write
 
 	// VOTE
 	SelectedOptions []string `json:"selectedOptions,omitempty" firestore:"selectedOptions,omitempty"`
@@ -26,9 +27,22 @@ type Interaction struct {
 	Status      string     `json:"status,omitempty" firestore:"status,omitempty"` // SUCCESS | WAITLIST | CANCELLED
 	Note        string     `json:"note,omitempty" firestore:"note,omitempty"`
 	CancelledAt *time.Time `json:"cancelledAt,omitempty" firestore:"cancelledAt,omitempty"` // Timestamp when cancelled (soft delete)
+	PromotedAt  *time.Time `json:"promotedAt,omitempty" firestore:"promotedAt,omitempty"`   // Timestamp when promoted from WAITLIST to SUCCESS
+	AddedBy     string     `json:"addedBy,omitempty" firestore:"addedBy,omitempty"`         // Organizer who registered a guest
+	CancelledBy string     `json:"cancelledBy,omitempty" firestore:"cancelledBy,omitempty"` // User who cancelled (the registrant or an organizer)
+	QueuedAt    *time.Time `json:"queuedAt,omitempty" firestore:"queuedAt,omitempty"`       // Queue position set by an organizer's reorder; Timestamp stays the registration time
 
 	// MEMO
 	Content   string   `json:"content,omitempty" firestore:"content,omitempty"`
 	ClapCount int      `json:"clapCount,omitempty" firestore:"clapCount,omitempty"` // Clap reactions count (max 99)
 	Reactions []string `json:"reactions,omitempty" firestore:"reactions,omitempty"`
 }
+
+// QueueTime orders LINEUP registrations: QueuedAt once an organizer has reordered the
+// roster, otherwise the registration Timestamp
+func (i *Interaction) QueueTime() time.Time {
+	if i.QueuedAt != nil {
+		return *i.QueuedAt
+	}
+	return i.Timestamp
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Session is a login session. Its refresh token is rotated on every use; access tokens
+// carry the session ID so revoking the session cuts them off too.
+type Session struct {
+	ID                string     `json:"id" firestore:"-"`
+	UserID            string     `json:"userId" firestore:"userId"`
+	RefreshTokenHash  string     `json:"-" firestore:"refreshTokenHash"`
+	PreviousTokenHash string     `json:"-" firestore:"previousTokenHash,omitempty"` // detects reuse of a rotated token
+	CreatedAt         time.Time  `json:"createdAt" firestore:"createdAt"`
+	LastUsedAt        time.Time  `json:"lastUsedAt" firestore:"lastUsedAt"`
+	ExpiresAt         time.Time  `json:"expiresAt" firestore:"expiresAt"`
+	RevokedAt         *time.Time `json:"revokedAt,omitempty" firestore:"revokedAt,omitempty"`
+}
+
+// Active reports whether the session can still be used at now
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
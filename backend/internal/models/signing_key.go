@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SigningKey is a key used to sign access tokens. PrivateKey holds the PKCS#8 DER key
+// encrypted with JWT_SECRET, so the database alone cannot mint tokens.
+type SigningKey struct {
+	ID         string    `json:"kid" firestore:"-"`
+	Algorithm  string    `json:"alg" firestore:"algorithm"` // "RS256" or "EdDSA"
+	PrivateKey []byte    `json:"-" firestore:"privateKey"`
+	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt" firestore:"expiresAt"` // Stops verifying tokens after this
+}
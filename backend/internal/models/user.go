@@ -10,3 +10,19 @@ type User struct {
 	Role            string    `json:"role" firestore:"role"` // "admin" or "user"
 	CreatedAt       time.Time `json:"createdAt" firestore:"createdAt"`
 }
+
+// DisplayName is the name shown to others: the custom name if set, else the LINE name
+func (u *User) DisplayName() string {
+	if u.CustomName != "" {
+		return u.CustomName
+	}
+	return u.LineDisplayName
+}
+
+// EventNickname is a name the user chose for one event; it overrides DisplayName there
+type EventNickname struct {
+	EventID   string    `json:"eventId" firestore:"eventId"`
+	UserID    string    `json:"userId" firestore:"userId"`
+	Nickname  string    `json:"nickname" firestore:"nickname"`
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestEventRepositoryContract(t *testing.T) {
+	runContract(t, eventContractCases)
+}
+
+var eventContractCases = []contractCase{
+	{"create and get round-trip", func(t *testing.T, env *contractEnv) {
+		showVoters := true
+		config := models.EventConfig{
+			MaxVotes:    2,
+			ShowVoters:  &showVoters,
+			Options:     []string{"a", "b"},
+			VoteOptions: []models.VoteOption{{ID: "o1", Label: "a"}, {ID: "o2", Label: "b"}},
+			StartTime:   contractTime.Add(time.Hour),
+		}
+		want := env.createEvent(t, "ev", models.EventTypeVote, config, 0)
+
+		got, err := env.Events.GetByID(context.Background(), want.EventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.EventID != want.EventID || got.Type != want.Type || got.Title != want.Title ||
+			got.IsActive != want.IsActive || got.IsArchived || got.CreatedBy != want.CreatedBy ||
+			!got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("GetByID = %+v, want %+v", got, want)
+		}
+		if !got.Config.StartTime.Equal(config.StartTime) || !reflect.DeepEqual(got.Config.VoteOptions, config.VoteOptions) ||
+			!reflect.DeepEqual(got.Config.Options, config.Options) || got.Config.ShowVoters == nil || !*got.Config.ShowVoters {
+			t.Errorf("Config = %+v, want %+v", got.Config, config)
+		}
+	}},
+
+	{"create rejects a duplicate ID", func(t *testing.T, env *contractEnv) {
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		if err := env.Events.Create(context.Background(), event); err == nil {
+			t.Fatal("second Create with the same ID succeeded")
+		}
+	}},
+
+	{"unknown ID is sql.ErrNoRows", func(t *testing.T, env *contractEnv) {
+		if _, err := env.Events.GetByID(context.Background(), env.id("missing")); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"GetByTag returns the newest event with the tag", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		tag := env.id("tag")
+		for i, name := range []string{"old", "new", "middle"} {
+			event := env.createEvent(t, name, models.EventTypeMemo, models.EventConfig{}, []time.Duration{0, 2 * time.Hour, time.Hour}[i])
+			event.Tag = tag
+			if err := env.Events.Update(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := env.Events.GetByTag(ctx, tag)
+		if err != nil || got.EventID != env.id("new") || got.Tag != tag {
+			t.Fatalf("GetByTag = %+v, %v; want %s", got, err, env.id("new"))
+		}
+		if _, err := env.Events.GetByTag(ctx, env.id("other")); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("unknown tag: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"Update keeps owner, creation time and archive flag", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 5}, 0)
+		if err := env.Events.UpdateArchived(ctx, event.EventID, true); err != nil {
+			t.Fatal(err)
+		}
+
+		changed := *event
+		changed.Title = "renamed"
+		changed.IsActive = false
+		changed.CreatedBy = "someone else"
+		changed.CreatedAt = contractTime.Add(time.Hour)
+		changed.IsArchived = false
+		changed.Config = models.EventConfig{MaxParticipants: 9}
+		if err := env.Events.Update(ctx, &changed); err != nil {
+			t.Fatal(err)
+		}
+
+		got, _ := env.Events.GetByID(ctx, event.EventID)
+		if got.Title != "renamed" || got.IsActive || got.Config.MaxParticipants != 9 {
+			t.Errorf("updated fields not stored: %+v", got)
+		}
+		if got.CreatedBy != event.CreatedBy || !got.CreatedAt.Equal(event.CreatedAt) || !got.IsArchived {
+			t.Errorf("Update changed owner, creation time or archive flag: %+v", got)
+		}
+
+		if err := env.Events.UpdateStatus(ctx, event.EventID, true); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := env.Events.GetByID(ctx, event.EventID); !got.IsActive {
+			t.Error("UpdateStatus did not activate the event")
+		}
+	}},
+
+	{"List returns newest first up to limit", func(t *testing.T, env *contractEnv) {
+		// Far in the future so these are the newest events even in a shared database
+		future := 100 * 365 * 24 * time.Hour
+		for i, name := range []string{"a", "b", "c"} {
+			env.createEvent(t, name, models.EventTypeMemo, models.EventConfig{}, future+time.Duration(i)*time.Minute)
+		}
+
+		events, err := env.Events.List(context.Background(), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 2 || events[0].EventID != env.id("c") || events[1].EventID != env.id("b") {
+			t.Fatalf("List(2) = %v", eventIDs(events))
+		}
+	}},
+
+	{"ListScheduled matches start or end in (from, to] and skips archived", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		from, to := contractTime, contractTime.Add(time.Hour)
+		env.createEvent(t, "starts", models.EventTypeLineUp, models.EventConfig{StartTime: to}, 0)
+		env.createEvent(t, "ends", models.EventTypeLineUp, models.EventConfig{EndTime: from.Add(time.Minute)}, time.Minute)
+		env.createEvent(t, "at-from", models.EventTypeLineUp, models.EventConfig{StartTime: from}, 0)
+		env.createEvent(t, "later", models.EventTypeLineUp, models.EventConfig{StartTime: to.Add(time.Second)}, 0)
+		env.createEvent(t, "unset", models.EventTypeLineUp, models.EventConfig{}, 0)
+		archived := env.createEvent(t, "archived", models.EventTypeLineUp, models.EventConfig{StartTime: to}, 0)
+		if err := env.Events.UpdateArchived(ctx, archived.EventID, true); err != nil {
+			t.Fatal(err)
+		}
+
+		events, err := env.Events.ListScheduled(ctx, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := eventIDs(withPrefix(env, events, func(e *models.Event) string { return e.EventID }))
+		want := []string{env.id("ends"), env.id("starts")}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListScheduled = %v, want %v", got, want)
+		}
+	}},
+
+	{"organizers", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{}, 0)
+		named := env.createUser(t, "named", "user", 0)
+		if err := env.Users.UpdateFields(ctx, named.LineUserID, map[string]interface{}{"customName": "Custom"}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, id := range []string{named.LineUserID, env.id("stranger"), named.LineUserID} {
+			if err := env.Events.AddOrganizer(ctx, event.EventID, id, env.id("owner")); err != nil {
+				t.Fatalf("AddOrganizer(%s): %v", id, err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		organizers, err := env.Events.ListOrganizers(ctx, event.EventID)
+		if err != nil || len(organizers) != 2 {
+			t.Fatalf("ListOrganizers = %+v, %v", organizers, err)
+		}
+		if organizers[0].UserID != named.LineUserID || organizers[0].UserDisplayName != "Custom" ||
+			organizers[1].UserID != env.id("stranger") || organizers[1].UserDisplayName != "" ||
+			organizers[0].AddedBy != env.id("owner") {
+			t.Errorf("ListOrganizers = %+v, %+v", organizers[0], organizers[1])
+		}
+
+		if ok, err := env.Events.IsOrganizer(ctx, event.EventID, named.LineUserID); err != nil || !ok {
+			t.Errorf("IsOrganizer = %v, %v", ok, err)
+		}
+		if err := env.Events.RemoveOrganizer(ctx, event.EventID, named.LineUserID); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.Events.RemoveOrganizer(ctx, event.EventID, named.LineUserID); !errors.Is(err, ErrOrganizerNotFound) {
+			t.Errorf("second RemoveOrganizer: err = %v, want ErrOrganizerNotFound", err)
+		}
+		if ok, _ := env.Events.IsOrganizer(ctx, event.EventID, named.LineUserID); ok {
+			t.Error("removed organizer is still an organizer")
+		}
+	}},
+
+	{"TransferOwnership", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{}, 0)
+		newOwner := env.id("co")
+		if err := env.Events.AddOrganizer(ctx, event.EventID, newOwner, event.CreatedBy); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := env.Events.TransferOwnership(ctx, event.EventID, newOwner, env.id("admin")); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := env.Events.GetByID(ctx, event.EventID)
+		organizers, _ := env.Events.ListOrganizers(ctx, event.EventID)
+		if got.CreatedBy != newOwner || len(organizers) != 1 || organizers[0].UserID != event.CreatedBy || organizers[0].AddedBy != env.id("admin") {
+			t.Fatalf("after transfer: owner %s, organizers %+v", got.CreatedBy, organizers)
+		}
+
+		// Transferring to the current owner changes nothing
+		if err := env.Events.TransferOwnership(ctx, event.EventID, newOwner, env.id("admin")); err != nil {
+			t.Fatal(err)
+		}
+		if organizers, _ := env.Events.ListOrganizers(ctx, event.EventID); len(organizers) != 1 {
+			t.Fatalf("no-op transfer changed organizers: %+v", organizers)
+		}
+
+		if err := env.Events.TransferOwnership(ctx, env.id("missing"), newOwner, env.id("admin")); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("unknown event: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+}
+
+func eventIDs(events []*models.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.EventID)
+	}
+	return ids
+}
@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestInteractionRepositoryContract(t *testing.T) {
+	runContract(t, interactionContractCases)
+}
+
+// lineUp is a LINEUP registration by user at contractTime plus offset
+func lineUp(env *contractEnv, user string, offset time.Duration) *models.Interaction {
+	return &models.Interaction{
+		UserID:          env.id(user),
+		UserDisplayName: user,
+		Type:            models.InteractionTypeLineUp,
+		Count:           1,
+		Timestamp:       contractTime.Add(offset),
+	}
+}
+
+// register calls RegisterLineUp and fails the test on error
+func register(t *testing.T, env *contractEnv, eventID string, rec *models.Interaction) string {
+	t.Helper()
+	id, err := env.Interactions.RegisterLineUp(context.Background(), eventID, rec, false)
+	if err != nil {
+		t.Fatalf("RegisterLineUp(%s): %v", rec.UserID, err)
+	}
+	return id
+}
+
+// statuses returns the status of each of the event's LINEUP records by record ID
+func statuses(t *testing.T, env *contractEnv, eventID string) map[string]string {
+	t.Helper()
+	records, err := env.Interactions.GetByEventID(context.Background(), eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make(map[string]string)
+	for _, rec := range records {
+		result[rec.ID] = rec.Status
+	}
+	return result
+}
+
+var interactionContractCases = []contractCase{
+	{"create and get round-trip", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{}, 0)
+		promotedAt := contractTime.Add(time.Minute)
+		want := &models.Interaction{
+			UserID:          env.id("u1"),
+			UserDisplayName: "User One",
+			UserPictureUrl:  "https://example.com/u1",
+			Type:            models.InteractionTypeLineUp,
+			Timestamp:       contractTime,
+			Count:           1,
+			Status:          "SUCCESS",
+			Note:            "note",
+			PromotedAt:      &promotedAt,
+			AddedBy:         env.id("owner"),
+		}
+
+		id, err := env.Interactions.Create(ctx, event.EventID, want)
+		if err != nil || id == "" {
+			t.Fatalf("Create = %q, %v", id, err)
+		}
+		got, err := env.Interactions.GetByID(ctx, event.EventID, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != id || got.UserID != want.UserID || got.UserDisplayName != want.UserDisplayName ||
+			got.UserPictureUrl != want.UserPictureUrl || got.Type != want.Type || !got.Timestamp.Equal(want.Timestamp) ||
+			got.Count != 1 || got.Status != "SUCCESS" || got.Note != "note" || got.AddedBy != want.AddedBy ||
+			got.PromotedAt == nil || !got.PromotedAt.Equal(promotedAt) || got.CancelledAt != nil {
+			t.Errorf("GetByID = %+v, want %+v", got, want)
+		}
+
+		revision, err := env.Interactions.GetRevision(ctx, event.EventID)
+		if err != nil || revision != 1 || got.Revision != 1 {
+			t.Errorf("revision = %d (record %d), %v; want 1", revision, got.Revision, err)
+		}
+
+		if _, err := env.Interactions.GetByID(ctx, event.EventID, env.id("missing")); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("unknown record: err = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := env.Interactions.GetRevision(ctx, env.id("missing")); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("unknown event revision: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"writes to an unknown event fail", func(t *testing.T, env *contractEnv) {
+		if _, err := env.Interactions.Create(context.Background(), env.id("missing"), lineUp(env, "u1", 0)); err == nil {
+			t.Fatal("Create for an unknown event succeeded")
+		}
+	}},
+
+	{"GetByEventID and GetByUserAndType are in timestamp order", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		memo := func(user string, offset time.Duration) *models.Interaction {
+			return &models.Interaction{UserID: env.id(user), Type: models.InteractionTypeMemo, Content: user, Timestamp: contractTime.Add(offset)}
+		}
+		for _, rec := range []*models.Interaction{memo("b", 2*time.Second), memo("a", 0), memo("a", 3*time.Second), memo("c", time.Second)} {
+			if _, err := env.Interactions.Create(ctx, event.EventID, rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := env.Interactions.Create(ctx, event.EventID, lineUp(env, "a", 4*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		all, err := env.Interactions.GetByEventID(ctx, event.EventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var order []string
+		for _, rec := range all {
+			order = append(order, rec.UserID[len(env.prefix):])
+		}
+		if !reflect.DeepEqual(order, []string{"a", "c", "b", "a", "a"}) {
+			t.Errorf("GetByEventID order = %v", order)
+		}
+
+		mine, err := env.Interactions.GetByUserAndType(ctx, event.EventID, env.id("a"), models.InteractionTypeMemo)
+		if err != nil || len(mine) != 2 || !mine[0].Timestamp.Before(mine[1].Timestamp) {
+			t.Errorf("GetByUserAndType = %+v, %v", mine, err)
+		}
+	}},
+
+	{"CreateWithID upserts", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeVote, models.EventConfig{}, 0)
+		recordID := env.id("voter")
+		vote := &models.Interaction{
+			UserID:          recordID,
+			UserDisplayName: "Before",
+			Type:            models.InteractionTypeVote,
+			SelectedOptions: []string{"o1"},
+			Timestamp:       contractTime,
+		}
+		if err := env.Interactions.CreateWithID(ctx, event.EventID, recordID, vote); err != nil {
+			t.Fatal(err)
+		}
+
+		changed := *vote
+		changed.UserDisplayName = "After"
+		changed.SelectedOptions = []string{"o2", "o3"}
+		changed.Timestamp = contractTime.Add(time.Minute)
+		if err := env.Interactions.CreateWithID(ctx, event.EventID, recordID, &changed); err != nil {
+			t.Fatal(err)
+		}
+
+		all, _ := env.Interactions.GetByEventID(ctx, event.EventID)
+		if len(all) != 1 {
+			t.Fatalf("%d records after upsert, want 1", len(all))
+		}
+		got := all[0]
+		if got.ID != recordID || got.UserDisplayName != "After" || !reflect.DeepEqual(got.SelectedOptions, []string{"o2", "o3"}) ||
+			!got.Timestamp.Equal(changed.Timestamp) {
+			t.Errorf("after upsert = %+v", got)
+		}
+
+		// PostgreSQL runs the revision trigger for both the attempted insert and the update,
+		// so only require that the record moved to the event's latest revision
+		if revision, _ := env.Interactions.GetRevision(ctx, event.EventID); got.Revision < 2 || got.Revision != revision {
+			t.Errorf("record revision %d, event revision %d", got.Revision, revision)
+		}
+	}},
+
+	{"GetByEventIDSince returns records written after a revision", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		var ids []string
+		for i, user := range []string{"a", "b", "c"} {
+			id, err := env.Interactions.Create(ctx, event.EventID, &models.Interaction{
+				UserID: env.id(user), Type: models.InteractionTypeMemo, Timestamp: contractTime.Add(time.Duration(i) * time.Second),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		// Rewriting the first record moves it past the others
+		if err := env.Interactions.Update(ctx, event.EventID, ids[0], map[string]interface{}{"content": "edited"}); err != nil {
+			t.Fatal(err)
+		}
+
+		since, err := env.Interactions.GetByEventIDSince(ctx, event.EventID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		var revisions []int64
+		for _, rec := range since {
+			got = append(got, rec.ID)
+			revisions = append(revisions, rec.Revision)
+		}
+		if !reflect.DeepEqual(got, []string{ids[1], ids[2], ids[0]}) || !reflect.DeepEqual(revisions, []int64{2, 3, 4}) {
+			t.Errorf("GetByEventIDSince(1) = %v at %v", got, revisions)
+		}
+		if rest, _ := env.Interactions.GetByEventIDSince(ctx, event.EventID, 4); len(rest) != 0 {
+			t.Errorf("GetByEventIDSince(current) = %d records, want 0", len(rest))
+		}
+	}},
+
+	{"Update merges known fields", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		id, err := env.Interactions.Create(ctx, event.EventID, &models.Interaction{
+			UserID: env.id("u1"), Type: models.InteractionTypeMemo, Content: "hi", Reactions: []string{"x"}, Timestamp: contractTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = env.Interactions.Update(ctx, event.EventID, id, map[string]interface{}{
+			"clapCount": 3,
+			"status":    "DONE",
+			"unknown":   "ignored",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := env.Interactions.GetByID(ctx, event.EventID, id)
+		if got.ClapCount != 3 || got.Status != "DONE" || got.Content != "hi" || !reflect.DeepEqual(got.Reactions, []string{"x"}) {
+			t.Errorf("after Update = %+v", got)
+		}
+
+		if err := env.Interactions.Update(ctx, event.EventID, env.id("missing"), map[string]interface{}{"note": "x"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("unknown record: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"Delete", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		id, _ := env.Interactions.Create(ctx, event.EventID, &models.Interaction{UserID: env.id("u1"), Type: models.InteractionTypeMemo, Timestamp: contractTime})
+
+		if err := env.Interactions.Delete(ctx, event.EventID, id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.Interactions.GetByID(ctx, event.EventID, id); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("deleted record: err = %v, want sql.ErrNoRows", err)
+		}
+		if err := env.Interactions.Delete(ctx, event.EventID, id); err != nil {
+			t.Errorf("deleting a missing record: %v", err)
+		}
+	}},
+
+	{"records with undecodable payloads are skipped in lists", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeMemo, models.EventConfig{}, 0)
+		bad, _ := env.Interactions.Create(ctx, event.EventID, &models.Interaction{UserID: env.id("bad"), Type: models.InteractionTypeMemo, Timestamp: contractTime})
+		good, _ := env.Interactions.Create(ctx, event.EventID, &models.Interaction{UserID: env.id("good"), Type: models.InteractionTypeMemo, Timestamp: contractTime})
+		env.corruptPayload(t, event.EventID, bad)
+
+		all, err := env.Interactions.GetByEventID(ctx, event.EventID)
+		if err != nil || len(all) != 1 || all[0].ID != good {
+			t.Errorf("GetByEventID = %+v, %v; want only the good record", all, err)
+		}
+		if _, err := env.Interactions.GetByID(ctx, event.EventID, bad); err == nil {
+			t.Error("GetByID decoded a corrupt payload")
+		}
+	}},
+
+	{"RegisterLineUp enforces capacity, waitlist and per-user limits", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 2, WaitlistLimit: 1, MaxCountPerUser: 1}, 0)
+
+		for i, user := range []string{"a", "b", "c"} {
+			rec := lineUp(env, user, time.Duration(i)*time.Second)
+			id := register(t, env, event.EventID, rec)
+			want := "SUCCESS"
+			if i == 2 {
+				want = "WAITLIST"
+			}
+			if got, _ := env.Interactions.GetByID(ctx, event.EventID, id); rec.Status != want || got.Status != want {
+				t.Errorf("%s: status %q (stored %q), want %q", user, rec.Status, got.Status, want)
+			}
+		}
+
+		if _, err := env.Interactions.RegisterLineUp(ctx, event.EventID, lineUp(env, "d", 3*time.Second), false); !errors.Is(err, ErrWaitlistFull) {
+			t.Errorf("full waitlist: err = %v, want ErrWaitlistFull", err)
+		}
+		if _, err := env.Interactions.RegisterLineUp(ctx, event.EventID, lineUp(env, "a", 4*time.Second), false); !errors.Is(err, ErrRegistrationLimitReached) {
+			t.Errorf("second registration: err = %v, want ErrRegistrationLimitReached", err)
+		}
+		if _, err := env.Interactions.RegisterLineUp(ctx, env.id("missing"), lineUp(env, "a", 0), false); err == nil {
+			t.Error("RegisterLineUp for an unknown event succeeded")
+		}
+
+		// The exemption skips only the per-user limit
+		if _, err := env.Interactions.RegisterLineUp(ctx, event.EventID, lineUp(env, "a", 5*time.Second), true); !errors.Is(err, ErrWaitlistFull) {
+			t.Errorf("exempt registration: err = %v, want ErrWaitlistFull", err)
+		}
+	}},
+
+	{"LINEUP writes use the stored event capacity", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1}, 0)
+		a := register(t, env, event.EventID, lineUp(env, "a", 0))
+
+		event.Config.MaxParticipants = 2
+		if err := env.Events.Update(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+		b := register(t, env, event.EventID, lineUp(env, "b", time.Second))
+		c := register(t, env, event.EventID, lineUp(env, "c", 2*time.Second))
+		want := map[string]string{a: "SUCCESS", b: "SUCCESS", c: "WAITLIST"}
+		if got := statuses(t, env, event.EventID); !reflect.DeepEqual(got, want) {
+			t.Fatalf("statuses = %v, want %v", got, want)
+		}
+
+		// Back at one seat, a cancellation frees nothing for the waitlist
+		event.Config.MaxParticipants = 1
+		if err := env.Events.Update(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+		if _, promoted, err := env.Interactions.CancelLineUpRecord(ctx, event.EventID, a, env.id("owner")); err != nil || len(promoted) != 0 {
+			t.Errorf("cancel promoted %+v, %v; want none", promoted, err)
+		}
+	}},
+
+	{"CancelLineUp cancels the latest registration and promotes the waitlist", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 2}, 0)
+		first := register(t, env, event.EventID, lineUp(env, "a", 0))
+		latest := register(t, env, event.EventID, lineUp(env, "a", time.Second))
+		waiting1 := register(t, env, event.EventID, lineUp(env, "b", 3*time.Second))
+		waiting0 := register(t, env, event.EventID, lineUp(env, "c", 2*time.Second))
+
+		cancelled, promoted, err := env.Interactions.CancelLineUp(ctx, event.EventID, env.id("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cancelled.ID != latest || cancelled.Status != "CANCELLED" || cancelled.CancelledAt == nil || cancelled.CancelledBy != env.id("a") {
+			t.Errorf("cancelled = %+v, want %s", cancelled, latest)
+		}
+		if len(promoted) != 1 || promoted[0].ID != waiting0 || promoted[0].Status != "SUCCESS" || promoted[0].PromotedAt == nil {
+			t.Errorf("promoted = %+v, want only %s", promoted, waiting0)
+		}
+		want := map[string]string{first: "SUCCESS", latest: "CANCELLED", waiting0: "SUCCESS", waiting1: "WAITLIST"}
+		if got := statuses(t, env, event.EventID); !reflect.DeepEqual(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+
+		if _, _, err := env.Interactions.CancelLineUp(ctx, event.EventID, env.id("nobody")); !errors.Is(err, ErrNoActiveRegistration) {
+			t.Errorf("no registration: err = %v, want ErrNoActiveRegistration", err)
+		}
+	}},
+
+	{"CancelLineUpRecord records who cancelled", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1}, 0)
+		seat := register(t, env, event.EventID, lineUp(env, "a", 0))
+		waiting := register(t, env, event.EventID, lineUp(env, "b", time.Second))
+
+		cancelled, promoted, err := env.Interactions.CancelLineUpRecord(ctx, event.EventID, seat, env.id("owner"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cancelled.ID != seat || cancelled.CancelledBy != env.id("owner") || len(promoted) != 1 || promoted[0].ID != waiting {
+			t.Errorf("cancelled = %+v, promoted = %+v", cancelled, promoted)
+		}
+		if _, _, err := env.Interactions.CancelLineUpRecord(ctx, event.EventID, seat, env.id("owner")); !errors.Is(err, ErrNoActiveRegistration) {
+			t.Errorf("cancelling twice: err = %v, want ErrNoActiveRegistration", err)
+		}
+	}},
+
+	{"ReconcileLineUp", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1}, 0)
+		a := register(t, env, event.EventID, lineUp(env, "a", 0))
+		b := register(t, env, event.EventID, lineUp(env, "b", time.Second))
+
+		// Raising capacity to 2 should promote b
+		changes, err := env.Interactions.ReconcileLineUp(ctx, event.EventID, 2, true)
+		if err != nil || len(changes) != 1 || changes[0].RecordID != b || changes[0].From != "WAITLIST" || changes[0].To != "SUCCESS" {
+			t.Fatalf("dry run = %+v, %v", changes, err)
+		}
+		if got := statuses(t, env, event.EventID)[b]; got != "WAITLIST" {
+			t.Fatalf("dry run wrote status %q", got)
+		}
+
+		if _, err := env.Interactions.ReconcileLineUp(ctx, event.EventID, 2, false); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{a: "SUCCESS", b: "SUCCESS"}
+		if got := statuses(t, env, event.EventID); !reflect.DeepEqual(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+		if rec, _ := env.Interactions.GetByID(ctx, event.EventID, b); rec.PromotedAt == nil {
+			t.Error("promotion did not set promotedAt")
+		}
+	}},
+
+	{"UpdateLineUpEvent", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 2}, 0)
+		a := register(t, env, event.EventID, lineUp(env, "a", 0))
+		b := register(t, env, event.EventID, lineUp(env, "b", time.Second))
+
+		// Lowering capacity to 1 saves the event and demotes b together
+		event.Title = "renamed"
+		event.Config.MaxParticipants = 1
+		changes, err := env.Interactions.UpdateLineUpEvent(ctx, event)
+		if err != nil || len(changes) != 1 || changes[0].RecordID != b || changes[0].To != "WAITLIST" {
+			t.Fatalf("UpdateLineUpEvent = %+v, %v", changes, err)
+		}
+		want := map[string]string{a: "SUCCESS", b: "WAITLIST"}
+		if got := statuses(t, env, event.EventID); !reflect.DeepEqual(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+		saved, err := env.Events.GetByID(ctx, event.EventID)
+		if err != nil || saved.Title != "renamed" || saved.Config.MaxParticipants != 1 {
+			t.Fatalf("saved event = %+v, %v", saved, err)
+		}
+
+		missing := &models.Event{EventID: "missing", Type: models.EventTypeLineUp}
+		if _, err := env.Interactions.UpdateLineUpEvent(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("missing event: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"ReorderLineUp", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1}, 0)
+		a := register(t, env, event.EventID, lineUp(env, "a", 0))
+		b := register(t, env, event.EventID, lineUp(env, "b", time.Second))
+		c := register(t, env, event.EventID, lineUp(env, "c", 2*time.Second))
+
+		if _, err := env.Interactions.ReorderLineUp(ctx, event.EventID, []string{b, a}); !errors.Is(err, ErrRosterMismatch) {
+			t.Fatalf("partial order: err = %v, want ErrRosterMismatch", err)
+		}
+
+		changes, err := env.Interactions.ReorderLineUp(ctx, event.EventID, []string{c, b, a})
+		if err != nil || len(changes) != 2 {
+			t.Fatalf("ReorderLineUp = %+v, %v", changes, err)
+		}
+		want := map[string]string{a: "WAITLIST", b: "WAITLIST", c: "SUCCESS"}
+		if got := statuses(t, env, event.EventID); !reflect.DeepEqual(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+
+		// Registration times are kept; the order lives in QueuedAt
+		for i, id := range []string{a, b, c} {
+			rec, _ := env.Interactions.GetByID(ctx, event.EventID, id)
+			if !rec.Timestamp.Equal(contractTime.Add(time.Duration(i) * time.Second)) {
+				t.Errorf("%s: timestamp changed to %v", rec.UserID, rec.Timestamp)
+			}
+		}
+
+		// Later LINEUP operations follow the new order
+		if changes, _ := env.Interactions.ReconcileLineUp(ctx, event.EventID, 1, true); len(changes) != 0 {
+			t.Errorf("reconcile after reorder = %+v, want no changes", changes)
+		}
+		_, promoted, err := env.Interactions.CancelLineUpRecord(ctx, event.EventID, c, "admin")
+		if err != nil || len(promoted) != 1 || promoted[0].ID != b {
+			t.Fatalf("cancel promoted %+v, %v; want b", promoted, err)
+		}
+	}},
+
+	{"TallyVotes", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		event := env.createEvent(t, "ev", models.EventTypeVote, models.EventConfig{}, 0)
+		votes := map[string][]string{"a": {"o2", "o1"}, "b": {"o1"}, "c": {}}
+		for i, user := range []string{"a", "b", "c"} {
+			err := env.Interactions.CreateWithID(ctx, event.EventID, env.id(user), &models.Interaction{
+				UserID:          env.id(user),
+				UserDisplayName: user,
+				Type:            models.InteractionTypeVote,
+				SelectedOptions: votes[user],
+				Timestamp:       contractTime.Add(time.Duration(i) * time.Second),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		summary, err := env.Interactions.TallyVotes(ctx, event.EventID, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.TotalVoters != 2 || len(summary.Tallies) != 2 {
+			t.Fatalf("summary = %+v", summary)
+		}
+		o1, o2 := summary.Tallies[0], summary.Tallies[1]
+		if o1.OptionID != "o1" || o1.Count != 2 || len(o1.Voters) != 2 || o1.Voters[0].UserID != env.id("a") || o1.Voters[1].UserDisplayName != "b" {
+			t.Errorf("o1 tally = %+v", o1)
+		}
+		if o2.OptionID != "o2" || o2.Count != 1 {
+			t.Errorf("o2 tally = %+v", o2)
+		}
+
+		if summary, _ := env.Interactions.TallyVotes(ctx, event.EventID, false); summary.Tallies[0].Voters != nil {
+			t.Error("voters loaded without withVoters")
+		}
+	}},
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// The contract suite runs the same behaviors against every repository implementation, so
+// a new backend only has to be added to contractBackends. Backends that need external
+// services skip themselves when those are not configured.
+
+// contractEnv is one test's view of a backend. Every ID a test creates starts with prefix
+// so shared databases can be cleaned up and global listings filtered.
+type contractEnv struct {
+	*Repositories
+	prefix string
+
+	// corruptPayload makes the record's stored payload undecodable
+	corruptPayload func(t *testing.T, eventID, recordID string)
+}
+
+type contractBackend struct {
+	name string
+	open func(t *testing.T) *contractEnv
+}
+
+func contractBackends() []contractBackend {
+	return []contractBackend{
+		{name: "memory", open: openMemoryContract},
+		{name: "postgres", open: openPostgresContract},
+		{name: "firestore", open: openFirestoreContract},
+		{name: "sqlite", open: openSQLiteContract},
+	}
+}
+
+func newContractPrefix() string {
+	return "ct" + uuid.New().String()[:8] + "-"
+}
+
+func openMemoryContract(t *testing.T) *contractEnv {
+	repos := NewMemoryRepositories()
+	return &contractEnv{
+		Repositories: repos,
+		prefix:       newContractPrefix(),
+		corruptPayload: func(t *testing.T, eventID, recordID string) {
+			interactions := repos.Interactions.(*MemoryInteractionRepository)
+			interactions.mu.Lock()
+			defer interactions.mu.Unlock()
+			interactions.records[recordID].payload = []byte(`"not an object"`)
+		},
+	}
+}
+
+func openPostgresContract(t *testing.T) *contractEnv {
+	client := newTestPostgresClient(t)
+	prefix := newContractPrefix()
+	t.Cleanup(func() {
+		like := prefix + "%"
+		client.DB.Exec(`DELETE FROM events WHERE event_id LIKE $1`, like)
+		client.DB.Exec(`DELETE FROM users WHERE line_user_id LIKE $1`, like)
+		client.DB.Exec(`DELETE FROM audit_log WHERE event_id LIKE $1`, like)
+		client.DB.Exec(`DELETE FROM sessions WHERE user_id LIKE $1`, like)
+		client.DB.Exec(`DELETE FROM signing_keys WHERE kid LIKE $1`, like)
+	})
+
+	return &contractEnv{
+		Repositories: &Repositories{
+			Events:       NewPostgresEventRepository(client),
+			Interactions: NewPostgresInteractionRepository(client),
+			Users:        NewPostgresUserRepository(client),
+			Audit:        NewPostgresAuditRepository(client),
+			Sessions:     NewPostgresSessionRepository(client),
+			SigningKeys:  NewPostgresSigningKeyRepository(client),
+			Close:        func() error { return nil },
+		},
+		prefix: prefix,
+		corruptPayload: func(t *testing.T, eventID, recordID string) {
+			_, err := client.DB.Exec(`UPDATE interactions SET payload = '"not an object"' WHERE id = $1`, recordID)
+			if err != nil {
+				t.Fatalf("corrupt payload: %v", err)
+			}
+		},
+	}
+}
+
+// contractCase is one behavior every implementation must show
+type contractCase struct {
+	name string
+	run  func(t *testing.T, env *contractEnv)
+}
+
+func runContract(t *testing.T, cases []contractCase) {
+	for _, backend := range contractBackends() {
+		t.Run(backend.name, func(t *testing.T) {
+			for _, c := range cases {
+				t.Run(c.name, func(t *testing.T) {
+					env := backend.open(t)
+					c.run(t, env)
+				})
+			}
+		})
+	}
+}
+
+func (env *contractEnv) id(name string) string {
+	return env.prefix + name
+}
+
+// contractTime is a fixed point in time at the precision every backend stores
+var contractTime = time.Date(2030, 1, 2, 3, 4, 5, 123456000, time.UTC)
+
+// createEvent stores an event named env.id(name), created at contractTime plus offset
+func (env *contractEnv) createEvent(t *testing.T, name string, eventType models.EventType, config models.EventConfig, offset time.Duration) *models.Event {
+	t.Helper()
+	event := &models.Event{
+		EventID:   env.id(name),
+		Type:      eventType,
+		Title:     "contract " + name,
+		IsActive:  true,
+		CreatedBy: env.id("owner"),
+		CreatedAt: contractTime.Add(offset),
+		Config:    config,
+	}
+	if err := env.Events.Create(context.Background(), event); err != nil {
+		t.Fatalf("create event %s: %v", name, err)
+	}
+	return event
+}
+
+// createUser stores a user named env.id(name), created at contractTime plus offset
+func (env *contractEnv) createUser(t *testing.T, name, role string, offset time.Duration) *models.User {
+	t.Helper()
+	user := &models.User{
+		LineUserID:      env.id(name),
+		LineDisplayName: "LINE " + name,
+		PictureURL:      "https://example.com/" + name,
+		Role:            role,
+		CreatedAt:       contractTime.Add(offset),
+	}
+	if err := env.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", name, err)
+	}
+	return user
+}
+
+// withPrefix keeps the items whose ID starts with the test's prefix
+func withPrefix[T any](env *contractEnv, items []T, id func(T) string) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if strings.HasPrefix(id(item), env.prefix) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
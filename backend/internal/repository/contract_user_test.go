@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestUserRepositoryContract(t *testing.T) {
+	runContract(t, userContractCases)
+}
+
+var userContractCases = []contractCase{
+	{"create, get and exists", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		want := env.createUser(t, "u1", "user", 0)
+
+		got, err := env.Users.GetByID(ctx, want.LineUserID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.LineUserID != want.LineUserID || got.LineDisplayName != want.LineDisplayName || got.PictureURL != want.PictureURL ||
+			got.CustomName != "" || got.Role != "user" || !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("GetByID = %+v, want %+v", got, want)
+		}
+		if ok, err := env.Users.Exists(ctx, want.LineUserID); err != nil || !ok {
+			t.Errorf("Exists = %v, %v", ok, err)
+		}
+
+		if err := env.Users.Create(ctx, want); err == nil {
+			t.Error("second Create with the same ID succeeded")
+		}
+		if _, err := env.Users.GetByID(ctx, env.id("missing")); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("unknown user: err = %v, want sql.ErrNoRows", err)
+		}
+		if ok, err := env.Users.Exists(ctx, env.id("missing")); err != nil || ok {
+			t.Errorf("Exists(missing) = %v, %v", ok, err)
+		}
+	}},
+
+	{"UpdateFields changes only the given fields", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		user := env.createUser(t, "u1", "user", 0)
+
+		err := env.Users.UpdateFields(ctx, user.LineUserID, map[string]interface{}{
+			"customName": "Custom",
+			"pictureUrl": "https://example.com/new",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := env.Users.GetByID(ctx, user.LineUserID)
+		if got.CustomName != "Custom" || got.PictureURL != "https://example.com/new" ||
+			got.LineDisplayName != user.LineDisplayName || got.Role != "user" || got.DisplayName() != "Custom" {
+			t.Errorf("after UpdateFields = %+v", got)
+		}
+
+		// An empty custom name falls back to the LINE name
+		if err := env.Users.UpdateFields(ctx, user.LineUserID, map[string]interface{}{"customName": ""}); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := env.Users.GetByID(ctx, user.LineUserID); got.DisplayName() != user.LineDisplayName {
+			t.Errorf("DisplayName after clearing = %q", got.DisplayName())
+		}
+
+		if err := env.Users.UpdateFields(ctx, env.id("missing"), map[string]interface{}{"role": "admin"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("unknown user: err = %v, want sql.ErrNoRows", err)
+		}
+	}},
+
+	{"ListByRole is oldest first", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		env.createUser(t, "newer", "admin", time.Hour)
+		env.createUser(t, "older", "admin", 0)
+		env.createUser(t, "plain", "user", 0)
+
+		admins, err := env.Users.ListByRole(ctx, "admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		admins = withPrefix(env, admins, func(u *models.User) string { return u.LineUserID })
+		if len(admins) != 2 || admins[0].LineUserID != env.id("older") || admins[1].LineUserID != env.id("newer") {
+			t.Fatalf("ListByRole(admin) = %+v", admins)
+		}
+
+		all, _ := env.Users.ListByRole(ctx, "admin")
+		if n, err := env.Users.CountByRole(ctx, "admin"); err != nil || n != len(all) {
+			t.Errorf("CountByRole = %d, %v; ListByRole has %d", n, err, len(all))
+		}
+	}},
+
+	{"RevokeAdmin", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		first := env.createUser(t, "first", "admin", 0)
+		second := env.createUser(t, "second", "admin", time.Second)
+		plain := env.createUser(t, "plain", "user", 0)
+
+		if err := env.Users.RevokeAdmin(ctx, plain.LineUserID); !errors.Is(err, ErrNotAdmin) {
+			t.Errorf("non-admin: err = %v, want ErrNotAdmin", err)
+		}
+		if err := env.Users.RevokeAdmin(ctx, first.LineUserID); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := env.Users.GetByID(ctx, first.LineUserID); got.Role != "user" {
+			t.Errorf("role after RevokeAdmin = %q", got.Role)
+		}
+
+		// Only checkable when no other admins exist, as in a fresh backend
+		if n, _ := env.Users.CountByRole(ctx, "admin"); n == 1 {
+			if err := env.Users.RevokeAdmin(ctx, second.LineUserID); !errors.Is(err, ErrLastAdmin) {
+				t.Errorf("last admin: err = %v, want ErrLastAdmin", err)
+			}
+		}
+	}},
+
+	{"event nicknames", func(t *testing.T, env *contractEnv) {
+		ctx := context.Background()
+		user := env.createUser(t, "u1", "user", 0)
+		first := env.createEvent(t, "ev1", models.EventTypeMemo, models.EventConfig{}, 0)
+		second := env.createEvent(t, "ev2", models.EventTypeMemo, models.EventConfig{}, 0)
+
+		if nickname, err := env.Users.GetEventNickname(ctx, first.EventID, user.LineUserID); err != nil || nickname != "" {
+			t.Fatalf("nickname before set = %q, %v", nickname, err)
+		}
+		for _, set := range []struct{ event, nickname string }{
+			{first.EventID, "One"},
+			{second.EventID, "Two"},
+			{first.EventID, "Uno"},
+		} {
+			if err := env.Users.SetEventNickname(ctx, set.event, user.LineUserID, set.nickname); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		if nickname, _ := env.Users.GetEventNickname(ctx, first.EventID, user.LineUserID); nickname != "Uno" {
+			t.Errorf("nickname = %q, want Uno", nickname)
+		}
+		list, err := env.Users.ListEventNicknames(ctx, user.LineUserID)
+		if err != nil || len(list) != 2 || list[0].EventID != first.EventID || list[1].Nickname != "Two" {
+			t.Fatalf("ListEventNicknames = %+v, %v; want most recently updated first", list, err)
+		}
+
+		if err := env.Users.SetEventNickname(ctx, first.EventID, user.LineUserID, ""); err != nil {
+			t.Fatal(err)
+		}
+		if list, _ := env.Users.ListEventNicknames(ctx, user.LineUserID); len(list) != 1 || list[0].EventID != second.EventID {
+			t.Errorf("after clearing = %+v", list)
+		}
+	}},
+}
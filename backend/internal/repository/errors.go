@@ -0,0 +1,15 @@
+package repository
+
+import "errors"
+
+// Errors returned by repository operations that enforce business rules
+var (
+	ErrRegistrationLimitReached = errors.New("registration limit reached")
+	ErrWaitlistFull             = errors.New("waitlist is full")
+	ErrNoActiveRegistration     = errors.New("no active registration found")
+	ErrOrganizerNotFound        = errors.New("user is not an organizer of this event")
+	ErrRosterMismatch           = errors.New("order must list every active registration exactly once")
+	ErrNotAdmin                 = errors.New("user is not an admin")
+	ErrLastAdmin                = errors.New("cannot revoke the last admin")
+	ErrSessionNotFound          = errors.New("session not found or no longer active")
+)
@@ -2,13 +2,25 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"os"
 )
 
+// Database types selectable with DB_TYPE
+const (
+	DBTypePostgres  = "postgres"
+	DBTypeFirestore = "firestore"
+	DBTypeSQLite    = "sqlite" // A single database file; for single-node deployments (needs -tags sqlite)
+	DBTypeMemory    = "memory" // Nothing is persisted; for tests and demos
+)
+
 // Config holds database configuration
 type Config struct {
-	Postgres PostgresConfig // PostgreSQL configuration
+	Type      string          // DB_TYPE, defaults to DBTypePostgres
+	Postgres  PostgresConfig  // PostgreSQL configuration
+	Firestore FirestoreConfig // Firestore configuration
+	SQLite    SQLiteConfig    // SQLite configuration
 }
 
 // PostgresConfig holds PostgreSQL connection settings
@@ -21,9 +33,42 @@ type PostgresConfig struct {
 	SSLMode  string
 }
 
+// FirestoreConfig holds Firestore connection settings
+type FirestoreConfig struct {
+	ProjectID       string // Detected from the credentials when empty
+	CredentialsFile string // Service account JSON; not needed with FIRESTORE_EMULATOR_HOST
+}
+
+// SQLiteConfig holds SQLite settings
+type SQLiteConfig struct {
+	Path string // Database file, created with the schema if missing
+}
+
 // LoadConfigFromEnv creates a Config from environment variables
 func LoadConfigFromEnv() *Config {
-	cfg := &Config{}
+	cfg := &Config{
+		Type: getEnvOrDefault("DB_TYPE", DBTypePostgres),
+	}
+	if cfg.Type == DBTypeMemory {
+		log.Printf("[Config] Using in-memory repositories; data is lost on restart")
+		return cfg
+	}
+	if cfg.Type == DBTypeFirestore {
+		cfg.Firestore = FirestoreConfig{
+			ProjectID:       os.Getenv("FIRESTORE_PROJECT_ID"),
+			CredentialsFile: os.Getenv("FIREBASE_CREDENTIALS"),
+		}
+		log.Printf("[Config] Firestore: project=%s, emulator=%s",
+			cfg.Firestore.ProjectID, os.Getenv("FIRESTORE_EMULATOR_HOST"))
+		return cfg
+	}
+	if cfg.Type == DBTypeSQLite {
+		cfg.SQLite = SQLiteConfig{
+			Path: getEnvOrDefault("SQLITE_PATH", "eventmanager.db"),
+		}
+		log.Printf("[Config] SQLite: path=%s", cfg.SQLite.Path)
+		return cfg
+	}
 
 	// Load PostgreSQL config
 	cfg.Postgres = PostgresConfig{
@@ -48,8 +93,23 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-// NewRepositories creates PostgreSQL repository instances
+// NewRepositories creates the repository instances for cfg.Type
 func NewRepositories(cfg *Config) (*Repositories, error) {
+	switch cfg.Type {
+	case DBTypePostgres, "":
+		return newPostgresRepositories(cfg)
+	case DBTypeFirestore:
+		return newFirestoreRepositories(cfg)
+	case DBTypeSQLite:
+		return newSQLiteRepositories(cfg)
+	case DBTypeMemory:
+		return NewMemoryRepositories(), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_TYPE %q", cfg.Type)
+	}
+}
+
+func newPostgresRepositories(cfg *Config) (*Repositories, error) {
 	if cfg.Postgres.Password == "" {
 		return nil, errors.New("POSTGRES_PASSWORD is required")
 	}
@@ -65,8 +125,50 @@ func NewRepositories(cfg *Config) (*Repositories, error) {
 		Events:       NewPostgresEventRepository(client),
 		Interactions: NewPostgresInteractionRepository(client),
 		Users:        NewPostgresUserRepository(client),
+		Audit:        NewPostgresAuditRepository(client),
+		Sessions:     NewPostgresSessionRepository(client),
+		SigningKeys:  NewPostgresSigningKeyRepository(client),
+		Close: func() error {
+			return client.Close()
+		},
+	}, nil
+}
+
+func newFirestoreRepositories(cfg *Config) (*Repositories, error) {
+	client, err := NewFirestoreClient(&cfg.Firestore)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Repositories] Firestore client created")
+
+	return &Repositories{
+		Events:       NewFirestoreEventRepository(client),
+		Interactions: NewFirestoreInteractionRepository(client),
+		Users:        NewFirestoreUserRepository(client),
+		Audit:        NewFirestoreAuditRepository(client),
+		Sessions:     NewFirestoreSessionRepository(client),
+		SigningKeys:  NewFirestoreSigningKeyRepository(client),
 		Close: func() error {
 			return client.Close()
 		},
 	}, nil
 }
+
+// NewMemoryRepositories creates empty in-memory repository instances
+func NewMemoryRepositories() *Repositories {
+	users := NewMemoryUserRepository()
+	events := NewMemoryEventRepository(users)
+
+	return &Repositories{
+		Events:       events,
+		Interactions: NewMemoryInteractionRepository(events),
+		Users:        users,
+		Audit:        NewMemoryAuditRepository(),
+		Sessions:     NewMemorySessionRepository(),
+		SigningKeys:  NewMemorySigningKeyRepository(),
+		Close: func() error {
+			return nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// firestoreAuditEntry is an auditLog document. Before and After are kept as JSON text,
+// since Firestore would store a json.RawMessage as an array of numbers.
+type firestoreAuditEntry struct {
+	ID        int64     `firestore:"id"`
+	EventID   string    `firestore:"eventId"`
+	RecordID  string    `firestore:"recordId,omitempty"`
+	Actor     string    `firestore:"actor"`
+	Action    string    `firestore:"action"`
+	Before    string    `firestore:"before,omitempty"`
+	After     string    `firestore:"after,omitempty"`
+	CreatedAt time.Time `firestore:"createdAt"`
+}
+
+// FirestoreAuditRepository implements AuditRepository using Firestore. Entry IDs come
+// from a counter document so they keep the BIGSERIAL ordering of audit_log.id.
+type FirestoreAuditRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreAuditRepository creates a new FirestoreAuditRepository
+func NewFirestoreAuditRepository(client *FirestoreClient) *FirestoreAuditRepository {
+	return &FirestoreAuditRepository{client: client}
+}
+
+func (r *FirestoreAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	counterRef := r.client.Client.Collection(firestoreCounters).Doc(firestoreAuditLog)
+	var stored firestoreAuditEntry
+	err := r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var counter struct {
+			Value int64 `firestore:"value"`
+		}
+		doc, err := tx.Get(counterRef)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&counter); err != nil {
+				return err
+			}
+		}
+
+		stored = firestoreAuditEntry{
+			ID:        counter.Value + 1,
+			EventID:   entry.EventID,
+			RecordID:  entry.RecordID,
+			Actor:     entry.Actor,
+			Action:    entry.Action,
+			Before:    string(entry.Before),
+			After:     string(entry.After),
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Set(counterRef, map[string]interface{}{"value": stored.ID}); err != nil {
+			return err
+		}
+		return tx.Create(r.client.Client.Collection(firestoreAuditLog).NewDoc(), &stored)
+	})
+	if err != nil {
+		return err
+	}
+
+	entry.ID = stored.ID
+	entry.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (r *FirestoreAuditRepository) ListByEvent(ctx context.Context, eventID string, limit int) ([]*models.AuditEntry, error) {
+	q := r.client.Client.Collection(firestoreAuditLog).
+		Where("eventId", "==", eventID).
+		OrderBy("createdAt", firestore.Desc).
+		OrderBy("id", firestore.Desc).
+		Limit(limit)
+	docs, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*models.AuditEntry, 0, len(docs))
+	for _, doc := range docs {
+		var stored firestoreAuditEntry
+		if err := doc.DataTo(&stored); err != nil {
+			return nil, err
+		}
+		entry := &models.AuditEntry{
+			ID:        stored.ID,
+			EventID:   stored.EventID,
+			RecordID:  stored.RecordID,
+			Actor:     stored.Actor,
+			Action:    stored.Action,
+			CreatedAt: stored.CreatedAt,
+		}
+		if stored.Before != "" {
+			entry.Before = json.RawMessage(stored.Before)
+		}
+		if stored.After != "" {
+			entry.After = json.RawMessage(stored.After)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Collection names. events, records and users follow the layout cmd/migrate reads, so an
+// existing Firebase project can be used as is.
+const (
+	firestoreEvents      = "events"
+	firestoreRecords     = "records"    // Interactions, a subcollection of each event
+	firestoreOrganizers  = "organizers" // Subcollection of each event, keyed by user ID
+	firestoreUsers       = "users"
+	firestoreNicknames   = "nicknames" // Subcollection of each user, keyed by event ID
+	firestoreAuditLog    = "auditLog"
+	firestoreSessions    = "sessions"
+	firestoreSigningKeys = "signingKeys"
+	firestoreCounters    = "counters"
+)
+
+// FirestoreClient holds the Firestore connection
+type FirestoreClient struct {
+	Client *firestore.Client
+}
+
+// NewFirestoreClient creates a new Firestore client. When FIRESTORE_EMULATOR_HOST is set
+// the client connects to the emulator and no credentials are needed.
+func NewFirestoreClient(cfg *FirestoreConfig) (*FirestoreClient, error) {
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		projectID = firestore.DetectProjectID
+	}
+
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := firestore.NewClient(context.Background(), projectID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Firestore client: %w", err)
+	}
+
+	return &FirestoreClient{Client: client}, nil
+}
+
+// Close closes the Firestore connection
+func (c *FirestoreClient) Close() error {
+	if c.Client != nil {
+		return c.Client.Close()
+	}
+	return nil
+}
+
+// isNotFound reports whether err is Firestore's missing document error
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// noRows maps a missing document to sql.ErrNoRows, which callers already check for
+func noRows(err error) error {
+	if isNotFound(err) {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// ignoreNotFound drops a missing document error, for updates that match no row in SQL
+func ignoreNotFound(err error) error {
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// firestoreEvent is an events document. Revision is advanced by every interaction write,
+// like the revision column.
+type firestoreEvent struct {
+	models.Event
+	Revision int64 `firestore:"revision"`
+}
+
+// decodeEvent reads an events document. Documents written by the original Firebase
+// backend may lack eventId, so the document ID is used instead.
+func decodeEvent(doc *firestore.DocumentSnapshot) (*models.Event, error) {
+	var stored firestoreEvent
+	if err := doc.DataTo(&stored); err != nil {
+		return nil, err
+	}
+	if stored.EventID == "" {
+		stored.EventID = doc.Ref.ID
+	}
+	return &stored.Event, nil
+}
+
+// FirestoreEventRepository implements EventRepository using Firestore
+type FirestoreEventRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreEventRepository creates a new FirestoreEventRepository
+func NewFirestoreEventRepository(client *FirestoreClient) *FirestoreEventRepository {
+	return &FirestoreEventRepository{client: client}
+}
+
+func (r *FirestoreEventRepository) events() *firestore.CollectionRef {
+	return r.client.Client.Collection(firestoreEvents)
+}
+
+func (r *FirestoreEventRepository) organizers(eventID string) *firestore.CollectionRef {
+	return r.events().Doc(eventID).Collection(firestoreOrganizers)
+}
+
+func (r *FirestoreEventRepository) Create(ctx context.Context, event *models.Event) error {
+	stored := firestoreEvent{Event: *event}
+	stored.IsArchived = false
+	_, err := r.events().Doc(event.EventID).Create(ctx, stored)
+	return err
+}
+
+func (r *FirestoreEventRepository) GetByID(ctx context.Context, eventID string) (*models.Event, error) {
+	doc, err := r.events().Doc(eventID).Get(ctx)
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return decodeEvent(doc)
+}
+
+// Update leaves CreatedBy, CreatedAt and IsArchived unchanged, like the Postgres UPDATE
+func (r *FirestoreEventRepository) Update(ctx context.Context, event *models.Event) error {
+	_, err := r.events().Doc(event.EventID).Update(ctx, eventUpdates(event))
+	return ignoreNotFound(err)
+}
+
+// eventUpdates lists the event's editable fields
+func eventUpdates(event *models.Event) []firestore.Update {
+	return []firestore.Update{
+		{Path: "type", Value: event.Type},
+		{Path: "title", Value: event.Title},
+		{Path: "tag", Value: event.Tag},
+		{Path: "isActive", Value: event.IsActive},
+		{Path: "config", Value: event.Config},
+		{Path: "statusChangedAt", Value: event.StatusChangedAt},
+	}
+}
+
+func (r *FirestoreEventRepository) UpdateStatus(ctx context.Context, eventID string, isActive bool) error {
+	_, err := r.events().Doc(eventID).Update(ctx, []firestore.Update{
+		{Path: "isActive", Value: isActive},
+		{Path: "statusChangedAt", Value: time.Now()},
+	})
+	return ignoreNotFound(err)
+}
+
+func (r *FirestoreEventRepository) UpdateArchived(ctx context.Context, eventID string, isArchived bool) error {
+	_, err := r.events().Doc(eventID).Update(ctx, []firestore.Update{{Path: "isArchived", Value: isArchived}})
+	return ignoreNotFound(err)
+}
+
+// queryEvents runs q and decodes the results, skipping undecodable documents
+func queryEvents(ctx context.Context, q firestore.Query) ([]*models.Event, error) {
+	events := make([]*models.Event, 0)
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		event, err := decodeEvent(doc)
+		if err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *FirestoreEventRepository) List(ctx context.Context, limit int) ([]*models.Event, error) {
+	return queryEvents(ctx, r.events().OrderBy("createdAt", firestore.Desc).Limit(limit))
+}
+
+// GetByTag returns the most recently created event with the specified tag
+func (r *FirestoreEventRepository) GetByTag(ctx context.Context, tag string) (*models.Event, error) {
+	events, err := queryEvents(ctx, r.events().Where("tag", "==", tag).OrderBy("createdAt", firestore.Desc).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return events[0], nil
+}
+
+// ListScheduled runs one range query per time field, since Firestore cannot OR two
+// ranges, and merges the results newest first
+func (r *FirestoreEventRepository) ListScheduled(ctx context.Context, from, to time.Time) ([]*models.Event, error) {
+	seen := make(map[string]bool)
+	events := make([]*models.Event, 0)
+	for _, field := range []string{"config.startTime", "config.endTime"} {
+		found, err := queryEvents(ctx, r.events().Where(field, ">", from).Where(field, "<=", to))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range found {
+			if event.IsArchived || seen[event.EventID] {
+				continue
+			}
+			seen[event.EventID] = true
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].EventID < events[j].EventID
+	})
+	return events, nil
+}
+
+func (r *FirestoreEventRepository) IsOrganizer(ctx context.Context, eventID, userID string) (bool, error) {
+	_, err := r.organizers(eventID).Doc(userID).Get(ctx)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListOrganizers looks up display names in users, like the join in
+// PostgresEventRepository.ListOrganizers
+func (r *FirestoreEventRepository) ListOrganizers(ctx context.Context, eventID string) ([]*models.EventOrganizer, error) {
+	docs, err := r.organizers(eventID).OrderBy("addedAt", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	organizers := make([]*models.EventOrganizer, 0, len(docs))
+	userRefs := make([]*firestore.DocumentRef, 0, len(docs))
+	for _, doc := range docs {
+		var organizer models.EventOrganizer
+		if err := doc.DataTo(&organizer); err != nil {
+			return nil, err
+		}
+		organizers = append(organizers, &organizer)
+		userRefs = append(userRefs, r.client.Client.Collection(firestoreUsers).Doc(organizer.UserID))
+	}
+	if len(userRefs) == 0 {
+		return organizers, nil
+	}
+
+	users, err := r.client.Client.GetAll(ctx, userRefs)
+	if err != nil {
+		return nil, err
+	}
+	for i, doc := range users {
+		var user models.User
+		if doc.Exists() && doc.DataTo(&user) == nil {
+			organizers[i].UserDisplayName = user.DisplayName()
+		}
+	}
+	return organizers, nil
+}
+
+// AddOrganizer returns sql.ErrNoRows for an unknown event, where PostgreSQL reports a
+// foreign key violation
+func (r *FirestoreEventRepository) AddOrganizer(ctx context.Context, eventID, userID, addedBy string) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(r.events().Doc(eventID)); err != nil {
+			return noRows(err)
+		}
+		return r.addOrganizer(tx, eventID, userID, addedBy)
+	})
+}
+
+// addOrganizer creates the organizer document unless it exists. It reads, so it must come
+// before the transaction's writes.
+func (r *FirestoreEventRepository) addOrganizer(tx *firestore.Transaction, eventID, userID, addedBy string) error {
+	ref := r.organizers(eventID).Doc(userID)
+	if _, err := tx.Get(ref); err == nil || !isNotFound(err) {
+		return err
+	}
+	return tx.Create(ref, &models.EventOrganizer{
+		EventID: eventID,
+		UserID:  userID,
+		AddedBy: addedBy,
+		AddedAt: time.Now(),
+	})
+}
+
+func (r *FirestoreEventRepository) RemoveOrganizer(ctx context.Context, eventID, userID string) error {
+	_, err := r.organizers(eventID).Doc(userID).Delete(ctx, firestore.Exists)
+	if isNotFound(err) {
+		return ErrOrganizerNotFound
+	}
+	return err
+}
+
+func (r *FirestoreEventRepository) TransferOwnership(ctx context.Context, eventID, newOwner, transferredBy string) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		eventRef := r.events().Doc(eventID)
+		doc, err := tx.Get(eventRef)
+		if err != nil {
+			return noRows(err)
+		}
+		event, err := decodeEvent(doc)
+		if err != nil {
+			return err
+		}
+		previousOwner := event.CreatedBy
+		if previousOwner == newOwner {
+			return nil
+		}
+
+		// A transaction must finish reading before it writes
+		previousRef := r.organizers(eventID).Doc(previousOwner)
+		_, err = tx.Get(previousRef)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		previousIsOrganizer := err == nil
+
+		if err := tx.Update(eventRef, []firestore.Update{{Path: "createdBy", Value: newOwner}}); err != nil {
+			return err
+		}
+		if err := tx.Delete(r.organizers(eventID).Doc(newOwner)); err != nil {
+			return err
+		}
+		if previousIsOrganizer {
+			return nil
+		}
+		return tx.Create(previousRef, &models.EventOrganizer{
+			EventID: eventID,
+			UserID:  previousOwner,
+			AddedBy: transferredBy,
+			AddedAt: time.Now(),
+		})
+	})
+}
@@ -0,0 +1,532 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FirestoreInteractionRepository implements InteractionRepository using Firestore. Records
+// are stored in the event's records subcollection. Every write runs in a transaction that
+// first reads the event document, which serializes writes per event like the event row
+// lock in PostgresInteractionRepository and lets each write advance the event revision.
+// A transaction is limited to 500 writes, which bounds the LINEUP size ReorderLineUp and
+// ReconcileLineUp can handle.
+type FirestoreInteractionRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreInteractionRepository creates a new FirestoreInteractionRepository
+func NewFirestoreInteractionRepository(client *FirestoreClient) *FirestoreInteractionRepository {
+	return &FirestoreInteractionRepository{client: client}
+}
+
+func (r *FirestoreInteractionRepository) event(eventID string) *firestore.DocumentRef {
+	return r.client.Client.Collection(firestoreEvents).Doc(eventID)
+}
+
+func (r *FirestoreInteractionRepository) records(eventID string) *firestore.CollectionRef {
+	return r.event(eventID).Collection(firestoreRecords)
+}
+
+// decodeInteraction reads a records document
+func decodeInteraction(doc *firestore.DocumentSnapshot) (*models.Interaction, error) {
+	var interaction models.Interaction
+	if err := doc.DataTo(&interaction); err != nil {
+		return nil, err
+	}
+	interaction.ID = doc.Ref.ID
+	return &interaction, nil
+}
+
+// decodeInteractions reads records documents, skipping undecodable ones like scanInteractions
+func decodeInteractions(docs []*firestore.DocumentSnapshot) []*models.Interaction {
+	interactions := make([]*models.Interaction, 0, len(docs))
+	for _, doc := range docs {
+		interaction, err := decodeInteraction(doc)
+		if err != nil {
+			continue
+		}
+		interactions = append(interactions, interaction)
+	}
+	return interactions
+}
+
+// sortByTimestamp puts interactions in timestamp order, breaking ties by ID
+func sortByTimestamp(interactions []*models.Interaction) {
+	sort.SliceStable(interactions, func(i, j int) bool {
+		if !interactions[i].Timestamp.Equal(interactions[j].Timestamp) {
+			return interactions[i].Timestamp.Before(interactions[j].Timestamp)
+		}
+		return interactions[i].ID < interactions[j].ID
+	})
+}
+
+// firestoreWrite is one interaction write transaction. It hands out event revisions
+// like trg_interactions_revision does per written row.
+type firestoreWrite struct {
+	repo     *FirestoreInteractionRepository
+	tx       *firestore.Transaction
+	eventID  string
+	revision int64
+	written  bool
+
+	// config is the event config read by the transaction; a concurrent change to the
+	// event document makes the transaction retry, so it stays current until commit
+	config models.EventConfig
+
+	// eventUpdates are saved on the event document along with the revision
+	eventUpdates []firestore.Update
+}
+
+// put stores rec under its ID, stamped with the next revision
+func (w *firestoreWrite) put(rec *models.Interaction) error {
+	w.revision++
+	w.written = true
+	rec.Revision = w.revision
+	return w.tx.Set(w.repo.records(w.eventID).Doc(rec.ID), rec)
+}
+
+// write runs fn in a transaction after reading the event document, then saves the event
+// revision. Returns sql.ErrNoRows if the event does not exist. fn must do all its reads
+// before its first put.
+func (r *FirestoreInteractionRepository) write(ctx context.Context, eventID string, fn func(w *firestoreWrite) error) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(r.event(eventID))
+		if err != nil {
+			return noRows(err)
+		}
+		var event firestoreEvent
+		if err := doc.DataTo(&event); err != nil {
+			return err
+		}
+
+		w := &firestoreWrite{repo: r, tx: tx, eventID: eventID, revision: event.Revision, config: event.Config}
+		if err := fn(w); err != nil {
+			return err
+		}
+		updates := w.eventUpdates
+		if w.written {
+			updates = append(updates, firestore.Update{Path: "revision", Value: w.revision})
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Update(doc.Ref, updates)
+	})
+}
+
+func (r *FirestoreInteractionRepository) Create(ctx context.Context, eventID string, interaction *models.Interaction) (string, error) {
+	rec := *interaction
+	rec.ID = r.records(eventID).NewDoc().ID
+	err := r.write(ctx, eventID, func(w *firestoreWrite) error {
+		return w.put(&rec)
+	})
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+// CreateWithID upserts like ON CONFLICT (id): an existing record keeps its user and type,
+// and takes the new name, picture, status, timestamp and payload
+func (r *FirestoreInteractionRepository) CreateWithID(ctx context.Context, eventID, recordID string, interaction *models.Interaction) error {
+	return r.write(ctx, eventID, func(w *firestoreWrite) error {
+		rec := *interaction
+		rec.ID = recordID
+
+		doc, err := w.tx.Get(r.records(eventID).Doc(recordID))
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if err == nil {
+			existing, err := decodeInteraction(doc)
+			if err != nil {
+				return err
+			}
+			rec.UserID = existing.UserID
+			rec.Type = existing.Type
+		}
+		return w.put(&rec)
+	})
+}
+
+func (r *FirestoreInteractionRepository) GetByEventID(ctx context.Context, eventID string) ([]*models.Interaction, error) {
+	docs, err := r.records(eventID).OrderBy("timestamp", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	interactions := decodeInteractions(docs)
+	sortByTimestamp(interactions)
+	return interactions, nil
+}
+
+// GetByEventIDSince returns interactions created or modified after the given event revision
+func (r *FirestoreInteractionRepository) GetByEventIDSince(ctx context.Context, eventID string, since int64) ([]*models.Interaction, error) {
+	docs, err := r.records(eventID).Where("revision", ">", since).OrderBy("revision", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return decodeInteractions(docs), nil
+}
+
+func (r *FirestoreInteractionRepository) GetRevision(ctx context.Context, eventID string) (int64, error) {
+	doc, err := r.event(eventID).Get(ctx)
+	if err != nil {
+		return 0, noRows(err)
+	}
+	var event firestoreEvent
+	if err := doc.DataTo(&event); err != nil {
+		return 0, err
+	}
+	return event.Revision, nil
+}
+
+func (r *FirestoreInteractionRepository) GetByUserAndType(ctx context.Context, eventID, userID string, iType models.InteractionType) ([]*models.Interaction, error) {
+	q := r.records(eventID).Where("userId", "==", userID).Where("type", "==", iType).OrderBy("timestamp", firestore.Asc)
+	docs, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	interactions := decodeInteractions(docs)
+	sortByTimestamp(interactions)
+	return interactions, nil
+}
+
+func (r *FirestoreInteractionRepository) GetByID(ctx context.Context, eventID, recordID string) (*models.Interaction, error) {
+	doc, err := r.records(eventID).Doc(recordID).Get(ctx)
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return decodeInteraction(doc)
+}
+
+func (r *FirestoreInteractionRepository) Update(ctx context.Context, eventID, recordID string, updates map[string]interface{}) error {
+	return r.write(ctx, eventID, func(w *firestoreWrite) error {
+		doc, err := w.tx.Get(r.records(eventID).Doc(recordID))
+		if err != nil {
+			return noRows(err)
+		}
+		current, err := decodeInteraction(doc)
+		if err != nil {
+			return err
+		}
+		applyInteractionUpdates(current, updates)
+		return w.put(current)
+	})
+}
+
+// Delete does not advance the event revision, matching the INSERT OR UPDATE trigger
+func (r *FirestoreInteractionRepository) Delete(ctx context.Context, eventID, recordID string) error {
+	_, err := r.records(eventID).Doc(recordID).Delete(ctx)
+	return err
+}
+
+// lineUp returns all of the event's LINEUP records in queue order, read within w
+func (r *FirestoreInteractionRepository) lineUp(w *firestoreWrite) ([]*models.Interaction, error) {
+	q := r.records(w.eventID).Where("type", "==", models.InteractionTypeLineUp)
+	docs, err := w.tx.Documents(q).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	records := decodeInteractions(docs)
+	sortByTimestamp(records)
+	SortLineUpQueue(records)
+	return records, nil
+}
+
+// activeLineUp returns the non-cancelled LINEUP records in queue order, read within w
+func (r *FirestoreInteractionRepository) activeLineUp(w *firestoreWrite) ([]*models.Interaction, error) {
+	records, err := r.lineUp(w)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*models.Interaction, 0, len(records))
+	for _, rec := range records {
+		if rec.Status != "CANCELLED" {
+			active = append(active, rec)
+		}
+	}
+	return active, nil
+}
+
+// RegisterLineUp counts active records and inserts within one transaction
+func (r *FirestoreInteractionRepository) RegisterLineUp(ctx context.Context, eventID string, interaction *models.Interaction, exemptUserLimit bool) (string, error) {
+	rec := *interaction
+	rec.ID = r.records(eventID).NewDoc().ID
+	err := r.write(ctx, eventID, func(w *firestoreWrite) error {
+		active, err := r.activeLineUp(w)
+		if err != nil {
+			return err
+		}
+
+		limits := LineUpLimitsOf(w.config)
+		if exemptUserLimit {
+			limits.MaxCountPerUser = 0
+		}
+		var waitlistCount, userActive int
+		for _, other := range active {
+			if other.Status == "WAITLIST" {
+				waitlistCount++
+			}
+			if other.UserID == interaction.UserID {
+				userActive++
+			}
+		}
+
+		if limits.MaxCountPerUser > 0 && userActive >= limits.MaxCountPerUser {
+			return ErrRegistrationLimitReached
+		}
+
+		if len(active) >= limits.MaxParticipants {
+			if limits.WaitlistLimit > 0 && waitlistCount >= limits.WaitlistLimit {
+				return ErrWaitlistFull
+			}
+			rec.Status = "WAITLIST"
+		} else {
+			rec.Status = "SUCCESS"
+		}
+		return w.put(&rec)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	interaction.Status = rec.Status
+	return rec.ID, nil
+}
+
+// CancelLineUp cancels the user's latest active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seats, all within one transaction.
+func (r *FirestoreInteractionRepository) CancelLineUp(ctx context.Context, eventID, userID string) (*models.Interaction, []*models.Interaction, error) {
+	return r.cancelLineUp(ctx, eventID, userID, func(records []*models.Interaction) *models.Interaction {
+		// Latest by registration time, not queue position
+		var latest *models.Interaction
+		for _, rec := range records {
+			if rec.UserID == userID && rec.Status != "CANCELLED" && (latest == nil || !rec.Timestamp.Before(latest.Timestamp)) {
+				latest = rec
+			}
+		}
+		return latest
+	})
+}
+
+// CancelLineUpRecord cancels a specific active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seat, all within one transaction.
+func (r *FirestoreInteractionRepository) CancelLineUpRecord(ctx context.Context, eventID, recordID, cancelledBy string) (*models.Interaction, []*models.Interaction, error) {
+	return r.cancelLineUp(ctx, eventID, cancelledBy, func(records []*models.Interaction) *models.Interaction {
+		for _, rec := range records {
+			if rec.ID == recordID && rec.Status != "CANCELLED" {
+				return rec
+			}
+		}
+		return nil
+	})
+}
+
+// cancelLineUp cancels the registration picked from the event's LINEUP records and fills
+// freed seats from the waitlist
+func (r *FirestoreInteractionRepository) cancelLineUp(ctx context.Context, eventID, cancelledBy string, pick func([]*models.Interaction) *models.Interaction) (*models.Interaction, []*models.Interaction, error) {
+	var cancelled *models.Interaction
+	var promoted []*models.Interaction
+	err := r.write(ctx, eventID, func(w *firestoreWrite) error {
+		records, err := r.lineUp(w)
+		if err != nil {
+			return err
+		}
+		cancelled = pick(records)
+		if cancelled == nil {
+			return ErrNoActiveRegistration
+		}
+
+		now := time.Now()
+		cancelled.Status = "CANCELLED"
+		cancelled.CancelledAt = &now
+		cancelled.CancelledBy = cancelledBy
+		if err := w.put(cancelled); err != nil {
+			return err
+		}
+
+		success := 0
+		for _, rec := range records {
+			if rec.Status == "SUCCESS" {
+				success++
+			}
+		}
+		promoted = make([]*models.Interaction, 0)
+		for _, rec := range records {
+			if success >= w.config.MaxParticipants {
+				break
+			}
+			if rec.Status != "WAITLIST" {
+				continue
+			}
+			rec.Status = "SUCCESS"
+			rec.PromotedAt = &now
+			if err := w.put(rec); err != nil {
+				return err
+			}
+			success++
+			promoted = append(promoted, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return cancelled, promoted, nil
+}
+
+// ReconcileLineUp plans status changes for all active LINEUP registrations within one
+// transaction and applies them unless dryRun is set
+func (r *FirestoreInteractionRepository) ReconcileLineUp(ctx context.Context, eventID string, maxParticipants int, dryRun bool) ([]LineUpStatusChange, error) {
+	var changes []LineUpStatusChange
+	err := r.write(ctx, eventID, func(w *firestoreWrite) error {
+		active, err := r.activeLineUp(w)
+		if err != nil {
+			return err
+		}
+		changes = PlanLineUpStatuses(active, maxParticipants)
+		if dryRun {
+			return nil
+		}
+		return putAll(w, active, applyLineUpChanges(active, changes, time.Now()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// UpdateLineUpEvent saves the event and applies the status changes its new capacity
+// requires, all within one transaction
+func (r *FirestoreInteractionRepository) UpdateLineUpEvent(ctx context.Context, event *models.Event) ([]LineUpStatusChange, error) {
+	var changes []LineUpStatusChange
+	err := r.write(ctx, event.EventID, func(w *firestoreWrite) error {
+		active, err := r.activeLineUp(w)
+		if err != nil {
+			return err
+		}
+		changes = PlanLineUpStatuses(active, event.Config.MaxParticipants)
+		w.eventUpdates = eventUpdates(event)
+		return putAll(w, active, applyLineUpChanges(active, changes, time.Now()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ReorderLineUp moves the active LINEUP registrations into the given order by setting
+// their queuedAt, then re-applies capacity, all within one transaction
+func (r *FirestoreInteractionRepository) ReorderLineUp(ctx context.Context, eventID string, recordIDs []string) ([]LineUpStatusChange, error) {
+	var changes []LineUpStatusChange
+	err := r.write(ctx, eventID, func(w *firestoreWrite) error {
+		active, err := r.activeLineUp(w)
+		if err != nil {
+			return err
+		}
+		queued, err := PlanLineUpOrder(active, recordIDs)
+		if err != nil {
+			return err
+		}
+
+		changed := make(map[string]bool)
+		for _, rec := range active {
+			if at := queued[rec.ID]; !at.Equal(rec.QueueTime()) {
+				rec.QueuedAt = &at
+				changed[rec.ID] = true
+			}
+		}
+		changes = PlanLineUpStatuses(active, w.config.MaxParticipants)
+		for id := range applyLineUpChanges(active, changes, time.Now()) {
+			changed[id] = true
+		}
+		return putAll(w, active, changed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// applyLineUpChanges sets planned statuses on the records, stamping promotedAt on
+// promotions, and returns the IDs of the records it changed
+func applyLineUpChanges(records []*models.Interaction, changes []LineUpStatusChange, now time.Time) map[string]bool {
+	to := make(map[string]string, len(changes))
+	for _, change := range changes {
+		to[change.RecordID] = change.To
+	}
+
+	changed := make(map[string]bool, len(changes))
+	for _, rec := range records {
+		status, ok := to[rec.ID]
+		if !ok {
+			continue
+		}
+		rec.Status = status
+		if status == "SUCCESS" {
+			rec.PromotedAt = &now
+		}
+		changed[rec.ID] = true
+	}
+	return changed
+}
+
+// putAll writes the records whose IDs are in changed, once each
+func putAll(w *firestoreWrite, records []*models.Interaction, changed map[string]bool) error {
+	for _, rec := range records {
+		if !changed[rec.ID] {
+			continue
+		}
+		if err := w.put(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FirestoreInteractionRepository) TallyVotes(ctx context.Context, eventID string, withVoters bool) (*VoteSummary, error) {
+	docs, err := r.records(eventID).Where("type", "==", models.InteractionTypeVote).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	votes := decodeInteractions(docs)
+	sortByTimestamp(votes)
+
+	summary := &VoteSummary{Tallies: make([]VoteTally, 0)}
+	byOption := make(map[string]*VoteTally)
+	for _, rec := range votes {
+		if len(rec.SelectedOptions) == 0 {
+			continue
+		}
+		summary.TotalVoters++
+		for _, opt := range rec.SelectedOptions {
+			tally, ok := byOption[opt]
+			if !ok {
+				tally = &VoteTally{OptionID: opt}
+				byOption[opt] = tally
+			}
+			tally.Count++
+			if withVoters {
+				tally.Voters = append(tally.Voters, Voter{
+					UserID:          rec.UserID,
+					UserDisplayName: rec.UserDisplayName,
+					UserPictureUrl:  rec.UserPictureUrl,
+				})
+			}
+		}
+	}
+
+	for _, tally := range byOption {
+		summary.Tallies = append(summary.Tallies, *tally)
+	}
+	sort.Slice(summary.Tallies, func(i, j int) bool {
+		return summary.Tallies[i].OptionID < summary.Tallies[j].OptionID
+	})
+	return summary, nil
+}
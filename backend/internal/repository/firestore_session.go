@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FirestoreSessionRepository implements SessionRepository using Firestore, with sessions
+// keyed by ID
+type FirestoreSessionRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreSessionRepository creates a new FirestoreSessionRepository
+func NewFirestoreSessionRepository(client *FirestoreClient) *FirestoreSessionRepository {
+	return &FirestoreSessionRepository{client: client}
+}
+
+func (r *FirestoreSessionRepository) sessions() *firestore.CollectionRef {
+	return r.client.Client.Collection(firestoreSessions)
+}
+
+// decodeSession reads a sessions document
+func decodeSession(doc *firestore.DocumentSnapshot) (*models.Session, error) {
+	var session models.Session
+	if err := doc.DataTo(&session); err != nil {
+		return nil, err
+	}
+	session.ID = doc.Ref.ID
+	return &session, nil
+}
+
+// Create checks the refresh token hash is unused in the same transaction, standing in
+// for the UNIQUE constraint on refresh_token_hash
+func (r *FirestoreSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	stored := *session
+	stored.PreviousTokenHash = ""
+	stored.RevokedAt = nil
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		clash, err := tx.Documents(r.sessions().Where("refreshTokenHash", "==", session.RefreshTokenHash).Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(clash) > 0 {
+			return errors.New("refresh token hash already in use")
+		}
+		return tx.Create(r.sessions().Doc(session.ID), &stored)
+	})
+}
+
+func (r *FirestoreSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	// Firestore cannot OR two fields in one equality query, so try each hash in turn
+	for _, field := range []string{"refreshTokenHash", "previousTokenHash"} {
+		docs, err := r.sessions().Where(field, "==", tokenHash).Limit(1).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(docs) > 0 {
+			return decodeSession(docs[0])
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r *FirestoreSessionRepository) Rotate(ctx context.Context, sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(r.sessions().Doc(sessionID))
+		if isNotFound(err) {
+			return ErrSessionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		session, err := decodeSession(doc)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if session.RefreshTokenHash != oldHash || !session.Active(now) {
+			return ErrSessionNotFound
+		}
+		return tx.Update(doc.Ref, []firestore.Update{
+			{Path: "refreshTokenHash", Value: newHash},
+			{Path: "previousTokenHash", Value: oldHash},
+			{Path: "expiresAt", Value: expiresAt},
+			{Path: "lastUsedAt", Value: now},
+		})
+	})
+}
+
+func (r *FirestoreSessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(r.sessions().Doc(sessionID))
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return revokeSession(tx, doc, time.Now())
+	})
+}
+
+func (r *FirestoreSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(r.sessions().Where("userId", "==", userID)).GetAll()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, doc := range docs {
+			if err := revokeSession(tx, doc, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// revokeSession stamps revokedAt on a session that has not been revoked yet
+func revokeSession(tx *firestore.Transaction, doc *firestore.DocumentSnapshot, now time.Time) error {
+	session, err := decodeSession(doc)
+	if err != nil {
+		return fmt.Errorf("session %s: %w", doc.Ref.ID, err)
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return tx.Update(doc.Ref, []firestore.Update{{Path: "revokedAt", Value: now}})
+}
+
+func (r *FirestoreSessionRepository) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	doc, err := r.sessions().Doc(sessionID).Get(ctx)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	session, err := decodeSession(doc)
+	if err != nil {
+		return false, err
+	}
+	return session.Active(time.Now()), nil
+}
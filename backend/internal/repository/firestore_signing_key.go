@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FirestoreSigningKeyRepository implements SigningKeyRepository using Firestore, with
+// keys keyed by kid
+type FirestoreSigningKeyRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreSigningKeyRepository creates a new FirestoreSigningKeyRepository
+func NewFirestoreSigningKeyRepository(client *FirestoreClient) *FirestoreSigningKeyRepository {
+	return &FirestoreSigningKeyRepository{client: client}
+}
+
+func (r *FirestoreSigningKeyRepository) keys() *firestore.CollectionRef {
+	return r.client.Client.Collection(firestoreSigningKeys)
+}
+
+func (r *FirestoreSigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	_, err := r.keys().Doc(key.ID).Create(ctx, key)
+	return err
+}
+
+// ListUnexpired sorts in memory so the expiry filter needs no composite index
+func (r *FirestoreSigningKeyRepository) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	docs, err := r.keys().Where("expiresAt", ">", now).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.SigningKey, 0, len(docs))
+	for _, doc := range docs {
+		var key models.SigningKey
+		if err := doc.DataTo(&key); err != nil {
+			return nil, err
+		}
+		key.ID = doc.Ref.ID
+		keys = append(keys, &key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *FirestoreSigningKeyRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	docs, err := r.keys().Where("expiresAt", "<=", before).Select().Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
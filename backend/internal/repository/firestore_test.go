@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+)
+
+// newTestFirestoreClient connects to the emulator named by FIRESTORE_EMULATOR_HOST, using
+// a fresh project so every test starts empty. Tests are skipped when it is not set.
+func newTestFirestoreClient(t *testing.T) *FirestoreClient {
+	t.Helper()
+
+	host := os.Getenv("FIRESTORE_EMULATOR_HOST")
+	if host == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST not set")
+	}
+
+	projectID := strings.TrimSuffix(newContractPrefix(), "-")
+	client, err := NewFirestoreClient(&FirestoreConfig{ProjectID: projectID})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		// The emulator's REST API drops all of a project's documents
+		url := "http://" + host + "/emulator/v1/projects/" + projectID + "/databases/(default)/documents"
+		if req, err := http.NewRequest(http.MethodDelete, url, nil); err == nil {
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+	})
+
+	return client
+}
+
+func openFirestoreContract(t *testing.T) *contractEnv {
+	client := newTestFirestoreClient(t)
+	return &contractEnv{
+		Repositories: &Repositories{
+			Events:       NewFirestoreEventRepository(client),
+			Interactions: NewFirestoreInteractionRepository(client),
+			Users:        NewFirestoreUserRepository(client),
+			Audit:        NewFirestoreAuditRepository(client),
+			Sessions:     NewFirestoreSessionRepository(client),
+			SigningKeys:  NewFirestoreSigningKeyRepository(client),
+			Close:        func() error { return nil },
+		},
+		prefix: newContractPrefix(),
+		corruptPayload: func(t *testing.T, eventID, recordID string) {
+			ref := client.Client.Collection(firestoreEvents).Doc(eventID).Collection(firestoreRecords).Doc(recordID)
+			_, err := ref.Update(context.Background(), []firestore.Update{{Path: "selectedOptions", Value: "not a list"}})
+			if err != nil {
+				t.Fatalf("corrupt payload: %v", err)
+			}
+		},
+	}
+}
+
+func TestNewRepositoriesSelectsFirestore(t *testing.T) {
+	// The client dials lazily, so no emulator needs to be listening
+	t.Setenv("DB_TYPE", DBTypeFirestore)
+	t.Setenv("FIRESTORE_EMULATOR_HOST", "localhost:1")
+	t.Setenv("FIRESTORE_PROJECT_ID", "event-manager-test")
+
+	repos, err := NewRepositories(LoadConfigFromEnv())
+	if err != nil {
+		t.Fatalf("NewRepositories: %v", err)
+	}
+	if _, ok := repos.Events.(*FirestoreEventRepository); !ok {
+		t.Fatalf("Events is %T, want *FirestoreEventRepository", repos.Events)
+	}
+	if err := repos.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
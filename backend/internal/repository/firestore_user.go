@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"event-manager/internal/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FirestoreUserRepository implements UserRepository using Firestore. Users are keyed by
+// LINE user ID and each user's event nicknames are a subcollection keyed by event ID.
+type FirestoreUserRepository struct {
+	client *FirestoreClient
+}
+
+// NewFirestoreUserRepository creates a new FirestoreUserRepository
+func NewFirestoreUserRepository(client *FirestoreClient) *FirestoreUserRepository {
+	return &FirestoreUserRepository{client: client}
+}
+
+func (r *FirestoreUserRepository) users() *firestore.CollectionRef {
+	return r.client.Client.Collection(firestoreUsers)
+}
+
+func (r *FirestoreUserRepository) nicknames(userID string) *firestore.CollectionRef {
+	return r.users().Doc(userID).Collection(firestoreNicknames)
+}
+
+// decodeUser reads a users document. Documents written by the original Firebase backend
+// may lack lineUserId, so the document ID is used instead.
+func decodeUser(doc *firestore.DocumentSnapshot) (*models.User, error) {
+	var user models.User
+	if err := doc.DataTo(&user); err != nil {
+		return nil, err
+	}
+	if user.LineUserID == "" {
+		user.LineUserID = doc.Ref.ID
+	}
+	return &user, nil
+}
+
+func (r *FirestoreUserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := r.users().Doc(user.LineUserID).Create(ctx, user)
+	return err
+}
+
+func (r *FirestoreUserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	doc, err := r.users().Doc(userID).Get(ctx)
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return decodeUser(doc)
+}
+
+// Update leaves CreatedAt unchanged, like the Postgres UPDATE
+func (r *FirestoreUserRepository) Update(ctx context.Context, user *models.User) error {
+	_, err := r.users().Doc(user.LineUserID).Update(ctx, []firestore.Update{
+		{Path: "lineDisplayName", Value: user.LineDisplayName},
+		{Path: "pictureUrl", Value: user.PictureURL},
+		{Path: "customName", Value: user.CustomName},
+		{Path: "role", Value: user.Role},
+	})
+	return ignoreNotFound(err)
+}
+
+// UpdateFields writes only the fields UserRepository accepts; other keys are ignored.
+// Returns sql.ErrNoRows for an unknown user.
+func (r *FirestoreUserRepository) UpdateFields(ctx context.Context, userID string, updates map[string]interface{}) error {
+	fields := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		switch key {
+		case "lineDisplayName", "pictureUrl", "customName", "role":
+			fields = append(fields, firestore.Update{Path: key, Value: value.(string)})
+		}
+	}
+	if len(fields) == 0 {
+		_, err := r.GetByID(ctx, userID)
+		return err
+	}
+
+	_, err := r.users().Doc(userID).Update(ctx, fields)
+	return noRows(err)
+}
+
+func (r *FirestoreUserRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	_, err := r.users().Doc(userID).Get(ctx)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListByRole sorts in memory so the role filter needs no composite index
+func (r *FirestoreUserRepository) ListByRole(ctx context.Context, role string) ([]*models.User, error) {
+	docs, err := r.users().Where("role", "==", role).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.User, 0, len(docs))
+	for _, doc := range docs {
+		user, err := decodeUser(doc)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].LineUserID < users[j].LineUserID
+	})
+	return users, nil
+}
+
+func (r *FirestoreUserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	// Select with no fields fetches only document names
+	docs, err := r.users().Where("role", "==", role).Select().Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// RevokeAdmin reads every admin in a transaction, so a concurrent revocation that changes
+// any of them makes one of the two retry
+func (r *FirestoreUserRepository) RevokeAdmin(ctx context.Context, userID string) error {
+	return r.client.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		admins, err := tx.Documents(r.users().Where("role", "==", "admin")).GetAll()
+		if err != nil {
+			return err
+		}
+
+		var target *firestore.DocumentRef
+		for _, doc := range admins {
+			if doc.Ref.ID == userID {
+				target = doc.Ref
+			}
+		}
+		if target == nil {
+			return ErrNotAdmin
+		}
+		if len(admins) <= 1 {
+			return ErrLastAdmin
+		}
+
+		return tx.Update(target, []firestore.Update{{Path: "role", Value: "user"}})
+	})
+}
+
+func (r *FirestoreUserRepository) GetEventNickname(ctx context.Context, eventID, userID string) (string, error) {
+	doc, err := r.nicknames(userID).Doc(eventID).Get(ctx)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var n models.EventNickname
+	if err := doc.DataTo(&n); err != nil {
+		return "", err
+	}
+	return n.Nickname, nil
+}
+
+func (r *FirestoreUserRepository) SetEventNickname(ctx context.Context, eventID, userID, nickname string) error {
+	ref := r.nicknames(userID).Doc(eventID)
+	if nickname == "" {
+		_, err := ref.Delete(ctx)
+		return err
+	}
+
+	_, err := ref.Set(ctx, &models.EventNickname{
+		EventID:   eventID,
+		UserID:    userID,
+		Nickname:  nickname,
+		UpdatedAt: time.Now(),
+	})
+	return err
+}
+
+func (r *FirestoreUserRepository) ListEventNicknames(ctx context.Context, userID string) ([]*models.EventNickname, error) {
+	docs, err := r.nicknames(userID).OrderBy("updatedAt", firestore.Desc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	nicknames := make([]*models.EventNickname, 0, len(docs))
+	for _, doc := range docs {
+		var n models.EventNickname
+		if err := doc.DataTo(&n); err != nil {
+			return nil, err
+		}
+		nicknames = append(nicknames, &n)
+	}
+	return nicknames, nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"event-manager/internal/models"
 )
@@ -15,6 +17,27 @@ type EventRepository interface {
 	UpdateStatus(ctx context.Context, eventID string, isActive bool) error
 	UpdateArchived(ctx context.Context, eventID string, isArchived bool) error
 	List(ctx context.Context, limit int) ([]*models.Event, error)
+
+	// ListScheduled returns non-archived events whose Config.StartTime or Config.EndTime
+	// falls within (from, to]
+	ListScheduled(ctx context.Context, from, to time.Time) ([]*models.Event, error)
+
+	// IsOrganizer reports whether the user has been granted co-organizer rights on the event
+	IsOrganizer(ctx context.Context, eventID, userID string) (bool, error)
+
+	// ListOrganizers returns the event's co-organizers in the order they were added
+	ListOrganizers(ctx context.Context, eventID string) ([]*models.EventOrganizer, error)
+
+	// AddOrganizer grants the user co-organizer rights; adding an existing organizer is a no-op
+	AddOrganizer(ctx context.Context, eventID, userID, addedBy string) error
+
+	// RemoveOrganizer revokes co-organizer rights. Returns ErrOrganizerNotFound if the
+	// user is not an organizer.
+	RemoveOrganizer(ctx context.Context, eventID, userID string) error
+
+	// TransferOwnership makes newOwner the event's CreatedBy in one transaction. The previous
+	// owner stays on as a co-organizer and newOwner is removed from the organizer list.
+	TransferOwnership(ctx context.Context, eventID, newOwner, transferredBy string) error
 }
 
 // InteractionRepository defines the interface for interaction data operations
@@ -28,6 +51,14 @@ type InteractionRepository interface {
 	// GetByEventID returns all interactions for an event
 	GetByEventID(ctx context.Context, eventID string) ([]*models.Interaction, error)
 
+	// GetByEventIDSince returns interactions created or modified after revision since,
+	// ordered by revision
+	GetByEventIDSince(ctx context.Context, eventID string, since int64) ([]*models.Interaction, error)
+
+	// GetRevision returns the event's current revision. Every interaction write advances
+	// it and stamps the written record with the new value.
+	GetRevision(ctx context.Context, eventID string) (int64, error)
+
 	// GetByUserAndType returns interactions filtered by user and type
 	GetByUserAndType(ctx context.Context, eventID, userID string, iType models.InteractionType) ([]*models.Interaction, error)
 
@@ -39,6 +70,59 @@ type InteractionRepository interface {
 
 	// Delete removes an interaction
 	Delete(ctx context.Context, eventID, recordID string) error
+
+	// The LINEUP writes below read the event's capacity rules (see LineUpLimitsOf) under
+	// the event lock, so they never act on a config that a concurrent UpdateLineUpEvent
+	// has replaced. They return sql.ErrNoRows if the event does not exist.
+
+	// RegisterLineUp atomically checks LINEUP capacity and inserts the registration,
+	// setting interaction.Status to SUCCESS or WAITLIST. With exemptUserLimit the event's
+	// MaxCountPerUser is not enforced. Returns the generated ID.
+	RegisterLineUp(ctx context.Context, eventID string, interaction *models.Interaction, exemptUserLimit bool) (string, error)
+
+	// CancelLineUp atomically cancels the user's latest active LINEUP registration and
+	// promotes the oldest WAITLIST registrations into freed seats. Returns the cancelled
+	// record and the promoted records in queue order.
+	CancelLineUp(ctx context.Context, eventID, userID string) (*models.Interaction, []*models.Interaction, error)
+
+	// CancelLineUpRecord is CancelLineUp for a specific active registration, recording who
+	// cancelled it. Returns ErrNoActiveRegistration if the record is not an active LINEUP.
+	CancelLineUpRecord(ctx context.Context, eventID, recordID, cancelledBy string) (*models.Interaction, []*models.Interaction, error)
+
+	// ReorderLineUp atomically puts the active LINEUP registrations in the given order by
+	// setting their QueuedAt (see PlanLineUpOrder) and re-applies capacity so the first
+	// MaxParticipants are SUCCESS. Timestamps are left alone.
+	ReorderLineUp(ctx context.Context, eventID string, recordIDs []string) ([]LineUpStatusChange, error)
+
+	// ReconcileLineUp recomputes active LINEUP statuses in queue order so the first
+	// maxParticipants are SUCCESS and the rest WAITLIST. With dryRun nothing is written.
+	ReconcileLineUp(ctx context.Context, eventID string, maxParticipants int, dryRun bool) ([]LineUpStatusChange, error)
+
+	// UpdateLineUpEvent atomically saves the event like EventRepository.Update and reconciles
+	// its LINEUP statuses against the new event.Config.MaxParticipants, holding the same lock
+	// as RegisterLineUp. Returns sql.ErrNoRows if the event does not exist.
+	UpdateLineUpEvent(ctx context.Context, event *models.Event) ([]LineUpStatusChange, error)
+
+	// TallyVotes aggregates VOTE selections per stored option value. Voter lists are
+	// only loaded when withVoters is set.
+	TallyVotes(ctx context.Context, eventID string, withVoters bool) (*VoteSummary, error)
+}
+
+// LineUpLimits holds the capacity rules applied by RegisterLineUp
+// (WaitlistLimit and MaxCountPerUser: 0 = unlimited)
+type LineUpLimits struct {
+	MaxParticipants int
+	WaitlistLimit   int
+	MaxCountPerUser int
+}
+
+// LineUpLimitsOf returns the capacity rules set in an event's config
+func LineUpLimitsOf(config models.EventConfig) LineUpLimits {
+	return LineUpLimits{
+		MaxParticipants: config.MaxParticipants,
+		WaitlistLimit:   config.WaitlistLimit,
+		MaxCountPerUser: config.MaxCountPerUser,
+	}
 }
 
 // UserRepository defines the interface for user data operations
@@ -48,6 +132,171 @@ type UserRepository interface {
 	Update(ctx context.Context, user *models.User) error
 	UpdateFields(ctx context.Context, userID string, updates map[string]interface{}) error
 	Exists(ctx context.Context, userID string) (bool, error)
+
+	// ListByRole returns users with the role, oldest first
+	ListByRole(ctx context.Context, role string) ([]*models.User, error)
+
+	// CountByRole returns the number of users with the role
+	CountByRole(ctx context.Context, role string) (int, error)
+
+	// RevokeAdmin atomically demotes an admin to "user". Returns ErrNotAdmin if the user
+	// is not an admin and ErrLastAdmin if they are the only one.
+	RevokeAdmin(ctx context.Context, userID string) error
+
+	// GetEventNickname returns the user's nickname for the event, or "" if none is set
+	GetEventNickname(ctx context.Context, eventID, userID string) (string, error)
+
+	// SetEventNickname sets the user's nickname for the event; an empty nickname removes it
+	SetEventNickname(ctx context.Context, eventID, userID, nickname string) error
+
+	// ListEventNicknames returns the user's nicknames, most recently updated first
+	ListEventNicknames(ctx context.Context, userID string) ([]*models.EventNickname, error)
+}
+
+// AuditRepository defines the interface for the append-only audit log
+type AuditRepository interface {
+	// Create appends an entry, setting its ID and CreatedAt
+	Create(ctx context.Context, entry *models.AuditEntry) error
+
+	// ListByEvent returns the event's most recent entries first, up to limit
+	ListByEvent(ctx context.Context, eventID string, limit int) ([]*models.AuditEntry, error)
+}
+
+// SessionRepository defines the interface for login sessions
+type SessionRepository interface {
+	// Create stores a new session
+	Create(ctx context.Context, session *models.Session) error
+
+	// GetByTokenHash returns the session whose current or previous refresh token hash
+	// matches, revoked or not. Returns ErrSessionNotFound if none does.
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error)
+
+	// Rotate replaces the session's refresh token hash if it is still oldHash and the
+	// session is active, keeping oldHash as the previous hash. Returns ErrSessionNotFound
+	// if the session was revoked, expired or rotated concurrently.
+	Rotate(ctx context.Context, sessionID, oldHash, newHash string, expiresAt time.Time) error
+
+	// Revoke ends a session; revoking an ended session is a no-op
+	Revoke(ctx context.Context, sessionID string) error
+
+	// RevokeAllForUser ends every active session of the user
+	RevokeAllForUser(ctx context.Context, userID string) error
+
+	// IsActive reports whether the session exists, is not revoked and has not expired
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// SigningKeyRepository defines the interface for access token signing keys
+type SigningKeyRepository interface {
+	// Create stores a new key
+	Create(ctx context.Context, key *models.SigningKey) error
+
+	// ListUnexpired returns keys whose ExpiresAt is after now, newest first
+	ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error)
+
+	// DeleteExpired removes keys that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// LineUpStatusChange describes a LINEUP registration whose status is changed by ReconcileLineUp
+type LineUpStatusChange struct {
+	RecordID        string    `json:"recordId"`
+	UserID          string    `json:"userId"`
+	UserDisplayName string    `json:"userDisplayName"`
+	Timestamp       time.Time `json:"timestamp"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+}
+
+// VoteSummary is the result of TallyVotes
+type VoteSummary struct {
+	TotalVoters int
+	Tallies     []VoteTally // Ordered by option value
+}
+
+// VoteTally counts the votes for one stored option value
+type VoteTally struct {
+	OptionID string
+	Count    int
+	Voters   []Voter // In vote order; nil unless requested
+}
+
+// Voter identifies a user who selected an option
+type Voter struct {
+	UserID          string `json:"userId"`
+	UserDisplayName string `json:"userDisplayName"`
+	UserPictureUrl  string `json:"userPictureUrl,omitempty"`
+}
+
+// SortLineUpQueue puts LINEUP registrations in queue order (see models.Interaction.QueueTime).
+// The sort is stable, so registrations with the same queue time keep their order.
+func SortLineUpQueue(records []*models.Interaction) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].QueueTime().Before(records[j].QueueTime())
+	})
+}
+
+// PlanLineUpStatuses returns the status changes needed so that, in queue order, the first
+// maxParticipants active registrations are SUCCESS and the rest WAITLIST. Registrations
+// beyond the waitlist limit are kept on the waitlist rather than cancelled.
+func PlanLineUpStatuses(active []*models.Interaction, maxParticipants int) []LineUpStatusChange {
+	sorted := make([]*models.Interaction, len(active))
+	copy(sorted, active)
+	SortLineUpQueue(sorted)
+
+	changes := make([]LineUpStatusChange, 0)
+	for i, rec := range sorted {
+		want := "WAITLIST"
+		if i < maxParticipants {
+			want = "SUCCESS"
+		}
+		if rec.Status != want {
+			changes = append(changes, LineUpStatusChange{
+				RecordID:        rec.ID,
+				UserID:          rec.UserID,
+				UserDisplayName: rec.UserDisplayName,
+				Timestamp:       rec.Timestamp,
+				From:            rec.Status,
+				To:              want,
+			})
+		}
+	}
+	return changes
+}
+
+// PlanLineUpOrder returns the queue times (QueuedAt) that put the active registrations in
+// the order of recordIDs, reusing their current queue times so the queue keeps its time
+// span and later registrations still join at the end. recordIDs must list every active
+// registration exactly once.
+func PlanLineUpOrder(active []*models.Interaction, recordIDs []string) (map[string]time.Time, error) {
+	if len(recordIDs) != len(active) {
+		return nil, ErrRosterMismatch
+	}
+
+	known := make(map[string]bool, len(active))
+	slots := make([]time.Time, 0, len(active))
+	for _, rec := range active {
+		known[rec.ID] = true
+		slots = append(slots, rec.QueueTime())
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+
+	queued := make(map[string]time.Time, len(recordIDs))
+	for i, id := range recordIDs {
+		if !known[id] {
+			return nil, ErrRosterMismatch
+		}
+		if _, dup := queued[id]; dup {
+			return nil, ErrRosterMismatch
+		}
+		// Keep the order strict even when registrations share a queue time
+		if i > 0 && !slots[i].After(slots[i-1]) {
+			slots[i] = slots[i-1].Add(time.Microsecond)
+		}
+		queued[id] = slots[i]
+	}
+
+	return queued, nil
 }
 
 // Repositories holds all repository instances
@@ -55,5 +304,8 @@ type Repositories struct {
 	Events       EventRepository
 	Interactions InteractionRepository
 	Users        UserRepository
+	Audit        AuditRepository
+	Sessions     SessionRepository
+	SigningKeys  SigningKeyRepository
 	Close        func() error
 }
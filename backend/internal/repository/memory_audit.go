@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// MemoryAuditRepository implements AuditRepository in memory
+type MemoryAuditRepository struct {
+	mu      sync.Mutex
+	entries []*models.AuditEntry // In insertion order
+}
+
+// NewMemoryAuditRepository creates a new MemoryAuditRepository
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = int64(len(r.entries) + 1)
+	entry.CreatedAt = time.Now()
+	copied := *entry
+	copied.Before = append([]byte(nil), entry.Before...)
+	copied.After = append([]byte(nil), entry.After...)
+	r.entries = append(r.entries, &copied)
+	return nil
+}
+
+func (r *MemoryAuditRepository) ListByEvent(ctx context.Context, eventID string, limit int) ([]*models.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]*models.AuditEntry, 0)
+	for i := len(r.entries) - 1; i >= 0 && len(entries) < limit; i-- {
+		if r.entries[i].EventID == eventID {
+			copied := *r.entries[i]
+			entries = append(entries, &copied)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// memoryEvent is an events row; Config is kept as JSON like the config column so
+// callers never share slices with the store
+type memoryEvent struct {
+	event    models.Event
+	config   []byte
+	revision int64
+}
+
+func (row *memoryEvent) toModel() (*models.Event, error) {
+	event := row.event
+	if err := json.Unmarshal(row.config, &event.Config); err != nil {
+		return nil, err
+	}
+	event.StatusChangedAt = copyTime(row.event.StatusChangedAt)
+	return &event, nil
+}
+
+// copyTime returns a copy of t so the store and its callers never share it
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// MemoryEventRepository implements EventRepository in memory. Writes that reference an
+// unknown event fail with sql.ErrNoRows where PostgreSQL reports a foreign key violation.
+type MemoryEventRepository struct {
+	users *MemoryUserRepository // For organizer display names; may be nil
+
+	mu         sync.Mutex
+	events     map[string]*memoryEvent
+	organizers map[string][]*models.EventOrganizer
+}
+
+// NewMemoryEventRepository creates a new MemoryEventRepository. users, if set, supplies
+// organizer display names like the join in PostgresEventRepository.ListOrganizers.
+func NewMemoryEventRepository(users *MemoryUserRepository) *MemoryEventRepository {
+	return &MemoryEventRepository{
+		users:      users,
+		events:     make(map[string]*memoryEvent),
+		organizers: make(map[string][]*models.EventOrganizer),
+	}
+}
+
+func (r *MemoryEventRepository) Create(ctx context.Context, event *models.Event) error {
+	configJSON, err := json.Marshal(event.Config)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.events[event.EventID]; ok {
+		return fmt.Errorf("event %s already exists", event.EventID)
+	}
+	row := &memoryEvent{event: *event, config: configJSON}
+	row.event.Config = models.EventConfig{}
+	row.event.IsArchived = false
+	row.event.StatusChangedAt = nil
+	r.events[event.EventID] = row
+	return nil
+}
+
+func (r *MemoryEventRepository) GetByID(ctx context.Context, eventID string) (*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.events[eventID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return row.toModel()
+}
+
+// Update leaves CreatedBy, CreatedAt and IsArchived unchanged, like the Postgres UPDATE
+func (r *MemoryEventRepository) Update(ctx context.Context, event *models.Event) error {
+	configJSON, err := json.Marshal(event.Config)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.events[event.EventID]
+	if !ok {
+		return nil
+	}
+	row.event.Type = event.Type
+	row.event.Title = event.Title
+	row.event.Tag = event.Tag
+	row.event.IsActive = event.IsActive
+	row.event.StatusChangedAt = copyTime(event.StatusChangedAt)
+	row.config = configJSON
+	return nil
+}
+
+func (r *MemoryEventRepository) UpdateStatus(ctx context.Context, eventID string, isActive bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if row, ok := r.events[eventID]; ok {
+		now := time.Now()
+		row.event.IsActive = isActive
+		row.event.StatusChangedAt = &now
+	}
+	return nil
+}
+
+func (r *MemoryEventRepository) UpdateArchived(ctx context.Context, eventID string, isArchived bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if row, ok := r.events[eventID]; ok {
+		row.event.IsArchived = isArchived
+	}
+	return nil
+}
+
+// listLocked returns the events matching keep, newest first; r.mu must be held
+func (r *MemoryEventRepository) listLocked(keep func(*models.Event) bool) []*models.Event {
+	events := make([]*models.Event, 0)
+	for _, row := range r.events {
+		event, err := row.toModel()
+		if err != nil || !keep(event) {
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].EventID < events[j].EventID
+	})
+	return events
+}
+
+func (r *MemoryEventRepository) List(ctx context.Context, limit int) ([]*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.listLocked(func(*models.Event) bool { return true })
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// GetByTag returns the most recently created event with the specified tag
+func (r *MemoryEventRepository) GetByTag(ctx context.Context, tag string) (*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.listLocked(func(e *models.Event) bool { return e.Tag == tag })
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return events[0], nil
+}
+
+func (r *MemoryEventRepository) ListScheduled(ctx context.Context, from, to time.Time) ([]*models.Event, error) {
+	in := func(t time.Time) bool {
+		return !t.IsZero() && t.After(from) && !t.After(to)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.listLocked(func(e *models.Event) bool {
+		return !e.IsArchived && (in(e.Config.StartTime) || in(e.Config.EndTime))
+	}), nil
+}
+
+func (r *MemoryEventRepository) IsOrganizer(ctx context.Context, eventID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.organizerIndex(eventID, userID) >= 0, nil
+}
+
+func (r *MemoryEventRepository) organizerIndex(eventID, userID string) int {
+	for i, o := range r.organizers[eventID] {
+		if o.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryEventRepository) ListOrganizers(ctx context.Context, eventID string) ([]*models.EventOrganizer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	organizers := make([]*models.EventOrganizer, 0, len(r.organizers[eventID]))
+	for _, o := range r.organizers[eventID] {
+		organizer := *o
+		if r.users != nil {
+			organizer.UserDisplayName = r.users.displayName(o.UserID)
+		}
+		organizers = append(organizers, &organizer)
+	}
+	return organizers, nil
+}
+
+func (r *MemoryEventRepository) AddOrganizer(ctx context.Context, eventID, userID, addedBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.events[eventID]; !ok {
+		return sql.ErrNoRows
+	}
+	r.addOrganizerLocked(eventID, userID, addedBy)
+	return nil
+}
+
+func (r *MemoryEventRepository) addOrganizerLocked(eventID, userID, addedBy string) {
+	if r.organizerIndex(eventID, userID) >= 0 {
+		return
+	}
+	r.organizers[eventID] = append(r.organizers[eventID], &models.EventOrganizer{
+		EventID: eventID,
+		UserID:  userID,
+		AddedBy: addedBy,
+		AddedAt: time.Now(),
+	})
+}
+
+func (r *MemoryEventRepository) removeOrganizerLocked(eventID, userID string) bool {
+	i := r.organizerIndex(eventID, userID)
+	if i < 0 {
+		return false
+	}
+	list := r.organizers[eventID]
+	r.organizers[eventID] = append(list[:i:i], list[i+1:]...)
+	return true
+}
+
+func (r *MemoryEventRepository) RemoveOrganizer(ctx context.Context, eventID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.removeOrganizerLocked(eventID, userID) {
+		return ErrOrganizerNotFound
+	}
+	return nil
+}
+
+func (r *MemoryEventRepository) TransferOwnership(ctx context.Context, eventID, newOwner, transferredBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.events[eventID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	previousOwner := row.event.CreatedBy
+	if previousOwner == newOwner {
+		return nil
+	}
+	row.event.CreatedBy = newOwner
+	r.removeOrganizerLocked(eventID, newOwner)
+	r.addOrganizerLocked(eventID, previousOwner, transferredBy)
+	return nil
+}
+
+// bumpRevision advances the event's revision like trg_interactions_revision and returns
+// the new value
+func (r *MemoryEventRepository) bumpRevision(eventID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.events[eventID]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	row.revision++
+	return row.revision, nil
+}
+
+func (r *MemoryEventRepository) revision(eventID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.events[eventID]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return row.revision, nil
+}
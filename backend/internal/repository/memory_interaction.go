@@ -0,0 +1,575 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// memoryInteraction is an interactions row; the payload fields are kept as JSON like the
+// payload column, so records read back exactly as they would from PostgreSQL
+type memoryInteraction struct {
+	id              string
+	eventID         string
+	userID          string
+	iType           models.InteractionType
+	userDisplayName string
+	userPictureUrl  string
+	status          string
+	timestamp       time.Time
+	payload         []byte
+	revision        int64
+	seq             int64 // Insertion order, breaks timestamp ties
+}
+
+func (row *memoryInteraction) toModel() (*models.Interaction, error) {
+	interaction := &models.Interaction{
+		ID:              row.id,
+		UserID:          row.userID,
+		Type:            row.iType,
+		UserDisplayName: row.userDisplayName,
+		UserPictureUrl:  row.userPictureUrl,
+		Status:          row.status,
+		Timestamp:       row.timestamp,
+		Revision:        row.revision,
+	}
+	var payload interactionPayload
+	if err := json.Unmarshal(row.payload, &payload); err != nil {
+		return nil, err
+	}
+	payload.applyTo(interaction)
+	return interaction, nil
+}
+
+// setPayload stores the payload fields of interaction
+func (row *memoryInteraction) setPayload(interaction *models.Interaction) error {
+	payloadJSON, err := json.Marshal(newInteractionPayload(interaction))
+	if err != nil {
+		return err
+	}
+	row.payload = payloadJSON
+	return nil
+}
+
+// MemoryInteractionRepository implements InteractionRepository in memory. One mutex
+// serializes all writes, which gives the LINEUP operations the same atomicity as the
+// event row lock in PostgresInteractionRepository.
+type MemoryInteractionRepository struct {
+	events *MemoryEventRepository
+
+	mu      sync.Mutex
+	records map[string]*memoryInteraction // By record ID
+	nextSeq int64
+}
+
+// NewMemoryInteractionRepository creates a new MemoryInteractionRepository. Records can
+// only be written for events that exist in events, which also holds the event revisions.
+func NewMemoryInteractionRepository(events *MemoryEventRepository) *MemoryInteractionRepository {
+	return &MemoryInteractionRepository{
+		events:  events,
+		records: make(map[string]*memoryInteraction),
+	}
+}
+
+// touch advances the event revision and stamps row with it; r.mu must be held
+func (r *MemoryInteractionRepository) touch(row *memoryInteraction) error {
+	revision, err := r.events.bumpRevision(row.eventID)
+	if err != nil {
+		return err
+	}
+	row.revision = revision
+	return nil
+}
+
+// insertLocked stores a new record under id (generated if empty); r.mu must be held
+func (r *MemoryInteractionRepository) insertLocked(eventID, id string, interaction *models.Interaction) (*memoryInteraction, error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+	r.nextSeq++
+	row := &memoryInteraction{
+		id:              id,
+		eventID:         eventID,
+		userID:          interaction.UserID,
+		iType:           interaction.Type,
+		userDisplayName: interaction.UserDisplayName,
+		userPictureUrl:  interaction.UserPictureUrl,
+		status:          interaction.Status,
+		timestamp:       interaction.Timestamp,
+		seq:             r.nextSeq,
+	}
+	if err := row.setPayload(interaction); err != nil {
+		return nil, err
+	}
+	if err := r.touch(row); err != nil {
+		return nil, err
+	}
+	r.records[id] = row
+	return row, nil
+}
+
+// selectLocked returns the event's rows matching keep in timestamp order; r.mu must be held
+func (r *MemoryInteractionRepository) selectLocked(eventID string, keep func(*memoryInteraction) bool) []*memoryInteraction {
+	rows := make([]*memoryInteraction, 0)
+	for _, row := range r.records {
+		if row.eventID == eventID && keep(row) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].timestamp.Equal(rows[j].timestamp) {
+			return rows[i].timestamp.Before(rows[j].timestamp)
+		}
+		return rows[i].seq < rows[j].seq
+	})
+	return rows
+}
+
+// toModels converts rows, skipping any whose payload cannot be decoded like scanInteractions
+func toModels(rows []*memoryInteraction) []*models.Interaction {
+	interactions := make([]*models.Interaction, 0, len(rows))
+	for _, row := range rows {
+		interaction, err := row.toModel()
+		if err != nil {
+			continue
+		}
+		interactions = append(interactions, interaction)
+	}
+	return interactions
+}
+
+func (r *MemoryInteractionRepository) Create(ctx context.Context, eventID string, interaction *models.Interaction) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, err := r.insertLocked(eventID, "", interaction)
+	if err != nil {
+		return "", err
+	}
+	return row.id, nil
+}
+
+// CreateWithID upserts like ON CONFLICT (id): an existing record keeps its event, user and
+// type, and takes the new name, picture, status, timestamp and payload
+func (r *MemoryInteractionRepository) CreateWithID(ctx context.Context, eventID, recordID string, interaction *models.Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.records[recordID]
+	if !ok {
+		_, err := r.insertLocked(eventID, recordID, interaction)
+		return err
+	}
+
+	updated := *row
+	updated.userDisplayName = interaction.UserDisplayName
+	updated.userPictureUrl = interaction.UserPictureUrl
+	updated.status = interaction.Status
+	updated.timestamp = interaction.Timestamp
+	if err := updated.setPayload(interaction); err != nil {
+		return err
+	}
+	if err := r.touch(&updated); err != nil {
+		return err
+	}
+	*row = updated
+	return nil
+}
+
+func (r *MemoryInteractionRepository) GetByEventID(ctx context.Context, eventID string) ([]*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return toModels(r.selectLocked(eventID, func(*memoryInteraction) bool { return true })), nil
+}
+
+// GetByEventIDSince returns interactions created or modified after the given event revision
+func (r *MemoryInteractionRepository) GetByEventIDSince(ctx context.Context, eventID string, since int64) ([]*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rows := r.selectLocked(eventID, func(row *memoryInteraction) bool { return row.revision > since })
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].revision < rows[j].revision })
+	return toModels(rows), nil
+}
+
+func (r *MemoryInteractionRepository) GetRevision(ctx context.Context, eventID string) (int64, error) {
+	return r.events.revision(eventID)
+}
+
+func (r *MemoryInteractionRepository) GetByUserAndType(ctx context.Context, eventID, userID string, iType models.InteractionType) ([]*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return toModels(r.selectLocked(eventID, func(row *memoryInteraction) bool {
+		return row.userID == userID && row.iType == iType
+	})), nil
+}
+
+// findLocked returns the event's record with the ID; r.mu must be held
+func (r *MemoryInteractionRepository) findLocked(eventID, recordID string) *memoryInteraction {
+	row, ok := r.records[recordID]
+	if !ok || row.eventID != eventID {
+		return nil
+	}
+	return row
+}
+
+func (r *MemoryInteractionRepository) GetByID(ctx context.Context, eventID, recordID string) (*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.findLocked(eventID, recordID)
+	if row == nil {
+		return nil, sql.ErrNoRows
+	}
+	return row.toModel()
+}
+
+func (r *MemoryInteractionRepository) Update(ctx context.Context, eventID, recordID string, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.findLocked(eventID, recordID)
+	if row == nil {
+		return sql.ErrNoRows
+	}
+	current, err := row.toModel()
+	if err != nil {
+		return err
+	}
+
+	applyInteractionUpdates(current, updates)
+
+	row.status = current.Status
+	if err := row.setPayload(current); err != nil {
+		return err
+	}
+	return r.touch(row)
+}
+
+func (r *MemoryInteractionRepository) Delete(ctx context.Context, eventID, recordID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.findLocked(eventID, recordID) != nil {
+		delete(r.records, recordID)
+	}
+	return nil
+}
+
+// RegisterLineUp counts active records and inserts while holding the repository lock
+func (r *MemoryInteractionRepository) RegisterLineUp(ctx context.Context, eventID string, interaction *models.Interaction, exemptUserLimit bool) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, err := r.events.GetByID(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+	limits := LineUpLimitsOf(event.Config)
+	if exemptUserLimit {
+		limits.MaxCountPerUser = 0
+	}
+
+	var totalActive, waitlistCount, userActive int
+	for _, row := range r.activeLineUpLocked(eventID) {
+		totalActive++
+		if row.status == "WAITLIST" {
+			waitlistCount++
+		}
+		if row.userID == interaction.UserID {
+			userActive++
+		}
+	}
+
+	if limits.MaxCountPerUser > 0 && userActive >= limits.MaxCountPerUser {
+		return "", ErrRegistrationLimitReached
+	}
+
+	if totalActive >= limits.MaxParticipants {
+		if limits.WaitlistLimit > 0 && waitlistCount >= limits.WaitlistLimit {
+			return "", ErrWaitlistFull
+		}
+		interaction.Status = "WAITLIST"
+	} else {
+		interaction.Status = "SUCCESS"
+	}
+
+	row, err := r.insertLocked(eventID, "", interaction)
+	if err != nil {
+		return "", err
+	}
+	return row.id, nil
+}
+
+// activeLineUpLocked returns the event's non-cancelled LINEUP rows in queue order;
+// r.mu must be held
+func (r *MemoryInteractionRepository) activeLineUpLocked(eventID string) []*memoryInteraction {
+	return sortQueue(r.selectLocked(eventID, func(row *memoryInteraction) bool {
+		return row.iType == models.InteractionTypeLineUp && row.status != "CANCELLED"
+	}))
+}
+
+// sortQueue stably reorders rows from timestamp order into queue order
+func sortQueue(rows []*memoryInteraction) []*memoryInteraction {
+	queued := make(map[*memoryInteraction]time.Time, len(rows))
+	for _, row := range rows {
+		queued[row] = row.timestamp
+		if rec, err := row.toModel(); err == nil {
+			queued[row] = rec.QueueTime()
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return queued[rows[i]].Before(queued[rows[j]]) })
+	return rows
+}
+
+// CancelLineUp cancels the user's latest active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seats
+func (r *MemoryInteractionRepository) CancelLineUp(ctx context.Context, eventID, userID string) (*models.Interaction, []*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, err := r.events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Latest by registration time, not queue position
+	var latest *memoryInteraction
+	for _, row := range r.selectLocked(eventID, func(row *memoryInteraction) bool {
+		return row.iType == models.InteractionTypeLineUp && row.status != "CANCELLED"
+	}) {
+		if row.userID == userID {
+			latest = row
+		}
+	}
+	if latest == nil {
+		return nil, nil, ErrNoActiveRegistration
+	}
+	return r.cancelLocked(latest, userID, event.Config.MaxParticipants)
+}
+
+// CancelLineUpRecord cancels a specific active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seat
+func (r *MemoryInteractionRepository) CancelLineUpRecord(ctx context.Context, eventID, recordID, cancelledBy string) (*models.Interaction, []*models.Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, err := r.events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	row := r.findLocked(eventID, recordID)
+	if row == nil || row.iType != models.InteractionTypeLineUp || row.status == "CANCELLED" {
+		return nil, nil, ErrNoActiveRegistration
+	}
+	return r.cancelLocked(row, cancelledBy, event.Config.MaxParticipants)
+}
+
+// cancelLocked cancels target and fills freed seats from the waitlist; r.mu must be held
+func (r *MemoryInteractionRepository) cancelLocked(target *memoryInteraction, cancelledBy string, maxParticipants int) (*models.Interaction, []*models.Interaction, error) {
+	now := time.Now()
+
+	cancelled, err := target.toModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	cancelled.Status = "CANCELLED"
+	cancelled.CancelledAt = &now
+	cancelled.CancelledBy = cancelledBy
+	if err := r.writeLocked(target, cancelled); err != nil {
+		return nil, nil, err
+	}
+
+	success := 0
+	waitlist := make([]*memoryInteraction, 0)
+	for _, row := range r.activeLineUpLocked(target.eventID) {
+		switch row.status {
+		case "SUCCESS":
+			success++
+		case "WAITLIST":
+			waitlist = append(waitlist, row)
+		}
+	}
+
+	promoted := make([]*models.Interaction, 0)
+	for _, row := range waitlist {
+		if success >= maxParticipants {
+			break
+		}
+		rec, err := r.promoteLocked(row, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		success++
+		promoted = append(promoted, rec)
+	}
+
+	result, err := target.toModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, promoted, nil
+}
+
+// writeLocked stores the status and payload fields of interaction on row; r.mu must be held
+func (r *MemoryInteractionRepository) writeLocked(row *memoryInteraction, interaction *models.Interaction) error {
+	row.status = interaction.Status
+	if err := row.setPayload(interaction); err != nil {
+		return err
+	}
+	return r.touch(row)
+}
+
+// promoteLocked marks row SUCCESS and stamps promotedAt; r.mu must be held
+func (r *MemoryInteractionRepository) promoteLocked(row *memoryInteraction, now time.Time) (*models.Interaction, error) {
+	rec, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+	rec.Status = "SUCCESS"
+	rec.PromotedAt = &now
+	if err := r.writeLocked(row, rec); err != nil {
+		return nil, err
+	}
+	return row.toModel()
+}
+
+// ReconcileLineUp plans status changes for all active LINEUP registrations and applies
+// them unless dryRun is set
+func (r *MemoryInteractionRepository) ReconcileLineUp(ctx context.Context, eventID string, maxParticipants int, dryRun bool) ([]LineUpStatusChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.events.revision(eventID); err != nil {
+		return nil, err
+	}
+
+	rows := r.activeLineUpLocked(eventID)
+	changes := PlanLineUpStatuses(toModels(rows), maxParticipants)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	if err := r.applyStatusesLocked(changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// UpdateLineUpEvent saves the event and applies the status changes its new capacity
+// requires while holding the lock every LINEUP write takes
+func (r *MemoryInteractionRepository) UpdateLineUpEvent(ctx context.Context, event *models.Event) ([]LineUpStatusChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.events.revision(event.EventID); err != nil {
+		return nil, err
+	}
+	if err := r.events.Update(ctx, event); err != nil {
+		return nil, err
+	}
+
+	rows := r.activeLineUpLocked(event.EventID)
+	changes := PlanLineUpStatuses(toModels(rows), event.Config.MaxParticipants)
+	if err := r.applyStatusesLocked(changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ReorderLineUp moves the active LINEUP registrations into the given order by setting
+// their queuedAt, then re-applies capacity
+func (r *MemoryInteractionRepository) ReorderLineUp(ctx context.Context, eventID string, recordIDs []string) ([]LineUpStatusChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, err := r.events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := r.activeLineUpLocked(eventID)
+	active := toModels(rows)
+	queued, err := PlanLineUpOrder(active, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range active {
+		at := queued[rec.ID]
+		if at.Equal(rec.QueueTime()) {
+			continue
+		}
+		rec.QueuedAt = &at
+		if err := r.writeLocked(r.records[rec.ID], rec); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := PlanLineUpStatuses(active, event.Config.MaxParticipants)
+	if err := r.applyStatusesLocked(changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// applyStatusesLocked writes planned status changes, stamping promotedAt on promotions;
+// r.mu must be held
+func (r *MemoryInteractionRepository) applyStatusesLocked(changes []LineUpStatusChange) error {
+	now := time.Now()
+	for _, change := range changes {
+		row := r.records[change.RecordID]
+		if change.To == "SUCCESS" {
+			if _, err := r.promoteLocked(row, now); err != nil {
+				return err
+			}
+			continue
+		}
+		row.status = change.To
+		if err := r.touch(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryInteractionRepository) TallyVotes(ctx context.Context, eventID string, withVoters bool) (*VoteSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := &VoteSummary{Tallies: make([]VoteTally, 0)}
+	byOption := make(map[string]*VoteTally)
+	for _, rec := range toModels(r.selectLocked(eventID, func(row *memoryInteraction) bool {
+		return row.iType == models.InteractionTypeVote
+	})) {
+		if len(rec.SelectedOptions) == 0 {
+			continue
+		}
+		summary.TotalVoters++
+		for _, opt := range rec.SelectedOptions {
+			tally, ok := byOption[opt]
+			if !ok {
+				tally = &VoteTally{OptionID: opt}
+				byOption[opt] = tally
+			}
+			tally.Count++
+			if withVoters {
+				tally.Voters = append(tally.Voters, Voter{
+					UserID:          rec.UserID,
+					UserDisplayName: rec.UserDisplayName,
+					UserPictureUrl:  rec.UserPictureUrl,
+				})
+			}
+		}
+	}
+
+	for _, tally := range byOption {
+		summary.Tallies = append(summary.Tallies, *tally)
+	}
+	sort.Slice(summary.Tallies, func(i, j int) bool {
+		return summary.Tallies[i].OptionID < summary.Tallies[j].OptionID
+	})
+	return summary, nil
+}
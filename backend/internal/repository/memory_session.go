@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// MemorySessionRepository implements SessionRepository in memory
+type MemorySessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+}
+
+// NewMemorySessionRepository creates a new MemorySessionRepository
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{sessions: make(map[string]*models.Session)}
+}
+
+func (r *MemorySessionRepository) Create(ctx context.Context, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sessions[session.ID]; ok {
+		return fmt.Errorf("session %s already exists", session.ID)
+	}
+	for _, s := range r.sessions {
+		if s.RefreshTokenHash == session.RefreshTokenHash {
+			return errors.New("refresh token hash already in use")
+		}
+	}
+	copied := *session
+	copied.PreviousTokenHash = ""
+	copied.RevokedAt = nil
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *MemorySessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.RefreshTokenHash == tokenHash || s.PreviousTokenHash == tokenHash {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r *MemorySessionRepository) Rotate(ctx context.Context, sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	s, ok := r.sessions[sessionID]
+	if !ok || s.RefreshTokenHash != oldHash || !s.Active(now) {
+		return ErrSessionNotFound
+	}
+	s.PreviousTokenHash = oldHash
+	s.RefreshTokenHash = newHash
+	s.ExpiresAt = expiresAt
+	s.LastUsedAt = now
+	return nil
+}
+
+func (r *MemorySessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sessions[sessionID]; ok && s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+	return nil
+}
+
+func (r *MemorySessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			revokedAt := now
+			s.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *MemorySessionRepository) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[sessionID]
+	return ok && s.Active(time.Now()), nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// MemorySigningKeyRepository implements SigningKeyRepository in memory
+type MemorySigningKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*models.SigningKey
+}
+
+// NewMemorySigningKeyRepository creates a new MemorySigningKeyRepository
+func NewMemorySigningKeyRepository() *MemorySigningKeyRepository {
+	return &MemorySigningKeyRepository{keys: make(map[string]*models.SigningKey)}
+}
+
+func (r *MemorySigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[key.ID]; ok {
+		return fmt.Errorf("signing key %s already exists", key.ID)
+	}
+	copied := *key
+	copied.PrivateKey = append([]byte(nil), key.PrivateKey...)
+	r.keys[key.ID] = &copied
+	return nil
+}
+
+func (r *MemorySigningKeyRepository) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]*models.SigningKey, 0)
+	for _, key := range r.keys {
+		if key.ExpiresAt.After(now) {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *MemorySigningKeyRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, key := range r.keys {
+		if !key.ExpiresAt.After(before) {
+			delete(r.keys, id)
+		}
+	}
+	return nil
+}
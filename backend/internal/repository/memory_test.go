@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestNewRepositoriesSelectsMemory(t *testing.T) {
+	t.Setenv("DB_TYPE", DBTypeMemory)
+	t.Setenv("POSTGRES_PASSWORD", "")
+
+	repos, err := NewRepositories(LoadConfigFromEnv())
+	if err != nil {
+		t.Fatalf("NewRepositories: %v", err)
+	}
+	if _, ok := repos.Events.(*MemoryEventRepository); !ok {
+		t.Fatalf("Events is %T, want *MemoryEventRepository", repos.Events)
+	}
+	if err := repos.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewRepositories(&Config{Type: "oracle"}); err == nil {
+		t.Fatal("expected an error for an unknown DB_TYPE")
+	}
+}
+
+func TestMemoryInteractionsRequireEvent(t *testing.T) {
+	repos := NewMemoryRepositories()
+	ctx := context.Background()
+
+	rec := &models.Interaction{UserID: "u1", Type: models.InteractionTypeMemo, Timestamp: time.Now()}
+	if _, err := repos.Interactions.Create(ctx, "missing", rec); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Create for unknown event: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repos.Interactions.RegisterLineUp(ctx, "missing", rec, false); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("RegisterLineUp for unknown event: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repos.Interactions.GetRevision(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetRevision for unknown event: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestMemoryRecordsAreCopies(t *testing.T) {
+	repos := NewMemoryRepositories()
+	ctx := context.Background()
+
+	event := &models.Event{EventID: "ev1", Type: models.EventTypeVote, Config: models.EventConfig{Options: []string{"a", "b"}}}
+	if err := repos.Events.Create(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	event.Config.Options[0] = "changed"
+	if got, _ := repos.Events.GetByID(ctx, "ev1"); got.Config.Options[0] != "a" {
+		t.Fatalf("stored event shares the caller's slice: %v", got.Config.Options)
+	}
+
+	vote := &models.Interaction{UserID: "u1", Type: models.InteractionTypeVote, SelectedOptions: []string{"a"}, Timestamp: time.Now()}
+	if err := repos.Interactions.CreateWithID(ctx, "ev1", "u1", vote); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := repos.Interactions.GetByID(ctx, "ev1", "u1")
+	got.SelectedOptions[0] = "changed"
+	if again, _ := repos.Interactions.GetByID(ctx, "ev1", "u1"); again.SelectedOptions[0] != "a" {
+		t.Fatalf("returned record shares the stored slice: %v", again.SelectedOptions)
+	}
+}
+
+func TestMemoryRegisterLineUpConcurrent(t *testing.T) {
+	repos := NewMemoryRepositories()
+	ctx := context.Background()
+	if err := repos.Events.Create(ctx, &models.Event{
+		EventID: "ev1",
+		Type:    models.EventTypeLineUp,
+		Config:  models.EventConfig{MaxParticipants: 10, WaitlistLimit: 5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			repos.Interactions.RegisterLineUp(ctx, "ev1", &models.Interaction{
+				UserID:    fmt.Sprintf("u%d", i),
+				Type:      models.InteractionTypeLineUp,
+				Count:     1,
+				Timestamp: time.Now(),
+			}, false)
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := repos.Interactions.GetByEventID(ctx, "ev1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, rec := range records {
+		counts[rec.Status]++
+	}
+	if counts["SUCCESS"] != 10 || counts["WAITLIST"] != 5 || len(records) != 15 {
+		t.Fatalf("statuses = %v (%d records), want 10 SUCCESS and 5 WAITLIST", counts, len(records))
+	}
+	if revision, _ := repos.Interactions.GetRevision(ctx, "ev1"); revision != 15 {
+		t.Fatalf("revision = %d, want 15", revision)
+	}
+}
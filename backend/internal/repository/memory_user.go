@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// MemoryUserRepository implements UserRepository in memory
+type MemoryUserRepository struct {
+	mu        sync.Mutex
+	users     map[string]*models.User
+	nicknames map[[2]string]*models.EventNickname // By (eventID, userID)
+}
+
+// NewMemoryUserRepository creates a new MemoryUserRepository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users:     make(map[string]*models.User),
+		nicknames: make(map[[2]string]*models.EventNickname),
+	}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.LineUserID]; ok {
+		return fmt.Errorf("user %s already exists", user.LineUserID)
+	}
+	copied := *user
+	r.users[user.LineUserID] = &copied
+	return nil
+}
+
+func (r *MemoryUserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// Update leaves CreatedAt unchanged, like the Postgres UPDATE
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.users[user.LineUserID]
+	if !ok {
+		return nil
+	}
+	existing.LineDisplayName = user.LineDisplayName
+	existing.PictureURL = user.PictureURL
+	existing.CustomName = user.CustomName
+	existing.Role = user.Role
+	return nil
+}
+
+func (r *MemoryUserRepository) UpdateFields(ctx context.Context, userID string, updates map[string]interface{}) error {
+	user, err := r.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range updates {
+		switch key {
+		case "lineDisplayName":
+			user.LineDisplayName = value.(string)
+		case "pictureUrl":
+			user.PictureURL = value.(string)
+		case "customName":
+			user.CustomName = value.(string)
+		case "role":
+			user.Role = value.(string)
+		}
+	}
+
+	return r.Update(ctx, user)
+}
+
+func (r *MemoryUserRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.users[userID]
+	return ok, nil
+}
+
+func (r *MemoryUserRepository) ListByRole(ctx context.Context, role string) ([]*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make([]*models.User, 0)
+	for _, user := range r.users {
+		if user.Role == role {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].LineUserID < users[j].LineUserID
+	})
+	return users, nil
+}
+
+func (r *MemoryUserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, user := range r.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryUserRepository) RevokeAdmin(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok || user.Role != "admin" {
+		return ErrNotAdmin
+	}
+	admins := 0
+	for _, other := range r.users {
+		if other.Role == "admin" {
+			admins++
+		}
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+
+	user.Role = "user"
+	return nil
+}
+
+func (r *MemoryUserRepository) GetEventNickname(ctx context.Context, eventID, userID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.nicknames[[2]string{eventID, userID}]; ok {
+		return n.Nickname, nil
+	}
+	return "", nil
+}
+
+func (r *MemoryUserRepository) SetEventNickname(ctx context.Context, eventID, userID, nickname string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := [2]string{eventID, userID}
+	if nickname == "" {
+		delete(r.nicknames, key)
+		return nil
+	}
+	r.nicknames[key] = &models.EventNickname{
+		EventID:   eventID,
+		UserID:    userID,
+		Nickname:  nickname,
+		UpdatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) ListEventNicknames(ctx context.Context, userID string) ([]*models.EventNickname, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	nicknames := make([]*models.EventNickname, 0)
+	for _, n := range r.nicknames {
+		if n.UserID == userID {
+			copied := *n
+			nicknames = append(nicknames, &copied)
+		}
+	}
+	sort.Slice(nicknames, func(i, j int) bool {
+		return nicknames[i].UpdatedAt.After(nicknames[j].UpdatedAt)
+	})
+	return nicknames, nil
+}
+
+// displayName returns the name ListOrganizers shows for the user, "" if unknown
+func (r *MemoryUserRepository) displayName(userID string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[userID]; ok {
+		return user.DisplayName()
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"event-manager/internal/models"
+)
+
+// PostgresAuditRepository implements AuditRepository using PostgreSQL
+type PostgresAuditRepository struct {
+	client *PostgresClient
+}
+
+// NewPostgresAuditRepository creates a new PostgresAuditRepository
+func NewPostgresAuditRepository(client *PostgresClient) *PostgresAuditRepository {
+	return &PostgresAuditRepository{client: client}
+}
+
+func (r *PostgresAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (event_id, record_id, actor, action, before, after)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.client.DB.QueryRowContext(ctx, query,
+		entry.EventID, entry.RecordID, entry.Actor, entry.Action, nullJSON(entry.Before), nullJSON(entry.After)).
+		Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *PostgresAuditRepository) ListByEvent(ctx context.Context, eventID string, limit int) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, event_id, COALESCE(record_id, ''), actor, action, before, after, created_at
+		FROM audit_log WHERE event_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&entry.ID, &entry.EventID, &entry.RecordID, &entry.Actor, &entry.Action, &before, &after, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullJSON stores empty JSON as SQL NULL
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return sql.NullString{}
+	}
+	return string(data)
+}
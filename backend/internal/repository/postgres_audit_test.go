@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func TestPostgresAuditLog(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresAuditRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1})
+	t.Cleanup(func() {
+		client.DB.Exec(`DELETE FROM audit_log WHERE event_id = $1`, event.EventID)
+	})
+
+	first := &models.AuditEntry{EventID: event.EventID, Actor: "a", Action: "EVENT_CREATE", After: json.RawMessage(`{"title":"x"}`)}
+	second := &models.AuditEntry{EventID: event.EventID, RecordID: "rec-1", Actor: "b", Action: "NOTE_UPDATE",
+		Before: json.RawMessage(`{"note":""}`), After: json.RawMessage(`{"note":"hi"}`)}
+	for _, entry := range []*models.AuditEntry{first, second} {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if entry.ID == 0 || entry.CreatedAt.IsZero() {
+			t.Fatalf("Create did not set ID/CreatedAt: %+v", entry)
+		}
+	}
+
+	entries, err := repo.ListByEvent(ctx, event.EventID, 10)
+	if err != nil {
+		t.Fatalf("ListByEvent: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != second.ID || entries[1].Before != nil {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].RecordID != "rec-1" || entries[1].RecordID != "" {
+		t.Errorf("record IDs = %q, %q", entries[0].RecordID, entries[1].RecordID)
+	}
+}
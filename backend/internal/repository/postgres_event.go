@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"event-manager/internal/models"
 
@@ -16,14 +17,17 @@ type PostgresClient struct {
 	DB *sql.DB
 }
 
-// NewPostgresClient creates a new PostgreSQL client
-func NewPostgresClient(cfg *PostgresConfig) (*PostgresClient, error) {
-	connStr := fmt.Sprintf(
+// ConnString returns the lib/pq connection string for the config
+func (cfg *PostgresConfig) ConnString() string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
 	)
+}
 
-	db, err := sql.Open("postgres", connStr)
+// NewPostgresClient creates a new PostgreSQL client
+func NewPostgresClient(cfg *PostgresConfig) (*PostgresClient, error) {
+	db, err := sql.Open("postgres", cfg.ConnString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -75,14 +79,14 @@ func (r *PostgresEventRepository) Create(ctx context.Context, event *models.Even
 
 func (r *PostgresEventRepository) GetByID(ctx context.Context, eventID string) (*models.Event, error) {
 	query := `
-		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config
+		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config, status_changed_at
 		FROM events WHERE event_id = $1
 	`
 	var event models.Event
 	var configJSON []byte
 
 	err := r.client.DB.QueryRowContext(ctx, query, eventID).Scan(
-		&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON)
+		&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON, &event.StatusChangedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +99,17 @@ func (r *PostgresEventRepository) GetByID(ctx context.Context, eventID string) (
 }
 
 func (r *PostgresEventRepository) Update(ctx context.Context, event *models.Event) error {
+	return updateEvent(ctx, r.client.DB, event)
+}
+
+// postgresQuerier is satisfied by both *sql.DB and *sql.Tx
+type postgresQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// updateEvent writes the event's editable columns
+func updateEvent(ctx context.Context, db postgresQuerier, event *models.Event) error {
 	configJSON, err := json.Marshal(event.Config)
 	if err != nil {
 		return err
@@ -102,16 +117,16 @@ func (r *PostgresEventRepository) Update(ctx context.Context, event *models.Even
 
 	query := `
 		UPDATE events 
-		SET type = $2, title = $3, tag = $4, is_active = $5, config = $6
+		SET type = $2, title = $3, tag = $4, is_active = $5, config = $6, status_changed_at = $7
 		WHERE event_id = $1
 	`
-	_, err = r.client.DB.ExecContext(ctx, query,
-		event.EventID, event.Type, event.Title, event.Tag, event.IsActive, configJSON)
+	_, err = db.ExecContext(ctx, query,
+		event.EventID, event.Type, event.Title, event.Tag, event.IsActive, configJSON, event.StatusChangedAt)
 	return err
 }
 
 func (r *PostgresEventRepository) UpdateStatus(ctx context.Context, eventID string, isActive bool) error {
-	query := `UPDATE events SET is_active = $2 WHERE event_id = $1`
+	query := `UPDATE events SET is_active = $2, status_changed_at = NOW() WHERE event_id = $1`
 	_, err := r.client.DB.ExecContext(ctx, query, eventID, isActive)
 	return err
 }
@@ -124,7 +139,7 @@ func (r *PostgresEventRepository) UpdateArchived(ctx context.Context, eventID st
 
 func (r *PostgresEventRepository) List(ctx context.Context, limit int) ([]*models.Event, error) {
 	query := `
-		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config
+		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config, status_changed_at
 		FROM events ORDER BY created_at DESC LIMIT $1
 	`
 	rows, err := r.client.DB.QueryContext(ctx, query, limit)
@@ -138,7 +153,7 @@ func (r *PostgresEventRepository) List(ctx context.Context, limit int) ([]*model
 		var event models.Event
 		var configJSON []byte
 
-		if err := rows.Scan(&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON); err != nil {
+		if err := rows.Scan(&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON, &event.StatusChangedAt); err != nil {
 			continue
 		}
 		if err := json.Unmarshal(configJSON, &event.Config); err != nil {
@@ -153,14 +168,14 @@ func (r *PostgresEventRepository) List(ctx context.Context, limit int) ([]*model
 // GetByTag returns the most recently created event with the specified tag
 func (r *PostgresEventRepository) GetByTag(ctx context.Context, tag string) (*models.Event, error) {
 	query := `
-		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config
+		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config, status_changed_at
 		FROM events WHERE tag = $1 ORDER BY created_at DESC LIMIT 1
 	`
 	var event models.Event
 	var configJSON []byte
 
 	err := r.client.DB.QueryRowContext(ctx, query, tag).Scan(
-		&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON)
+		&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON, &event.StatusChangedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -171,3 +186,125 @@ func (r *PostgresEventRepository) GetByTag(ctx context.Context, tag string) (*mo
 
 	return &event, nil
 }
+
+func (r *PostgresEventRepository) ListScheduled(ctx context.Context, from, to time.Time) ([]*models.Event, error) {
+	query := `
+		SELECT event_id, type, title, COALESCE(tag, ''), is_active, COALESCE(is_archived, false), created_by, created_at, config, status_changed_at
+		FROM events
+		WHERE COALESCE(is_archived, false) = false
+		AND (
+			(NULLIF(config->>'startTime', '')::timestamptz > $1 AND NULLIF(config->>'startTime', '')::timestamptz <= $2)
+			OR (NULLIF(config->>'endTime', '')::timestamptz > $1 AND NULLIF(config->>'endTime', '')::timestamptz <= $2)
+		)
+		ORDER BY created_at DESC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.Event, 0)
+	for rows.Next() {
+		var event models.Event
+		var configJSON []byte
+
+		if err := rows.Scan(&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON, &event.StatusChangedAt); err != nil {
+			continue
+		}
+		if err := json.Unmarshal(configJSON, &event.Config); err != nil {
+			continue
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+func (r *PostgresEventRepository) IsOrganizer(ctx context.Context, eventID, userID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM event_organizers WHERE event_id = $1 AND user_id = $2)`
+	err := r.client.DB.QueryRowContext(ctx, query, eventID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresEventRepository) ListOrganizers(ctx context.Context, eventID string) ([]*models.EventOrganizer, error) {
+	query := `
+		SELECT o.event_id, o.user_id, COALESCE(NULLIF(u.custom_name, ''), u.line_display_name, ''), o.added_by, o.added_at
+		FROM event_organizers o
+		LEFT JOIN users u ON u.line_user_id = o.user_id
+		WHERE o.event_id = $1
+		ORDER BY o.added_at ASC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizers := make([]*models.EventOrganizer, 0)
+	for rows.Next() {
+		var organizer models.EventOrganizer
+		if err := rows.Scan(&organizer.EventID, &organizer.UserID, &organizer.UserDisplayName, &organizer.AddedBy, &organizer.AddedAt); err != nil {
+			return nil, err
+		}
+		organizers = append(organizers, &organizer)
+	}
+
+	return organizers, rows.Err()
+}
+
+func (r *PostgresEventRepository) AddOrganizer(ctx context.Context, eventID, userID, addedBy string) error {
+	query := `
+		INSERT INTO event_organizers (event_id, user_id, added_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, user_id) DO NOTHING
+	`
+	_, err := r.client.DB.ExecContext(ctx, query, eventID, userID, addedBy)
+	return err
+}
+
+func (r *PostgresEventRepository) RemoveOrganizer(ctx context.Context, eventID, userID string) error {
+	result, err := r.client.DB.ExecContext(ctx, `DELETE FROM event_organizers WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrOrganizerNotFound
+	}
+	return nil
+}
+
+func (r *PostgresEventRepository) TransferOwnership(ctx context.Context, eventID, newOwner, transferredBy string) error {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousOwner string
+	err = tx.QueryRowContext(ctx, `SELECT created_by FROM events WHERE event_id = $1 FOR UPDATE`, eventID).Scan(&previousOwner)
+	if err != nil {
+		return err
+	}
+	if previousOwner == newOwner {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET created_by = $2 WHERE event_id = $1`, eventID, newOwner); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_organizers WHERE event_id = $1 AND user_id = $2`, eventID, newOwner); err != nil {
+		return err
+	}
+	insert := `
+		INSERT INTO event_organizers (event_id, user_id, added_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, user_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insert, eventID, previousOwner, transferredBy); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
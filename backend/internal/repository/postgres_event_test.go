@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func TestPostgresEventOrganizers(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresEventRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1})
+
+	if err := repo.AddOrganizer(ctx, event.EventID, "co", "test"); err != nil {
+		t.Fatalf("AddOrganizer: %v", err)
+	}
+	// Adding twice is a no-op
+	if err := repo.AddOrganizer(ctx, event.EventID, "co", "test"); err != nil {
+		t.Fatalf("AddOrganizer again: %v", err)
+	}
+
+	ok, err := repo.IsOrganizer(ctx, event.EventID, "co")
+	if err != nil || !ok {
+		t.Fatalf("IsOrganizer(co) = %v, %v", ok, err)
+	}
+
+	if err := repo.TransferOwnership(ctx, event.EventID, "co", "test"); err != nil {
+		t.Fatalf("TransferOwnership: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, event.EventID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.CreatedBy != "co" {
+		t.Errorf("CreatedBy = %s, want co", stored.CreatedBy)
+	}
+
+	organizers, err := repo.ListOrganizers(ctx, event.EventID)
+	if err != nil {
+		t.Fatalf("ListOrganizers: %v", err)
+	}
+	if len(organizers) != 1 || organizers[0].UserID != "test" {
+		t.Fatalf("organizers = %+v, want previous owner only", organizers)
+	}
+
+	if err := repo.RemoveOrganizer(ctx, event.EventID, "test"); err != nil {
+		t.Fatalf("RemoveOrganizer: %v", err)
+	}
+	if err := repo.RemoveOrganizer(ctx, event.EventID, "test"); !errors.Is(err, ErrOrganizerNotFound) {
+		t.Errorf("RemoveOrganizer again = %v, want ErrOrganizerNotFound", err)
+	}
+}
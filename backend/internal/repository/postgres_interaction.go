@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"log"
+	"time"
 
 	"event-manager/internal/models"
 )
@@ -21,24 +22,76 @@ func NewPostgresInteractionRepository(client *PostgresClient) *PostgresInteracti
 
 // interactionPayload holds the JSONB payload fields
 type interactionPayload struct {
-	SelectedOptions []string `json:"selectedOptions,omitempty"`
-	Count           int      `json:"count,omitempty"`
-	Note            string   `json:"note,omitempty"`
-	CancelledAt     *string  `json:"cancelledAt,omitempty"`
-	Content         string   `json:"content,omitempty"`
-	ClapCount       int      `json:"clapCount,omitempty"`
-	Reactions       []string `json:"reactions,omitempty"`
+	SelectedOptions []string   `json:"selectedOptions,omitempty"`
+	Count           int        `json:"count,omitempty"`
+	Note            string     `json:"note,omitempty"`
+	CancelledAt     *time.Time `json:"cancelledAt,omitempty"`
+	PromotedAt      *time.Time `json:"promotedAt,omitempty"`
+	AddedBy         string     `json:"addedBy,omitempty"`
+	CancelledBy     string     `json:"cancelledBy,omitempty"`
+	QueuedAt        *time.Time `json:"queuedAt,omitempty"`
+	Content         string     `json:"content,omitempty"`
+	ClapCount       int        `json:"clapCount,omitempty"`
+	Reactions       []string   `json:"reactions,omitempty"`
 }
 
-func (r *PostgresInteractionRepository) Create(ctx context.Context, eventID string, interaction *models.Interaction) (string, error) {
-	payload := interactionPayload{
+// newInteractionPayload extracts the JSONB payload fields from an interaction
+func newInteractionPayload(interaction *models.Interaction) interactionPayload {
+	return interactionPayload{
 		SelectedOptions: interaction.SelectedOptions,
 		Count:           interaction.Count,
 		Note:            interaction.Note,
+		CancelledAt:     interaction.CancelledAt,
+		PromotedAt:      interaction.PromotedAt,
+		AddedBy:         interaction.AddedBy,
+		CancelledBy:     interaction.CancelledBy,
+		QueuedAt:        interaction.QueuedAt,
 		Content:         interaction.Content,
 		ClapCount:       interaction.ClapCount,
 		Reactions:       interaction.Reactions,
 	}
+}
+
+// applyTo copies the payload fields onto an interaction
+func (p *interactionPayload) applyTo(interaction *models.Interaction) {
+	interaction.SelectedOptions = p.SelectedOptions
+	interaction.Count = p.Count
+	interaction.Note = p.Note
+	interaction.CancelledAt = p.CancelledAt
+	interaction.PromotedAt = p.PromotedAt
+	interaction.AddedBy = p.AddedBy
+	interaction.CancelledBy = p.CancelledBy
+	interaction.QueuedAt = p.QueuedAt
+	interaction.Content = p.Content
+	interaction.ClapCount = p.ClapCount
+	interaction.Reactions = p.Reactions
+}
+
+// applyInteractionUpdates merges the fields Update accepts onto an interaction; other
+// keys are ignored
+func applyInteractionUpdates(interaction *models.Interaction, updates map[string]interface{}) {
+	for key, value := range updates {
+		switch key {
+		case "status":
+			interaction.Status = value.(string)
+		case "note":
+			interaction.Note = value.(string)
+		case "content":
+			interaction.Content = value.(string)
+		case "clapCount":
+			interaction.ClapCount = value.(int)
+		case "cancelledAt":
+			t := value.(time.Time)
+			interaction.CancelledAt = &t
+		case "promotedAt":
+			t := value.(time.Time)
+			interaction.PromotedAt = &t
+		}
+	}
+}
+
+func (r *PostgresInteractionRepository) Create(ctx context.Context, eventID string, interaction *models.Interaction) (string, error) {
+	payload := newInteractionPayload(interaction)
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
@@ -61,14 +114,7 @@ func (r *PostgresInteractionRepository) Create(ctx context.Context, eventID stri
 }
 
 func (r *PostgresInteractionRepository) CreateWithID(ctx context.Context, eventID, recordID string, interaction *models.Interaction) error {
-	payload := interactionPayload{
-		SelectedOptions: interaction.SelectedOptions,
-		Count:           interaction.Count,
-		Note:            interaction.Note,
-		Content:         interaction.Content,
-		ClapCount:       interaction.ClapCount,
-		Reactions:       interaction.Reactions,
-	}
+	payload := newInteractionPayload(interaction)
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		log.Printf("[CreateWithID] Failed to marshal payload: %v", err)
@@ -132,7 +178,7 @@ func (r *PostgresInteractionRepository) GetByEventID(ctx context.Context, eventI
 	log.Printf("[GetByEventID] Querying for eventID: %s", eventID)
 
 	query := `
-		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload
+		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
 		FROM interactions WHERE event_id = $1 ORDER BY timestamp ASC
 	`
 	rows, err := r.client.DB.QueryContext(ctx, query, eventID)
@@ -147,9 +193,31 @@ func (r *PostgresInteractionRepository) GetByEventID(ctx context.Context, eventI
 	return result, err
 }
 
+// GetByEventIDSince returns interactions created or modified after the given event revision
+func (r *PostgresInteractionRepository) GetByEventIDSince(ctx context.Context, eventID string, since int64) ([]*models.Interaction, error) {
+	query := `
+		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
+		FROM interactions WHERE event_id = $1 AND revision > $2 ORDER BY revision ASC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, eventID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanInteractions(rows)
+}
+
+// GetRevision returns the event's current revision, bumped by a trigger on every interaction write
+func (r *PostgresInteractionRepository) GetRevision(ctx context.Context, eventID string) (int64, error) {
+	var revision int64
+	err := r.client.DB.QueryRowContext(ctx, `SELECT revision FROM events WHERE event_id = $1`, eventID).Scan(&revision)
+	return revision, err
+}
+
 func (r *PostgresInteractionRepository) GetByUserAndType(ctx context.Context, eventID, userID string, iType models.InteractionType) ([]*models.Interaction, error) {
 	query := `
-		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload
+		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
 		FROM interactions WHERE event_id = $1 AND user_id = $2 AND type = $3 ORDER BY timestamp ASC
 	`
 	rows, err := r.client.DB.QueryContext(ctx, query, eventID, userID, iType)
@@ -162,16 +230,21 @@ func (r *PostgresInteractionRepository) GetByUserAndType(ctx context.Context, ev
 }
 
 func (r *PostgresInteractionRepository) GetByID(ctx context.Context, eventID, recordID string) (*models.Interaction, error) {
+	return getInteraction(ctx, r.client.DB, eventID, recordID)
+}
+
+// getInteraction reads one record through db, which may be a locking transaction
+func getInteraction(ctx context.Context, db postgresQuerier, eventID, recordID string) (*models.Interaction, error) {
 	query := `
-		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload
+		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
 		FROM interactions WHERE event_id = $1 AND id = $2
 	`
 	var interaction models.Interaction
 	var payloadJSON []byte
 	var displayName, pictureUrl, status sql.NullString
 
-	err := r.client.DB.QueryRowContext(ctx, query, eventID, recordID).Scan(
-		&interaction.ID, &interaction.UserID, &interaction.Type, &displayName, &pictureUrl, &status, &interaction.Timestamp, &payloadJSON)
+	err := db.QueryRowContext(ctx, query, eventID, recordID).Scan(
+		&interaction.ID, &interaction.UserID, &interaction.Type, &displayName, &pictureUrl, &status, &interaction.Timestamp, &payloadJSON, &interaction.Revision)
 	if err != nil {
 		return nil, err
 	}
@@ -191,53 +264,44 @@ func (r *PostgresInteractionRepository) GetByID(ctx context.Context, eventID, re
 		return nil, err
 	}
 
-	interaction.SelectedOptions = payload.SelectedOptions
-	interaction.Count = payload.Count
-	interaction.Note = payload.Note
-	interaction.Content = payload.Content
-	interaction.ClapCount = payload.ClapCount
-	interaction.Reactions = payload.Reactions
+	payload.applyTo(&interaction)
 
 	return &interaction, nil
 }
 
+// Update merges updates into the record. The event row is locked first: the revision
+// trigger updates events after the interaction row is locked, so without it this would
+// take the two locks in the opposite order to the LINEUP paths and could deadlock.
 func (r *PostgresInteractionRepository) Update(ctx context.Context, eventID, recordID string, updates map[string]interface{}) error {
-	// For simplicity, fetch current, merge, and update
-	current, err := r.GetByID(ctx, eventID, recordID)
+	tx, err := r.client.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	// Apply updates
-	for key, value := range updates {
-		switch key {
-		case "status":
-			current.Status = value.(string)
-		case "note":
-			current.Note = value.(string)
-		case "content":
-			current.Content = value.(string)
-		case "clapCount":
-			current.ClapCount = value.(int)
-		}
+	if _, err := lockEvent(ctx, tx, eventID); err != nil {
+		return err
 	}
 
-	payload := interactionPayload{
-		SelectedOptions: current.SelectedOptions,
-		Count:           current.Count,
-		Note:            current.Note,
-		Content:         current.Content,
-		ClapCount:       current.ClapCount,
-		Reactions:       current.Reactions,
+	// Fetch current, merge, and update
+	current, err := getInteraction(ctx, tx, eventID, recordID)
+	if err != nil {
+		return err
 	}
+
+	applyInteractionUpdates(current, updates)
+
+	payload := newInteractionPayload(current)
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
 	query := `UPDATE interactions SET status = $3, payload = $4 WHERE event_id = $1 AND id = $2`
-	_, err = r.client.DB.ExecContext(ctx, query, eventID, recordID, current.Status, payloadJSON)
-	return err
+	if _, err := tx.ExecContext(ctx, query, eventID, recordID, current.Status, payloadJSON); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *PostgresInteractionRepository) Delete(ctx context.Context, eventID, recordID string) error {
@@ -256,7 +320,7 @@ func (r *PostgresInteractionRepository) scanInteractions(rows *sql.Rows) ([]*mod
 		var payloadJSON []byte
 		var displayName, pictureUrl, status sql.NullString
 
-		if err := rows.Scan(&interaction.ID, &interaction.UserID, &interaction.Type, &displayName, &pictureUrl, &status, &interaction.Timestamp, &payloadJSON); err != nil {
+		if err := rows.Scan(&interaction.ID, &interaction.UserID, &interaction.Type, &displayName, &pictureUrl, &status, &interaction.Timestamp, &payloadJSON, &interaction.Revision); err != nil {
 			log.Printf("[scanInteractions] Row %d scan error: %v", rowCount, err)
 			continue
 		}
@@ -277,12 +341,7 @@ func (r *PostgresInteractionRepository) scanInteractions(rows *sql.Rows) ([]*mod
 			continue
 		}
 
-		interaction.SelectedOptions = payload.SelectedOptions
-		interaction.Count = payload.Count
-		interaction.Note = payload.Note
-		interaction.Content = payload.Content
-		interaction.ClapCount = payload.ClapCount
-		interaction.Reactions = payload.Reactions
+		payload.applyTo(&interaction)
 
 		interactions = append(interactions, &interaction)
 	}
@@ -290,3 +349,407 @@ func (r *PostgresInteractionRepository) scanInteractions(rows *sql.Rows) ([]*mod
 	log.Printf("[scanInteractions] Processed %d rows, returned %d interactions", rowCount, len(interactions))
 	return interactions, nil
 }
+
+// RegisterLineUp locks the event row so concurrent registrations for the same event
+// are serialized, then counts active records and inserts within one transaction.
+func (r *PostgresInteractionRepository) RegisterLineUp(ctx context.Context, eventID string, interaction *models.Interaction, exemptUserLimit bool) (string, error) {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	config, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return "", err
+	}
+	limits := LineUpLimitsOf(config)
+	if exemptUserLimit {
+		limits.MaxCountPerUser = 0
+	}
+
+	var totalActive, waitlistCount, userActive int
+	countQuery := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'WAITLIST'),
+			COUNT(*) FILTER (WHERE user_id = $2)
+		FROM interactions
+		WHERE event_id = $1 AND type = 'LINEUP' AND status <> 'CANCELLED'
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, eventID, interaction.UserID).Scan(&totalActive, &waitlistCount, &userActive); err != nil {
+		return "", err
+	}
+
+	if limits.MaxCountPerUser > 0 && userActive >= limits.MaxCountPerUser {
+		return "", ErrRegistrationLimitReached
+	}
+
+	if totalActive >= limits.MaxParticipants {
+		if limits.WaitlistLimit > 0 && waitlistCount >= limits.WaitlistLimit {
+			return "", ErrWaitlistFull
+		}
+		interaction.Status = "WAITLIST"
+	} else {
+		interaction.Status = "SUCCESS"
+	}
+
+	payload := newInteractionPayload(interaction)
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	insertQuery := `
+		INSERT INTO interactions (event_id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	err = tx.QueryRowContext(ctx, insertQuery,
+		eventID, interaction.UserID, interaction.Type, interaction.UserDisplayName,
+		interaction.UserPictureUrl, interaction.Status, interaction.Timestamp, payloadJSON).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CancelLineUp cancels the user's latest active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seats, all within one transaction.
+func (r *PostgresInteractionRepository) CancelLineUp(ctx context.Context, eventID, userID string) (*models.Interaction, []*models.Interaction, error) {
+	latest := `
+		SELECT id FROM interactions
+		WHERE event_id = $1 AND user_id = $2 AND type = 'LINEUP' AND status <> 'CANCELLED'
+		ORDER BY timestamp DESC LIMIT 1
+	`
+	return r.cancelLineUp(ctx, eventID, latest, userID, userID)
+}
+
+// CancelLineUpRecord cancels a specific active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seat, all within one transaction.
+func (r *PostgresInteractionRepository) CancelLineUpRecord(ctx context.Context, eventID, recordID, cancelledBy string) (*models.Interaction, []*models.Interaction, error) {
+	record := `
+		SELECT id FROM interactions
+		WHERE event_id = $1 AND id = $2 AND type = 'LINEUP' AND status <> 'CANCELLED'
+	`
+	return r.cancelLineUp(ctx, eventID, record, recordID, cancelledBy)
+}
+
+// cancelLineUp cancels the registration picked by selectQuery (parameters $1 = eventID,
+// $2 = selectArg) and fills freed seats from the waitlist
+func (r *PostgresInteractionRepository) cancelLineUp(ctx context.Context, eventID, selectQuery, selectArg, cancelledBy string) (*models.Interaction, []*models.Interaction, error) {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	config, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	cancelQuery := `
+		UPDATE interactions
+		SET status = 'CANCELLED', payload = payload || jsonb_build_object('cancelledAt', $3::text, 'cancelledBy', $4::text)
+		WHERE id = (` + selectQuery + `)
+		RETURNING id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
+	`
+	rows, err := tx.QueryContext(ctx, cancelQuery, eventID, selectArg, now.Format(time.RFC3339Nano), cancelledBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	cancelled, err := r.scanInteractions(rows)
+	rows.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cancelled) == 0 {
+		return nil, nil, ErrNoActiveRegistration
+	}
+
+	promoted, err := r.promoteWaitlist(ctx, tx, eventID, config.MaxParticipants, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return cancelled[0], promoted, nil
+}
+
+// postgresQueueOrder orders LINEUP registrations by queue time (see models.Interaction.QueueTime)
+const postgresQueueOrder = `COALESCE((payload->>'queuedAt')::timestamptz, timestamp) ASC, timestamp ASC`
+
+// lockEvent takes a row lock on the event, serializing LINEUP changes for that event.
+// Every transaction that writes interactions must call it before touching them, because
+// the revision trigger locks the event row only after the interaction row.
+// The returned config is the one in force until the transaction ends.
+func lockEvent(ctx context.Context, tx *sql.Tx, eventID string) (models.EventConfig, error) {
+	var config models.EventConfig
+	var configJSON []byte
+	err := tx.QueryRowContext(ctx, `SELECT config FROM events WHERE event_id = $1 FOR UPDATE`, eventID).Scan(&configJSON)
+	if err != nil {
+		return config, err
+	}
+	if len(configJSON) > 0 {
+		err = json.Unmarshal(configJSON, &config)
+	}
+	return config, err
+}
+
+// promoteWaitlist moves the oldest WAITLIST registrations to SUCCESS until
+// maxParticipants SUCCESS records exist. Must run with the event row locked.
+func (r *PostgresInteractionRepository) promoteWaitlist(ctx context.Context, tx *sql.Tx, eventID string, maxParticipants int, now time.Time) ([]*models.Interaction, error) {
+	var successCount int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM interactions WHERE event_id = $1 AND type = 'LINEUP' AND status = 'SUCCESS'`,
+		eventID).Scan(&successCount)
+	if err != nil {
+		return nil, err
+	}
+
+	free := maxParticipants - successCount
+	if free <= 0 {
+		return []*models.Interaction{}, nil
+	}
+
+	promoteQuery := `
+		UPDATE interactions
+		SET status = 'SUCCESS', payload = payload || jsonb_build_object('promotedAt', $3::text)
+		WHERE id IN (
+			SELECT id FROM interactions
+			WHERE event_id = $1 AND type = 'LINEUP' AND status = 'WAITLIST'
+			ORDER BY ` + postgresQueueOrder + ` LIMIT $2
+		)
+		RETURNING id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
+	`
+	rows, err := tx.QueryContext(ctx, promoteQuery, eventID, free, now.Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promoted, err := r.scanInteractions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING order is unspecified; report promotions in queue order
+	SortLineUpQueue(promoted)
+
+	return promoted, nil
+}
+
+// ReconcileLineUp locks the event, plans status changes for all active LINEUP registrations
+// and applies them unless dryRun is set.
+func (r *PostgresInteractionRepository) ReconcileLineUp(ctx context.Context, eventID string, maxParticipants int, dryRun bool) ([]LineUpStatusChange, error) {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockEvent(ctx, tx, eventID); err != nil {
+		return nil, err
+	}
+
+	active, err := r.activeLineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := PlanLineUpStatuses(active, maxParticipants)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err := applyLineUpStatuses(ctx, tx, eventID, changes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// UpdateLineUpEvent locks the event, saves it and applies the status changes its new
+// capacity requires, all within one transaction.
+func (r *PostgresInteractionRepository) UpdateLineUpEvent(ctx context.Context, event *models.Event) ([]LineUpStatusChange, error) {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockEvent(ctx, tx, event.EventID); err != nil {
+		return nil, err
+	}
+
+	if err := updateEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	active, err := r.activeLineUp(ctx, tx, event.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := PlanLineUpStatuses(active, event.Config.MaxParticipants)
+	if err := applyLineUpStatuses(ctx, tx, event.EventID, changes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ReorderLineUp locks the event, moves the active LINEUP registrations into the given
+// order by setting their queuedAt, then re-applies capacity.
+func (r *PostgresInteractionRepository) ReorderLineUp(ctx context.Context, eventID string, recordIDs []string) ([]LineUpStatusChange, error) {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	config, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := r.activeLineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := PlanLineUpOrder(active, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range active {
+		at := queued[rec.ID]
+		if at.Equal(rec.QueueTime()) {
+			continue
+		}
+		update := `UPDATE interactions SET payload = payload || jsonb_build_object('queuedAt', $3::text) WHERE event_id = $1 AND id = $2`
+		if _, err := tx.ExecContext(ctx, update, eventID, rec.ID, at.Format(time.RFC3339Nano)); err != nil {
+			return nil, err
+		}
+		rec.QueuedAt = &at
+	}
+
+	changes := PlanLineUpStatuses(active, config.MaxParticipants)
+	if err := applyLineUpStatuses(ctx, tx, eventID, changes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// activeLineUp returns the event's non-cancelled LINEUP registrations in queue order
+func (r *PostgresInteractionRepository) activeLineUp(ctx context.Context, tx *sql.Tx, eventID string) ([]*models.Interaction, error) {
+	query := `
+		SELECT id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload, revision
+		FROM interactions
+		WHERE event_id = $1 AND type = 'LINEUP' AND status <> 'CANCELLED'
+		ORDER BY ` + postgresQueueOrder + `
+	`
+	rows, err := tx.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanInteractions(rows)
+}
+
+// applyLineUpStatuses writes planned status changes, stamping promotedAt on promotions
+func applyLineUpStatuses(ctx context.Context, tx *sql.Tx, eventID string, changes []LineUpStatusChange) error {
+	now := time.Now().Format(time.RFC3339Nano)
+	for _, change := range changes {
+		update := `UPDATE interactions SET status = $3 WHERE event_id = $1 AND id = $2`
+		args := []interface{}{eventID, change.RecordID, change.To}
+		if change.To == "SUCCESS" {
+			update = `UPDATE interactions SET status = $3, payload = payload || jsonb_build_object('promotedAt', $4::text) WHERE event_id = $1 AND id = $2`
+			args = append(args, now)
+		}
+		if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresInteractionRepository) TallyVotes(ctx context.Context, eventID string, withVoters bool) (*VoteSummary, error) {
+	summary := &VoteSummary{Tallies: make([]VoteTally, 0)}
+
+	totalQuery := `
+		SELECT COUNT(*) FROM interactions
+		WHERE event_id = $1 AND type = 'VOTE'
+		AND jsonb_typeof(payload->'selectedOptions') = 'array'
+		AND jsonb_array_length(payload->'selectedOptions') > 0
+	`
+	if err := r.client.DB.QueryRowContext(ctx, totalQuery, eventID).Scan(&summary.TotalVoters); err != nil {
+		return nil, err
+	}
+
+	votersExpr := `NULL::json`
+	if withVoters {
+		votersExpr = `json_agg(json_build_object(
+			'userId', i.user_id,
+			'userDisplayName', COALESCE(i.user_display_name, ''),
+			'userPictureUrl', COALESCE(i.user_picture_url, '')
+		) ORDER BY i.timestamp)`
+	}
+	tallyQuery := `
+		SELECT opt, COUNT(*), ` + votersExpr + `
+		FROM interactions i
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(i.payload->'selectedOptions') = 'array' THEN i.payload->'selectedOptions' ELSE '[]'::jsonb END
+		) AS opt
+		WHERE i.event_id = $1 AND i.type = 'VOTE'
+		GROUP BY opt
+		ORDER BY opt
+	`
+	rows, err := r.client.DB.QueryContext(ctx, tallyQuery, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tally VoteTally
+		var votersJSON []byte
+		if err := rows.Scan(&tally.OptionID, &tally.Count, &votersJSON); err != nil {
+			return nil, err
+		}
+		if votersJSON != nil {
+			if err := json.Unmarshal(votersJSON, &tally.Voters); err != nil {
+				return nil, err
+			}
+		}
+		summary.Tallies = append(summary.Tallies, tally)
+	}
+
+	return summary, rows.Err()
+}
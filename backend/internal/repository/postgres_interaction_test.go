@@ -0,0 +1,434 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestPostgresRegisterLineUpConcurrent(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+
+	const (
+		attempts        = 300
+		maxParticipants = 40
+		waitlistLimit   = 15
+	)
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{
+		MaxParticipants: maxParticipants,
+		WaitlistLimit:   waitlistLimit,
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.RegisterLineUp(context.Background(), event.EventID, &models.Interaction{
+				UserID:    fmt.Sprintf("user-%d", i),
+				Type:      models.InteractionTypeLineUp,
+				Count:     1,
+				Timestamp: time.Now(),
+			}, false)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	rejected := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrWaitlistFull):
+			rejected++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	records, err := repo.GetByEventID(context.Background(), event.EventID)
+	if err != nil {
+		t.Fatalf("GetByEventID: %v", err)
+	}
+
+	success, waitlist := 0, 0
+	for _, rec := range records {
+		switch rec.Status {
+		case "SUCCESS":
+			success++
+		case "WAITLIST":
+			waitlist++
+		}
+	}
+
+	if success != maxParticipants {
+		t.Errorf("SUCCESS = %d, want %d", success, maxParticipants)
+	}
+	if waitlist != waitlistLimit {
+		t.Errorf("WAITLIST = %d, want %d", waitlist, waitlistLimit)
+	}
+	if rejected != attempts-maxParticipants-waitlistLimit {
+		t.Errorf("rejected = %d, want %d", rejected, attempts-maxParticipants-waitlistLimit)
+	}
+}
+
+func TestPostgresRegisterLineUpPerUserLimit(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 10, MaxCountPerUser: 2})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.RegisterLineUp(context.Background(), event.EventID, &models.Interaction{
+				UserID:    "same-user",
+				Type:      models.InteractionTypeLineUp,
+				Count:     1,
+				Timestamp: time.Now(),
+			}, false)
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrRegistrationLimitReached) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 2 {
+		t.Errorf("succeeded = %d, want 2", succeeded)
+	}
+}
+
+func TestPostgresCancelLineUpPromotesWaitlist(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 2})
+
+	base := time.Now()
+	for i, uid := range []string{"a", "b", "c", "d"} {
+		_, err := repo.RegisterLineUp(ctx, event.EventID, &models.Interaction{
+			UserID:    uid,
+			Type:      models.InteractionTypeLineUp,
+			Count:     1,
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+		}, false)
+		if err != nil {
+			t.Fatalf("register %s: %v", uid, err)
+		}
+	}
+
+	cancelled, promoted, err := repo.CancelLineUp(ctx, event.EventID, "a")
+	if err != nil {
+		t.Fatalf("CancelLineUp: %v", err)
+	}
+	if cancelled.Status != "CANCELLED" || cancelled.CancelledAt == nil {
+		t.Errorf("cancelled = %+v", cancelled)
+	}
+	if len(promoted) != 1 || promoted[0].UserID != "c" || promoted[0].PromotedAt == nil {
+		t.Fatalf("promoted = %+v, want user c with promotedAt", promoted)
+	}
+
+	stored, err := repo.GetByID(ctx, event.EventID, promoted[0].ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Status != "SUCCESS" || stored.PromotedAt == nil {
+		t.Errorf("stored = %+v, want SUCCESS with promotedAt", stored)
+	}
+
+	if _, _, err := repo.CancelLineUp(ctx, event.EventID, "nobody"); !errors.Is(err, ErrNoActiveRegistration) {
+		t.Errorf("err = %v, want ErrNoActiveRegistration", err)
+	}
+}
+
+func TestPostgresReconcileLineUp(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1})
+	base := time.Now()
+	for i, uid := range []string{"a", "b", "c"} {
+		_, err := repo.RegisterLineUp(ctx, event.EventID, &models.Interaction{
+			UserID:    uid,
+			Type:      models.InteractionTypeLineUp,
+			Count:     1,
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+		}, false)
+		if err != nil {
+			t.Fatalf("register %s: %v", uid, err)
+		}
+	}
+
+	preview, err := repo.ReconcileLineUp(ctx, event.EventID, 2, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(preview) != 1 || preview[0].UserID != "b" || preview[0].To != "SUCCESS" {
+		t.Fatalf("preview = %+v, want b -> SUCCESS", preview)
+	}
+
+	records, _ := repo.GetByUserAndType(ctx, event.EventID, "b", models.InteractionTypeLineUp)
+	if records[0].Status != "WAITLIST" {
+		t.Fatalf("dry run wrote status %s", records[0].Status)
+	}
+
+	if _, err := repo.ReconcileLineUp(ctx, event.EventID, 2, false); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	records, _ = repo.GetByUserAndType(ctx, event.EventID, "b", models.InteractionTypeLineUp)
+	if records[0].Status != "SUCCESS" || records[0].PromotedAt == nil {
+		t.Errorf("b = %+v, want SUCCESS with promotedAt", records[0])
+	}
+}
+
+func TestPostgresTallyVotes(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeVote, models.EventConfig{MaxVotes: 2})
+	votes := map[string][]string{"u1": {"x", "y"}, "u2": {"x"}}
+	for uid, selected := range votes {
+		err := repo.CreateWithID(ctx, event.EventID, event.EventID+"_"+uid, &models.Interaction{
+			UserID:          uid,
+			Type:            models.InteractionTypeVote,
+			SelectedOptions: selected,
+			Timestamp:       time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("vote %s: %v", uid, err)
+		}
+	}
+
+	summary, err := repo.TallyVotes(ctx, event.EventID, true)
+	if err != nil {
+		t.Fatalf("TallyVotes: %v", err)
+	}
+	if summary.TotalVoters != 2 {
+		t.Errorf("TotalVoters = %d, want 2", summary.TotalVoters)
+	}
+	if len(summary.Tallies) != 2 || summary.Tallies[0].OptionID != "x" || summary.Tallies[0].Count != 2 || len(summary.Tallies[0].Voters) != 2 {
+		t.Errorf("tallies = %+v", summary.Tallies)
+	}
+
+	summary, err = repo.TallyVotes(ctx, event.EventID, false)
+	if err != nil {
+		t.Fatalf("TallyVotes: %v", err)
+	}
+	if summary.Tallies[0].Voters != nil {
+		t.Errorf("voters loaded without withVoters")
+	}
+}
+
+func TestPostgresRevisions(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeMemo, models.EventConfig{MaxCommentsPerUser: 5})
+	first, err := repo.Create(ctx, event.EventID, &models.Interaction{UserID: "u1", Type: models.InteractionTypeMemo, Content: "one", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	cursor, err := repo.GetRevision(ctx, event.EventID)
+	if err != nil || cursor == 0 {
+		t.Fatalf("GetRevision = %d, %v", cursor, err)
+	}
+
+	if _, err := repo.Create(ctx, event.EventID, &models.Interaction{UserID: "u1", Type: models.InteractionTypeMemo, Content: "two", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Update(ctx, event.EventID, first, map[string]interface{}{"content": "one (edited)"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	changed, err := repo.GetByEventIDSince(ctx, event.EventID, cursor)
+	if err != nil {
+		t.Fatalf("GetByEventIDSince: %v", err)
+	}
+	if len(changed) != 2 || changed[0].Content != "two" || changed[1].Content != "one (edited)" {
+		t.Fatalf("changed = %+v", changed)
+	}
+	if changed[1].Revision <= changed[0].Revision {
+		t.Errorf("revisions not increasing: %d, %d", changed[0].Revision, changed[1].Revision)
+	}
+}
+
+func TestPostgresCancelLineUpRecordAndReorder(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1})
+
+	base := time.Now()
+	ids := make(map[string]string)
+	for i, uid := range []string{"a", "b", "c"} {
+		id, err := repo.RegisterLineUp(ctx, event.EventID, &models.Interaction{
+			UserID:    uid,
+			Type:      models.InteractionTypeLineUp,
+			Count:     1,
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+		}, false)
+		if err != nil {
+			t.Fatalf("register %s: %v", uid, err)
+		}
+		ids[uid] = id
+	}
+
+	if _, err := repo.ReorderLineUp(ctx, event.EventID, []string{ids["c"], ids["a"]}); !errors.Is(err, ErrRosterMismatch) {
+		t.Fatalf("partial reorder = %v, want ErrRosterMismatch", err)
+	}
+
+	changes, err := repo.ReorderLineUp(ctx, event.EventID, []string{ids["c"], ids["a"], ids["b"]})
+	if err != nil {
+		t.Fatalf("ReorderLineUp: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want c promoted and a moved to waitlist", changes)
+	}
+
+	cancelled, promoted, err := repo.CancelLineUpRecord(ctx, event.EventID, ids["c"], "organizer")
+	if err != nil {
+		t.Fatalf("CancelLineUpRecord: %v", err)
+	}
+	if cancelled.CancelledBy != "organizer" {
+		t.Errorf("CancelledBy = %q, want organizer", cancelled.CancelledBy)
+	}
+	if len(promoted) != 1 || promoted[0].UserID != "a" {
+		t.Errorf("promoted = %+v, want a (first after reorder)", promoted)
+	}
+
+	if _, _, err := repo.CancelLineUpRecord(ctx, event.EventID, ids["c"], "organizer"); !errors.Is(err, ErrNoActiveRegistration) {
+		t.Errorf("cancel twice = %v, want ErrNoActiveRegistration", err)
+	}
+}
+
+func TestPostgresUpdateDoesNotDeadlockWithLineUp(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1})
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := repo.RegisterLineUp(ctx, event.EventID, &models.Interaction{
+			UserID:    fmt.Sprintf("user-%d", i),
+			Type:      models.InteractionTypeLineUp,
+			Count:     1,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Second),
+		}, false)
+		if err != nil {
+			t.Fatalf("RegisterLineUp: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Plain updates race reorders that rewrite the same rows; a lock-order
+	// inversion shows up as a deadlock error from one side
+	const rounds = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*rounds)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			errs <- repo.Update(ctx, event.EventID, ids[i%len(ids)], map[string]interface{}{"note": fmt.Sprintf("note %d", i)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			order := []string{ids[(i+1)%3], ids[(i+2)%3], ids[i%3]}
+			_, err := repo.ReorderLineUp(ctx, event.EventID, order)
+			errs <- err
+		}
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestPostgresRegisterLineUpRacesCapacityChange(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresInteractionRepository(client)
+	ctx := context.Background()
+
+	const attempts = 100
+	event := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 50})
+
+	// Capacity drops to 5 while registrations are in flight; whichever side wins the
+	// event lock, no more than 5 registrations may end up SUCCESS
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts+1)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.RegisterLineUp(ctx, event.EventID, &models.Interaction{
+				UserID:    fmt.Sprintf("user-%d", i),
+				Type:      models.InteractionTypeLineUp,
+				Count:     1,
+				Timestamp: time.Now(),
+			}, false)
+			errs <- err
+		}(i)
+		if i == attempts/4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lowered := *event
+				lowered.Config.MaxParticipants = 5
+				_, err := repo.UpdateLineUpEvent(ctx, &lowered)
+				errs <- err
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	records, err := repo.GetByEventID(ctx, event.EventID)
+	if err != nil {
+		t.Fatalf("GetByEventID: %v", err)
+	}
+	success := 0
+	for _, rec := range records {
+		if rec.Status == "SUCCESS" {
+			success++
+		}
+	}
+	if success != 5 {
+		t.Errorf("SUCCESS = %d, want 5", success)
+	}
+}
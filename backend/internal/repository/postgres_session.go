@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// PostgresSessionRepository implements SessionRepository using PostgreSQL
+type PostgresSessionRepository struct {
+	client *PostgresClient
+}
+
+// NewPostgresSessionRepository creates a new PostgresSessionRepository
+func NewPostgresSessionRepository(client *PostgresClient) *PostgresSessionRepository {
+	return &PostgresSessionRepository{client: client}
+}
+
+func (r *PostgresSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, refresh_token_hash, created_at, last_used_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		session.ID, session.UserID, session.RefreshTokenHash, session.CreatedAt, session.LastUsedAt, session.ExpiresAt)
+	return err
+}
+
+func (r *PostgresSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, refresh_token_hash, COALESCE(previous_token_hash, ''),
+		       created_at, last_used_at, expires_at, revoked_at
+		FROM sessions WHERE refresh_token_hash = $1 OR previous_token_hash = $1
+		LIMIT 1
+	`
+	var session models.Session
+	var revokedAt sql.NullTime
+	err := r.client.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID, &session.UserID, &session.RefreshTokenHash, &session.PreviousTokenHash,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return &session, nil
+}
+
+func (r *PostgresSessionRepository) Rotate(ctx context.Context, sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	query := `
+		UPDATE sessions
+		SET refresh_token_hash = $3, previous_token_hash = $2, expires_at = $4, last_used_at = NOW()
+		WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+	result, err := r.client.DB.ExecContext(ctx, query, sessionID, oldHash, newHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	_, err := r.client.DB.ExecContext(ctx, query, sessionID)
+	return err
+}
+
+func (r *PostgresSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.client.DB.ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *PostgresSessionRepository) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM sessions WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		)
+	`
+	var active bool
+	err := r.client.DB.QueryRowContext(ctx, query, sessionID).Scan(&active)
+	return active, err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgresSessionRotation(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresSessionRepository(client)
+	ctx := context.Background()
+
+	userID := "test-" + uuid.New().String()[:8]
+	t.Cleanup(func() { client.DB.Exec(`DELETE FROM sessions WHERE user_id = $1`, userID) })
+
+	now := time.Now()
+	session := &models.Session{
+		ID:               uuid.New().String(),
+		UserID:           userID,
+		RefreshTokenHash: "hash-1-" + userID,
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(time.Hour),
+	}
+	if err := repo.Create(ctx, session); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := repo.Rotate(ctx, session.ID, "hash-1-"+userID, "hash-2-"+userID, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	// Rotating from a stale hash loses the race
+	if err := repo.Rotate(ctx, session.ID, "hash-1-"+userID, "hash-3-"+userID, now.Add(2*time.Hour)); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("stale Rotate: err = %v, want ErrSessionNotFound", err)
+	}
+
+	// The previous hash still finds the session so reuse can be detected
+	got, err := repo.GetByTokenHash(ctx, "hash-1-"+userID)
+	if err != nil || got.ID != session.ID || got.RefreshTokenHash != "hash-2-"+userID || got.PreviousTokenHash != "hash-1-"+userID {
+		t.Fatalf("GetByTokenHash(previous) = %+v, %v", got, err)
+	}
+	if _, err := repo.GetByTokenHash(ctx, "unknown"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("unknown hash: err = %v, want ErrSessionNotFound", err)
+	}
+
+	if active, err := repo.IsActive(ctx, session.ID); err != nil || !active {
+		t.Fatalf("IsActive = %v, %v; want true", active, err)
+	}
+	if err := repo.RevokeAllForUser(ctx, userID); err != nil {
+		t.Fatalf("RevokeAllForUser: %v", err)
+	}
+	if active, err := repo.IsActive(ctx, session.ID); err != nil || active {
+		t.Fatalf("IsActive after revoke = %v, %v; want false", active, err)
+	}
+	got, err = repo.GetByTokenHash(ctx, "hash-2-"+userID)
+	if err != nil || got.RevokedAt == nil {
+		t.Fatalf("revoked session = %+v, %v", got, err)
+	}
+	if err := repo.Rotate(ctx, session.ID, "hash-2-"+userID, "hash-3-"+userID, now.Add(2*time.Hour)); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Rotate after revoke: err = %v, want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// PostgresSigningKeyRepository implements SigningKeyRepository using PostgreSQL
+type PostgresSigningKeyRepository struct {
+	client *PostgresClient
+}
+
+// NewPostgresSigningKeyRepository creates a new PostgresSigningKeyRepository
+func NewPostgresSigningKeyRepository(client *PostgresClient) *PostgresSigningKeyRepository {
+	return &PostgresSigningKeyRepository{client: client}
+}
+
+func (r *PostgresSigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		key.ID, key.Algorithm, key.PrivateKey, key.CreatedAt, key.ExpiresAt)
+	return err
+}
+
+func (r *PostgresSigningKeyRepository) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key, created_at, expires_at
+		FROM signing_keys WHERE expires_at > $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.SigningKey, 0)
+	for rows.Next() {
+		var key models.SigningKey
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.ExpiresAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *PostgresSigningKeyRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.client.DB.ExecContext(ctx, `DELETE FROM signing_keys WHERE expires_at <= $1`, before)
+	return err
+}
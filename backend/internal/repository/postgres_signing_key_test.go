@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgresSigningKeys(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresSigningKeyRepository(client)
+	ctx := context.Background()
+
+	now := time.Now()
+	older := &models.SigningKey{ID: uuid.New().String(), Algorithm: "EdDSA", PrivateKey: []byte{1, 2}, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	newer := &models.SigningKey{ID: uuid.New().String(), Algorithm: "RS256", PrivateKey: []byte{3, 4}, CreatedAt: now, ExpiresAt: now.Add(2 * time.Hour)}
+	expired := &models.SigningKey{ID: uuid.New().String(), Algorithm: "EdDSA", PrivateKey: []byte{5}, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Minute)}
+	for _, key := range []*models.SigningKey{older, newer, expired} {
+		if err := repo.Create(ctx, key); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		id := key.ID
+		t.Cleanup(func() { client.DB.Exec(`DELETE FROM signing_keys WHERE kid = $1`, id) })
+	}
+
+	keys, err := repo.ListUnexpired(ctx, now)
+	if err != nil {
+		t.Fatalf("ListUnexpired: %v", err)
+	}
+	seen := make(map[string]int)
+	for i, key := range keys {
+		seen[key.ID] = i
+	}
+	if _, ok := seen[expired.ID]; ok {
+		t.Fatal("expired key listed")
+	}
+	i, okNewer := seen[newer.ID]
+	j, okOlder := seen[older.ID]
+	if !okNewer || !okOlder || i > j {
+		t.Fatalf("keys not listed newest first: %v", seen)
+	}
+	if string(keys[i].PrivateKey) != string(newer.PrivateKey) || keys[i].Algorithm != "RS256" {
+		t.Fatalf("stored key = %+v", keys[i])
+	}
+
+	if err := repo.DeleteExpired(ctx, now); err != nil {
+		t.Fatalf("DeleteExpired: %v", err)
+	}
+	var count int
+	client.DB.QueryRow(`SELECT COUNT(*) FROM signing_keys WHERE kid = $1`, expired.ID).Scan(&count)
+	if count != 0 {
+		t.Fatal("expired key not deleted")
+	}
+}
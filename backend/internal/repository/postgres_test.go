@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"event-manager/internal/migrate"
+	"event-manager/internal/models"
+	"event-manager/migrations"
+
+	"github.com/google/uuid"
+)
+
+// newTestPostgresClient connects to the database named by TEST_POSTGRES_DSN and
+// applies the pending migrations. Tests are skipped when the variable is not set.
+func newTestPostgresClient(t *testing.T) *PostgresClient {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	runner, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := runner.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return &PostgresClient{DB: db}
+}
+
+// createTestEvent inserts an event and removes it (and its interactions) when the test ends
+func createTestEvent(t *testing.T, client *PostgresClient, eventType models.EventType, config models.EventConfig) *models.Event {
+	t.Helper()
+
+	event := &models.Event{
+		EventID:   uuid.New().String(),
+		Type:      eventType,
+		Title:     "test event",
+		IsActive:  true,
+		CreatedBy: "test",
+		CreatedAt: time.Now(),
+		Config:    config,
+	}
+	if err := NewPostgresEventRepository(client).Create(context.Background(), event); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	t.Cleanup(func() {
+		client.DB.Exec(`DELETE FROM events WHERE event_id = $1`, event.EventID)
+	})
+
+	return event
+}
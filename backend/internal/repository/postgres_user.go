@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"event-manager/internal/models"
 )
@@ -19,24 +20,24 @@ func NewPostgresUserRepository(client *PostgresClient) *PostgresUserRepository {
 
 func (r *PostgresUserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (line_user_id, line_display_name, picture_url, role, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (line_user_id, line_display_name, picture_url, custom_name, role, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
 	`
 	_, err := r.client.DB.ExecContext(ctx, query,
-		user.LineUserID, user.LineDisplayName, user.PictureURL, user.Role, user.CreatedAt)
+		user.LineUserID, user.LineDisplayName, user.PictureURL, user.CustomName, user.Role, user.CreatedAt)
 	return err
 }
 
 func (r *PostgresUserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
 	query := `
-		SELECT line_user_id, line_display_name, picture_url, role, created_at
+		SELECT line_user_id, line_display_name, picture_url, COALESCE(custom_name, ''), role, created_at
 		FROM users WHERE line_user_id = $1
 	`
 	var user models.User
 	var displayName, pictureUrl sql.NullString
 
 	err := r.client.DB.QueryRowContext(ctx, query, userID).Scan(
-		&user.LineUserID, &displayName, &pictureUrl, &user.Role, &user.CreatedAt)
+		&user.LineUserID, &displayName, &pictureUrl, &user.CustomName, &user.Role, &user.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -54,11 +55,11 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, userID string) (*m
 func (r *PostgresUserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users 
-		SET line_display_name = $2, picture_url = $3, role = $4
+		SET line_display_name = $2, picture_url = $3, custom_name = NULLIF($4, ''), role = $5
 		WHERE line_user_id = $1
 	`
 	_, err := r.client.DB.ExecContext(ctx, query,
-		user.LineUserID, user.LineDisplayName, user.PictureURL, user.Role)
+		user.LineUserID, user.LineDisplayName, user.PictureURL, user.CustomName, user.Role)
 	return err
 }
 
@@ -75,6 +76,8 @@ func (r *PostgresUserRepository) UpdateFields(ctx context.Context, userID string
 			user.LineDisplayName = value.(string)
 		case "pictureUrl":
 			user.PictureURL = value.(string)
+		case "customName":
+			user.CustomName = value.(string)
 		case "role":
 			user.Role = value.(string)
 		}
@@ -89,3 +92,131 @@ func (r *PostgresUserRepository) Exists(ctx context.Context, userID string) (boo
 	err := r.client.DB.QueryRowContext(ctx, query, userID).Scan(&exists)
 	return exists, err
 }
+
+func (r *PostgresUserRepository) ListByRole(ctx context.Context, role string) ([]*models.User, error) {
+	query := `
+		SELECT line_user_id, line_display_name, picture_url, COALESCE(custom_name, ''), role, created_at
+		FROM users WHERE role = $1 ORDER BY created_at ASC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var user models.User
+		var displayName, pictureUrl sql.NullString
+		if err := rows.Scan(&user.LineUserID, &displayName, &pictureUrl, &user.CustomName, &user.Role, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		user.LineDisplayName = displayName.String
+		user.PictureURL = pictureUrl.String
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	var count int
+	err := r.client.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE role = $1`, role).Scan(&count)
+	return count, err
+}
+
+// RevokeAdmin locks every admin row so concurrent revocations cannot remove the last admin
+func (r *PostgresUserRepository) RevokeAdmin(ctx context.Context, userID string) error {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT line_user_id FROM users WHERE role = 'admin' FOR UPDATE`)
+	if err != nil {
+		return err
+	}
+	admins := 0
+	isAdmin := false
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		admins++
+		if id == userID {
+			isAdmin = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return ErrNotAdmin
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET role = 'user' WHERE line_user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresUserRepository) GetEventNickname(ctx context.Context, eventID, userID string) (string, error) {
+	query := `SELECT nickname FROM event_nicknames WHERE event_id = $1 AND user_id = $2`
+	var nickname string
+	err := r.client.DB.QueryRowContext(ctx, query, eventID, userID).Scan(&nickname)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return nickname, err
+}
+
+func (r *PostgresUserRepository) SetEventNickname(ctx context.Context, eventID, userID, nickname string) error {
+	if nickname == "" {
+		_, err := r.client.DB.ExecContext(ctx,
+			`DELETE FROM event_nicknames WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+		return err
+	}
+
+	query := `
+		INSERT INTO event_nicknames (event_id, user_id, nickname, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (event_id, user_id) DO UPDATE SET
+			nickname = EXCLUDED.nickname,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.client.DB.ExecContext(ctx, query, eventID, userID, nickname)
+	return err
+}
+
+func (r *PostgresUserRepository) ListEventNicknames(ctx context.Context, userID string) ([]*models.EventNickname, error) {
+	query := `
+		SELECT event_id, user_id, nickname, updated_at
+		FROM event_nicknames WHERE user_id = $1
+		ORDER BY updated_at DESC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nicknames := make([]*models.EventNickname, 0)
+	for rows.Next() {
+		var n models.EventNickname
+		if err := rows.Scan(&n.EventID, &n.UserID, &n.Nickname, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		nicknames = append(nicknames, &n)
+	}
+
+	return nicknames, rows.Err()
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgresRevokeAdmin(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresUserRepository(client)
+	ctx := context.Background()
+
+	// Start from a known set of admins
+	if _, err := client.DB.Exec(`UPDATE users SET role = 'user' WHERE role = 'admin'`); err != nil {
+		t.Fatalf("reset admins: %v", err)
+	}
+
+	ids := []string{"test-" + uuid.New().String()[:8], "test-" + uuid.New().String()[:8]}
+	for _, id := range ids {
+		if err := repo.Create(ctx, &models.User{LineUserID: id, Role: "admin", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		t.Cleanup(func() { client.DB.Exec(`DELETE FROM users WHERE line_user_id = $1`, id) })
+	}
+
+	if n, err := repo.CountByRole(ctx, "admin"); err != nil || n != 2 {
+		t.Fatalf("CountByRole = %d, %v; want 2", n, err)
+	}
+
+	if err := repo.RevokeAdmin(ctx, ids[0]); err != nil {
+		t.Fatalf("RevokeAdmin: %v", err)
+	}
+	if err := repo.RevokeAdmin(ctx, ids[0]); !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("revoke twice = %v, want ErrNotAdmin", err)
+	}
+	if err := repo.RevokeAdmin(ctx, ids[1]); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("revoke last = %v, want ErrLastAdmin", err)
+	}
+
+	admins, err := repo.ListByRole(ctx, "admin")
+	if err != nil || len(admins) != 1 || admins[0].LineUserID != ids[1] {
+		t.Errorf("ListByRole = %+v, %v", admins, err)
+	}
+}
+
+func TestPostgresCustomNameAndNicknames(t *testing.T) {
+	client := newTestPostgresClient(t)
+	repo := NewPostgresUserRepository(client)
+	ctx := context.Background()
+
+	userID := "test-" + uuid.New().String()[:8]
+	if err := repo.Create(ctx, &models.User{LineUserID: userID, LineDisplayName: "LINE Name", Role: "user", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() { client.DB.Exec(`DELETE FROM users WHERE line_user_id = $1`, userID) })
+
+	if err := repo.UpdateFields(ctx, userID, map[string]interface{}{"customName": "Custom"}); err != nil {
+		t.Fatalf("UpdateFields: %v", err)
+	}
+	user, err := repo.GetByID(ctx, userID)
+	if err != nil || user.CustomName != "Custom" || user.DisplayName() != "Custom" {
+		t.Fatalf("GetByID = %+v, %v", user, err)
+	}
+
+	eventID := createTestEvent(t, client, models.EventTypeLineUp, models.EventConfig{MaxParticipants: 1}).EventID
+	if nickname, err := repo.GetEventNickname(ctx, eventID, userID); err != nil || nickname != "" {
+		t.Fatalf("GetEventNickname before set = %q, %v", nickname, err)
+	}
+	for _, nickname := range []string{"First", "Second"} {
+		if err := repo.SetEventNickname(ctx, eventID, userID, nickname); err != nil {
+			t.Fatalf("SetEventNickname(%q): %v", nickname, err)
+		}
+	}
+	if nickname, err := repo.GetEventNickname(ctx, eventID, userID); err != nil || nickname != "Second" {
+		t.Fatalf("GetEventNickname = %q, %v; want Second", nickname, err)
+	}
+	if list, err := repo.ListEventNicknames(ctx, userID); err != nil || len(list) != 1 || list[0].EventID != eventID {
+		t.Fatalf("ListEventNicknames = %+v, %v", list, err)
+	}
+
+	if err := repo.SetEventNickname(ctx, eventID, userID, ""); err != nil {
+		t.Fatalf("clear nickname: %v", err)
+	}
+	if list, err := repo.ListEventNicknames(ctx, userID); err != nil || len(list) != 0 {
+		t.Fatalf("ListEventNicknames after clear = %+v, %v", list, err)
+	}
+}
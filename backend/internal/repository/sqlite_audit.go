@@ -0,0 +1,73 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// SQLiteAuditRepository implements AuditRepository using SQLite
+type SQLiteAuditRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteAuditRepository creates a new SQLiteAuditRepository
+func NewSQLiteAuditRepository(client *SQLiteClient) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{client: client}
+}
+
+func (r *SQLiteAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	now := time.Now()
+	query := `
+		INSERT INTO audit_log (event_id, record_id, actor, action, before, after, created_at)
+		VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?)
+	`
+	result, err := r.client.DB.ExecContext(ctx, query,
+		entry.EventID, entry.RecordID, entry.Actor, entry.Action, nullJSON(entry.Before), nullJSON(entry.After), sqliteTime(now))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	entry.CreatedAt = now
+	return nil
+}
+
+func (r *SQLiteAuditRepository) ListByEvent(ctx context.Context, eventID string, limit int) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, event_id, COALESCE(record_id, ''), actor, action, before, after, created_at
+		FROM audit_log WHERE event_id = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.EventID, &entry.RecordID, &entry.Actor, &entry.Action, &before, &after, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,298 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"event-manager/internal/models"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// sqliteTimeFormat is fixed-width so stored timestamps compare and sort as text
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000000-07:00"
+
+// sqliteTime formats t for a TIMESTAMP column
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// SQLiteClient holds the SQLite connection
+type SQLiteClient struct {
+	DB *sql.DB
+}
+
+// DSN returns the go-sqlite3 data source name for the config. Transactions take the write
+// lock when they begin, which serializes LINEUP changes the way the Postgres row lock does.
+func (cfg *SQLiteConfig) DSN() string {
+	return cfg.Path + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate&_loc=UTC"
+}
+
+// NewSQLiteClient opens the database file, creating it if needed, and applies the schema
+func NewSQLiteClient(cfg *SQLiteConfig) (*SQLiteClient, error) {
+	db, err := sql.Open("sqlite3", cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+	if err := addSQLiteColumn(db, "events", "status_changed_at", "TIMESTAMP"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+
+	return &SQLiteClient{DB: db}, nil
+}
+
+// addSQLiteColumn adds a column that sqliteSchema gained after files were first created
+// with it, since CREATE TABLE IF NOT EXISTS leaves existing tables alone
+func addSQLiteColumn(db *sql.DB, table, column, decl string) error {
+	var exists bool
+	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = ?)`, table, column).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + decl)
+	return err
+}
+
+// Close closes the database connection
+func (c *SQLiteClient) Close() error {
+	if c.DB != nil {
+		return c.DB.Close()
+	}
+	return nil
+}
+
+// SQLiteEventRepository implements EventRepository using SQLite
+type SQLiteEventRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteEventRepository creates a new SQLiteEventRepository
+func NewSQLiteEventRepository(client *SQLiteClient) *SQLiteEventRepository {
+	return &SQLiteEventRepository{client: client}
+}
+
+const sqliteEventColumns = `event_id, type, title, COALESCE(tag, ''), is_active, is_archived, created_by, created_at, config, status_changed_at`
+
+// scanSQLiteEvent reads a row selected with sqliteEventColumns
+func scanSQLiteEvent(row interface{ Scan(...interface{}) error }) (*models.Event, error) {
+	var event models.Event
+	var configJSON string
+	err := row.Scan(&event.EventID, &event.Type, &event.Title, &event.Tag, &event.IsActive, &event.IsArchived, &event.CreatedBy, &event.CreatedAt, &configJSON, &event.StatusChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(configJSON), &event.Config); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// queryEvents runs a query selecting sqliteEventColumns, skipping rows that fail to decode
+func (r *SQLiteEventRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*models.Event, error) {
+	rows, err := r.client.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.Event, 0)
+	for rows.Next() {
+		event, err := scanSQLiteEvent(rows)
+		if err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *SQLiteEventRepository) Create(ctx context.Context, event *models.Event) error {
+	configJSON, err := json.Marshal(event.Config)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO events (event_id, type, title, tag, is_active, created_by, created_at, config)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.client.DB.ExecContext(ctx, query,
+		event.EventID, event.Type, event.Title, event.Tag, event.IsActive, event.CreatedBy, sqliteTime(event.CreatedAt), string(configJSON))
+	return err
+}
+
+func (r *SQLiteEventRepository) GetByID(ctx context.Context, eventID string) (*models.Event, error) {
+	query := `SELECT ` + sqliteEventColumns + ` FROM events WHERE event_id = ?`
+	return scanSQLiteEvent(r.client.DB.QueryRowContext(ctx, query, eventID))
+}
+
+func (r *SQLiteEventRepository) Update(ctx context.Context, event *models.Event) error {
+	return sqliteUpdateEvent(ctx, r.client.DB, event)
+}
+
+// sqliteUpdateEvent writes the event's editable columns
+func sqliteUpdateEvent(ctx context.Context, q sqliteQuerier, event *models.Event) error {
+	configJSON, err := json.Marshal(event.Config)
+	if err != nil {
+		return err
+	}
+
+	var statusChangedAt interface{}
+	if event.StatusChangedAt != nil {
+		statusChangedAt = sqliteTime(*event.StatusChangedAt)
+	}
+
+	query := `UPDATE events SET type = ?, title = ?, tag = ?, is_active = ?, config = ?, status_changed_at = ? WHERE event_id = ?`
+	_, err = q.ExecContext(ctx, query,
+		event.Type, event.Title, event.Tag, event.IsActive, string(configJSON), statusChangedAt, event.EventID)
+	return err
+}
+
+func (r *SQLiteEventRepository) UpdateStatus(ctx context.Context, eventID string, isActive bool) error {
+	_, err := r.client.DB.ExecContext(ctx, `UPDATE events SET is_active = ?, status_changed_at = ? WHERE event_id = ?`,
+		isActive, sqliteTime(time.Now()), eventID)
+	return err
+}
+
+func (r *SQLiteEventRepository) UpdateArchived(ctx context.Context, eventID string, isArchived bool) error {
+	_, err := r.client.DB.ExecContext(ctx, `UPDATE events SET is_archived = ? WHERE event_id = ?`, isArchived, eventID)
+	return err
+}
+
+func (r *SQLiteEventRepository) List(ctx context.Context, limit int) ([]*models.Event, error) {
+	query := `SELECT ` + sqliteEventColumns + ` FROM events ORDER BY created_at DESC LIMIT ?`
+	return r.queryEvents(ctx, query, limit)
+}
+
+// GetByTag returns the most recently created event with the specified tag
+func (r *SQLiteEventRepository) GetByTag(ctx context.Context, tag string) (*models.Event, error) {
+	query := `SELECT ` + sqliteEventColumns + ` FROM events WHERE tag = ? ORDER BY created_at DESC LIMIT 1`
+	return scanSQLiteEvent(r.client.DB.QueryRowContext(ctx, query, tag))
+}
+
+// ListScheduled compares the config times in Go, since SQLite has no timestamp type to
+// cast them to
+func (r *SQLiteEventRepository) ListScheduled(ctx context.Context, from, to time.Time) ([]*models.Event, error) {
+	query := `
+		SELECT ` + sqliteEventColumns + `
+		FROM events
+		WHERE is_archived = 0
+		ORDER BY created_at DESC
+	`
+	unarchived, err := r.queryEvents(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	in := func(t time.Time) bool {
+		return !t.IsZero() && t.After(from) && !t.After(to)
+	}
+	events := make([]*models.Event, 0)
+	for _, event := range unarchived {
+		if in(event.Config.StartTime) || in(event.Config.EndTime) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *SQLiteEventRepository) IsOrganizer(ctx context.Context, eventID, userID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM event_organizers WHERE event_id = ? AND user_id = ?)`
+	err := r.client.DB.QueryRowContext(ctx, query, eventID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *SQLiteEventRepository) ListOrganizers(ctx context.Context, eventID string) ([]*models.EventOrganizer, error) {
+	query := `
+		SELECT o.event_id, o.user_id, COALESCE(NULLIF(u.custom_name, ''), u.line_display_name, ''), o.added_by, o.added_at
+		FROM event_organizers o
+		LEFT JOIN users u ON u.line_user_id = o.user_id
+		WHERE o.event_id = ?
+		ORDER BY o.added_at ASC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizers := make([]*models.EventOrganizer, 0)
+	for rows.Next() {
+		var organizer models.EventOrganizer
+		if err := rows.Scan(&organizer.EventID, &organizer.UserID, &organizer.UserDisplayName, &organizer.AddedBy, &organizer.AddedAt); err != nil {
+			return nil, err
+		}
+		organizers = append(organizers, &organizer)
+	}
+
+	return organizers, rows.Err()
+}
+
+// sqliteAddOrganizer is shared by AddOrganizer and TransferOwnership
+const sqliteAddOrganizer = `
+	INSERT INTO event_organizers (event_id, user_id, added_by, added_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (event_id, user_id) DO NOTHING
+`
+
+func (r *SQLiteEventRepository) AddOrganizer(ctx context.Context, eventID, userID, addedBy string) error {
+	_, err := r.client.DB.ExecContext(ctx, sqliteAddOrganizer, eventID, userID, addedBy, sqliteTime(time.Now()))
+	return err
+}
+
+func (r *SQLiteEventRepository) RemoveOrganizer(ctx context.Context, eventID, userID string) error {
+	result, err := r.client.DB.ExecContext(ctx, `DELETE FROM event_organizers WHERE event_id = ? AND user_id = ?`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrOrganizerNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteEventRepository) TransferOwnership(ctx context.Context, eventID, newOwner, transferredBy string) error {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousOwner string
+	err = tx.QueryRowContext(ctx, `SELECT created_by FROM events WHERE event_id = ?`, eventID).Scan(&previousOwner)
+	if err != nil {
+		return err
+	}
+	if previousOwner == newOwner {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET created_by = ? WHERE event_id = ?`, newOwner, eventID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_organizers WHERE event_id = ? AND user_id = ?`, eventID, newOwner); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, sqliteAddOrganizer, eventID, previousOwner, transferredBy, sqliteTime(time.Now())); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
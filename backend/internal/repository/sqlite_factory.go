@@ -0,0 +1,26 @@
+//go:build sqlite
+
+package repository
+
+import "log"
+
+func newSQLiteRepositories(cfg *Config) (*Repositories, error) {
+	client, err := NewSQLiteClient(&cfg.SQLite)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Repositories] SQLite database opened")
+
+	return &Repositories{
+		Events:       NewSQLiteEventRepository(client),
+		Interactions: NewSQLiteInteractionRepository(client),
+		Users:        NewSQLiteUserRepository(client),
+		Audit:        NewSQLiteAuditRepository(client),
+		Sessions:     NewSQLiteSessionRepository(client),
+		SigningKeys:  NewSQLiteSigningKeyRepository(client),
+		Close: func() error {
+			return client.Close()
+		},
+	}, nil
+}
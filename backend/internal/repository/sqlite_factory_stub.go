@@ -0,0 +1,11 @@
+//go:build !sqlite
+
+package repository
+
+import "errors"
+
+// newSQLiteRepositories reports that the binary was built without SQLite, whose
+// driver needs cgo; build with -tags sqlite (and CGO_ENABLED=1) to enable it
+func newSQLiteRepositories(cfg *Config) (*Repositories, error) {
+	return nil, errors.New("DB_TYPE=sqlite requires a build with -tags sqlite")
+}
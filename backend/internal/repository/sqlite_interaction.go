@@ -0,0 +1,546 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"event-manager/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// SQLiteInteractionRepository implements InteractionRepository using SQLite. Payloads are
+// stored as JSON text; LINEUP changes are planned in Go inside a write transaction.
+type SQLiteInteractionRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteInteractionRepository creates a new SQLiteInteractionRepository
+func NewSQLiteInteractionRepository(client *SQLiteClient) *SQLiteInteractionRepository {
+	return &SQLiteInteractionRepository{client: client}
+}
+
+const sqliteInteractionColumns = `id, user_id, type, COALESCE(user_display_name, ''), COALESCE(user_picture_url, ''), COALESCE(status, ''), timestamp, payload, revision`
+
+// sqliteQuerier is satisfied by both *sql.DB and *sql.Tx
+type sqliteQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// scanSQLiteInteraction reads a row selected with sqliteInteractionColumns
+func scanSQLiteInteraction(row interface{ Scan(...interface{}) error }) (*models.Interaction, error) {
+	var interaction models.Interaction
+	var payloadJSON string
+	err := row.Scan(&interaction.ID, &interaction.UserID, &interaction.Type, &interaction.UserDisplayName,
+		&interaction.UserPictureUrl, &interaction.Status, &interaction.Timestamp, &payloadJSON, &interaction.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload interactionPayload
+	if err := json.Unmarshal([]byte(payloadJSON), &payload); err != nil {
+		return nil, err
+	}
+	payload.applyTo(&interaction)
+
+	return &interaction, nil
+}
+
+// queryInteractions runs a query selecting sqliteInteractionColumns, skipping rows that
+// fail to decode
+func queryInteractions(ctx context.Context, q sqliteQuerier, query string, args ...interface{}) ([]*models.Interaction, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interactions := make([]*models.Interaction, 0)
+	for rows.Next() {
+		interaction, err := scanSQLiteInteraction(rows)
+		if err != nil {
+			continue
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, rows.Err()
+}
+
+// insertInteraction stores a new interaction under id; the insert trigger sets its revision
+func insertInteraction(ctx context.Context, q sqliteQuerier, eventID, id string, interaction *models.Interaction) error {
+	payloadJSON, err := json.Marshal(newInteractionPayload(interaction))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO interactions (id, event_id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = q.ExecContext(ctx, query,
+		id, eventID, interaction.UserID, interaction.Type, interaction.UserDisplayName,
+		interaction.UserPictureUrl, interaction.Status, sqliteTime(interaction.Timestamp), string(payloadJSON))
+	return err
+}
+
+// putInteraction writes back the status, timestamp and payload of a stored interaction
+// and refreshes its revision, which the update trigger has advanced
+func putInteraction(ctx context.Context, tx *sql.Tx, eventID string, interaction *models.Interaction) error {
+	payloadJSON, err := json.Marshal(newInteractionPayload(interaction))
+	if err != nil {
+		return err
+	}
+
+	update := `UPDATE interactions SET status = ?, timestamp = ?, payload = ? WHERE event_id = ? AND id = ?`
+	if _, err := tx.ExecContext(ctx, update,
+		interaction.Status, sqliteTime(interaction.Timestamp), string(payloadJSON), eventID, interaction.ID); err != nil {
+		return err
+	}
+	return tx.QueryRowContext(ctx, `SELECT revision FROM interactions WHERE id = ?`, interaction.ID).Scan(&interaction.Revision)
+}
+
+func (r *SQLiteInteractionRepository) Create(ctx context.Context, eventID string, interaction *models.Interaction) (string, error) {
+	id := uuid.New().String()
+	if err := insertInteraction(ctx, r.client.DB, eventID, id, interaction); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteInteractionRepository) CreateWithID(ctx context.Context, eventID, recordID string, interaction *models.Interaction) error {
+	payloadJSON, err := json.Marshal(newInteractionPayload(interaction))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO interactions (id, event_id, user_id, type, user_display_name, user_picture_url, status, timestamp, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_display_name = excluded.user_display_name,
+			user_picture_url = excluded.user_picture_url,
+			status = excluded.status,
+			timestamp = excluded.timestamp,
+			payload = excluded.payload
+	`
+	_, err = r.client.DB.ExecContext(ctx, query,
+		recordID, eventID, interaction.UserID, interaction.Type, interaction.UserDisplayName,
+		interaction.UserPictureUrl, interaction.Status, sqliteTime(interaction.Timestamp), string(payloadJSON))
+	return err
+}
+
+func (r *SQLiteInteractionRepository) GetByEventID(ctx context.Context, eventID string) ([]*models.Interaction, error) {
+	query := `SELECT ` + sqliteInteractionColumns + ` FROM interactions WHERE event_id = ? ORDER BY timestamp ASC, id ASC`
+	return queryInteractions(ctx, r.client.DB, query, eventID)
+}
+
+// GetByEventIDSince returns interactions created or modified after the given event revision
+func (r *SQLiteInteractionRepository) GetByEventIDSince(ctx context.Context, eventID string, since int64) ([]*models.Interaction, error) {
+	query := `SELECT ` + sqliteInteractionColumns + ` FROM interactions WHERE event_id = ? AND revision > ? ORDER BY revision ASC`
+	return queryInteractions(ctx, r.client.DB, query, eventID, since)
+}
+
+// GetRevision returns the event's current revision, bumped by a trigger on every interaction write
+func (r *SQLiteInteractionRepository) GetRevision(ctx context.Context, eventID string) (int64, error) {
+	var revision int64
+	err := r.client.DB.QueryRowContext(ctx, `SELECT revision FROM events WHERE event_id = ?`, eventID).Scan(&revision)
+	return revision, err
+}
+
+func (r *SQLiteInteractionRepository) GetByUserAndType(ctx context.Context, eventID, userID string, iType models.InteractionType) ([]*models.Interaction, error) {
+	query := `
+		SELECT ` + sqliteInteractionColumns + `
+		FROM interactions WHERE event_id = ? AND user_id = ? AND type = ? ORDER BY timestamp ASC, id ASC
+	`
+	return queryInteractions(ctx, r.client.DB, query, eventID, userID, iType)
+}
+
+func (r *SQLiteInteractionRepository) GetByID(ctx context.Context, eventID, recordID string) (*models.Interaction, error) {
+	query := `SELECT ` + sqliteInteractionColumns + ` FROM interactions WHERE event_id = ? AND id = ?`
+	return scanSQLiteInteraction(r.client.DB.QueryRowContext(ctx, query, eventID, recordID))
+}
+
+func (r *SQLiteInteractionRepository) Update(ctx context.Context, eventID, recordID string, updates map[string]interface{}) error {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + sqliteInteractionColumns + ` FROM interactions WHERE event_id = ? AND id = ?`
+	current, err := scanSQLiteInteraction(tx.QueryRowContext(ctx, query, eventID, recordID))
+	if err != nil {
+		return err
+	}
+
+	applyInteractionUpdates(current, updates)
+	if err := putInteraction(ctx, tx, eventID, current); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteInteractionRepository) Delete(ctx context.Context, eventID, recordID string) error {
+	_, err := r.client.DB.ExecContext(ctx, `DELETE FROM interactions WHERE event_id = ? AND id = ?`, eventID, recordID)
+	return err
+}
+
+// beginLineUp starts a write transaction for a LINEUP change. The transaction holds the
+// database write lock from BEGIN (see SQLiteConfig.DSN), so changes to one event are
+// serialized, and the returned event config cannot change before it ends. It fails with
+// sql.ErrNoRows when the event does not exist.
+func (r *SQLiteInteractionRepository) beginLineUp(ctx context.Context, eventID string) (*sql.Tx, models.EventConfig, error) {
+	var config models.EventConfig
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, config, err
+	}
+
+	var configJSON string
+	if err := tx.QueryRowContext(ctx, `SELECT config FROM events WHERE event_id = ?`, eventID).Scan(&configJSON); err != nil {
+		tx.Rollback()
+		return nil, config, err
+	}
+	if configJSON != "" {
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			tx.Rollback()
+			return nil, config, err
+		}
+	}
+	return tx, config, nil
+}
+
+// lineUp returns all of the event's LINEUP records in queue order
+func (r *SQLiteInteractionRepository) lineUp(ctx context.Context, tx *sql.Tx, eventID string) ([]*models.Interaction, error) {
+	query := `
+		SELECT ` + sqliteInteractionColumns + `
+		FROM interactions WHERE event_id = ? AND type = 'LINEUP'
+		ORDER BY timestamp ASC, id ASC
+	`
+	records, err := queryInteractions(ctx, tx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	SortLineUpQueue(records)
+	return records, nil
+}
+
+// activeLineUp returns the non-cancelled LINEUP records in queue order
+func (r *SQLiteInteractionRepository) activeLineUp(ctx context.Context, tx *sql.Tx, eventID string) ([]*models.Interaction, error) {
+	records, err := r.lineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*models.Interaction, 0, len(records))
+	for _, rec := range records {
+		if rec.Status != "CANCELLED" {
+			active = append(active, rec)
+		}
+	}
+	return active, nil
+}
+
+// RegisterLineUp counts active records and inserts within one write transaction
+func (r *SQLiteInteractionRepository) RegisterLineUp(ctx context.Context, eventID string, interaction *models.Interaction, exemptUserLimit bool) (string, error) {
+	tx, config, err := r.beginLineUp(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	limits := LineUpLimitsOf(config)
+	if exemptUserLimit {
+		limits.MaxCountPerUser = 0
+	}
+
+	active, err := r.activeLineUp(ctx, tx, eventID)
+	if err != nil {
+		return "", err
+	}
+
+	var waitlistCount, userActive int
+	for _, other := range active {
+		if other.Status == "WAITLIST" {
+			waitlistCount++
+		}
+		if other.UserID == interaction.UserID {
+			userActive++
+		}
+	}
+
+	if limits.MaxCountPerUser > 0 && userActive >= limits.MaxCountPerUser {
+		return "", ErrRegistrationLimitReached
+	}
+
+	if len(active) >= limits.MaxParticipants {
+		if limits.WaitlistLimit > 0 && waitlistCount >= limits.WaitlistLimit {
+			return "", ErrWaitlistFull
+		}
+		interaction.Status = "WAITLIST"
+	} else {
+		interaction.Status = "SUCCESS"
+	}
+
+	id := uuid.New().String()
+	if err := insertInteraction(ctx, tx, eventID, id, interaction); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CancelLineUp cancels the user's latest active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seats, all within one transaction.
+func (r *SQLiteInteractionRepository) CancelLineUp(ctx context.Context, eventID, userID string) (*models.Interaction, []*models.Interaction, error) {
+	return r.cancelLineUp(ctx, eventID, userID, func(records []*models.Interaction) *models.Interaction {
+		// Latest by registration time, not queue position
+		var latest *models.Interaction
+		for _, rec := range records {
+			if rec.UserID == userID && rec.Status != "CANCELLED" && (latest == nil || !rec.Timestamp.Before(latest.Timestamp)) {
+				latest = rec
+			}
+		}
+		return latest
+	})
+}
+
+// CancelLineUpRecord cancels a specific active LINEUP registration and promotes the
+// oldest WAITLIST registrations into the freed seat, all within one transaction.
+func (r *SQLiteInteractionRepository) CancelLineUpRecord(ctx context.Context, eventID, recordID, cancelledBy string) (*models.Interaction, []*models.Interaction, error) {
+	return r.cancelLineUp(ctx, eventID, cancelledBy, func(records []*models.Interaction) *models.Interaction {
+		for _, rec := range records {
+			if rec.ID == recordID && rec.Status != "CANCELLED" {
+				return rec
+			}
+		}
+		return nil
+	})
+}
+
+// cancelLineUp cancels the registration picked from the event's LINEUP records and fills
+// freed seats from the waitlist
+func (r *SQLiteInteractionRepository) cancelLineUp(ctx context.Context, eventID, cancelledBy string, pick func([]*models.Interaction) *models.Interaction) (*models.Interaction, []*models.Interaction, error) {
+	tx, config, err := r.beginLineUp(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	records, err := r.lineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cancelled := pick(records)
+	if cancelled == nil {
+		return nil, nil, ErrNoActiveRegistration
+	}
+
+	now := time.Now()
+	cancelled.Status = "CANCELLED"
+	cancelled.CancelledAt = &now
+	cancelled.CancelledBy = cancelledBy
+	if err := putInteraction(ctx, tx, eventID, cancelled); err != nil {
+		return nil, nil, err
+	}
+
+	success := 0
+	for _, rec := range records {
+		if rec.Status == "SUCCESS" {
+			success++
+		}
+	}
+	promoted := make([]*models.Interaction, 0)
+	for _, rec := range records {
+		if success >= config.MaxParticipants {
+			break
+		}
+		if rec.Status != "WAITLIST" {
+			continue
+		}
+		rec.Status = "SUCCESS"
+		rec.PromotedAt = &now
+		if err := putInteraction(ctx, tx, eventID, rec); err != nil {
+			return nil, nil, err
+		}
+		success++
+		promoted = append(promoted, rec)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return cancelled, promoted, nil
+}
+
+// ReconcileLineUp plans status changes for all active LINEUP registrations within one
+// transaction and applies them unless dryRun is set
+func (r *SQLiteInteractionRepository) ReconcileLineUp(ctx context.Context, eventID string, maxParticipants int, dryRun bool) ([]LineUpStatusChange, error) {
+	tx, _, err := r.beginLineUp(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	active, err := r.activeLineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := PlanLineUpStatuses(active, maxParticipants)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err := putChanged(ctx, tx, eventID, active, applyLineUpChanges(active, changes, time.Now())); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// UpdateLineUpEvent saves the event and applies the status changes its new capacity
+// requires, all within one write transaction
+func (r *SQLiteInteractionRepository) UpdateLineUpEvent(ctx context.Context, event *models.Event) ([]LineUpStatusChange, error) {
+	tx, _, err := r.beginLineUp(ctx, event.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := sqliteUpdateEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	active, err := r.activeLineUp(ctx, tx, event.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := PlanLineUpStatuses(active, event.Config.MaxParticipants)
+	if err := putChanged(ctx, tx, event.EventID, active, applyLineUpChanges(active, changes, time.Now())); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ReorderLineUp moves the active LINEUP registrations into the given order by setting
+// their queuedAt, then re-applies capacity, all within one transaction
+func (r *SQLiteInteractionRepository) ReorderLineUp(ctx context.Context, eventID string, recordIDs []string) ([]LineUpStatusChange, error) {
+	tx, config, err := r.beginLineUp(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	active, err := r.activeLineUp(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := PlanLineUpOrder(active, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, rec := range active {
+		if at := queued[rec.ID]; !at.Equal(rec.QueueTime()) {
+			rec.QueuedAt = &at
+			changed[rec.ID] = true
+		}
+	}
+	changes := PlanLineUpStatuses(active, config.MaxParticipants)
+	for id := range applyLineUpChanges(active, changes, time.Now()) {
+		changed[id] = true
+	}
+	if err := putChanged(ctx, tx, eventID, active, changed); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// putChanged writes the records whose IDs are in changed, once each
+func putChanged(ctx context.Context, tx *sql.Tx, eventID string, records []*models.Interaction, changed map[string]bool) error {
+	for _, rec := range records {
+		if !changed[rec.ID] {
+			continue
+		}
+		if err := putInteraction(ctx, tx, eventID, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteInteractionRepository) TallyVotes(ctx context.Context, eventID string, withVoters bool) (*VoteSummary, error) {
+	summary := &VoteSummary{Tallies: make([]VoteTally, 0)}
+
+	totalQuery := `
+		SELECT COUNT(*) FROM interactions
+		WHERE event_id = ? AND type = 'VOTE'
+		AND json_type(payload, '$.selectedOptions') = 'array'
+		AND json_array_length(payload, '$.selectedOptions') > 0
+	`
+	if err := r.client.DB.QueryRowContext(ctx, totalQuery, eventID).Scan(&summary.TotalVoters); err != nil {
+		return nil, err
+	}
+
+	// One row per selected option; rows are grouped into tallies below
+	tallyQuery := `
+		SELECT opt.value, i.user_id, COALESCE(i.user_display_name, ''), COALESCE(i.user_picture_url, '')
+		FROM interactions i, json_each(i.payload, '$.selectedOptions') AS opt
+		WHERE i.event_id = ? AND i.type = 'VOTE'
+		AND json_type(i.payload, '$.selectedOptions') = 'array'
+		ORDER BY opt.value, i.timestamp, i.id
+	`
+	rows, err := r.client.DB.QueryContext(ctx, tallyQuery, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var option string
+		var voter Voter
+		if err := rows.Scan(&option, &voter.UserID, &voter.UserDisplayName, &voter.UserPictureUrl); err != nil {
+			return nil, err
+		}
+		last := len(summary.Tallies) - 1
+		if last < 0 || summary.Tallies[last].OptionID != option {
+			summary.Tallies = append(summary.Tallies, VoteTally{OptionID: option})
+			last++
+		}
+		summary.Tallies[last].Count++
+		if withVoters {
+			summary.Tallies[last].Voters = append(summary.Tallies[last].Voters, voter)
+		}
+	}
+
+	return summary, rows.Err()
+}
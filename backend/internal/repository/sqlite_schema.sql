@@ -0,0 +1,121 @@
+-- Event Manager SQLite schema, applied by NewSQLiteClient every time a database is opened.
+-- It mirrors the PostgreSQL migrations in migrations/. JSON columns hold text, and timestamps
+-- are fixed-width UTC text written by sqliteTime so they sort and compare in time order.
+
+CREATE TABLE IF NOT EXISTS events (
+    event_id        TEXT PRIMARY KEY,
+    type            TEXT NOT NULL CHECK (type IN ('VOTE', 'LINEUP', 'MEMO')),
+    title           TEXT NOT NULL,
+    tag             TEXT,
+    is_active       BOOLEAN NOT NULL DEFAULT 1,
+    is_archived     BOOLEAN NOT NULL DEFAULT 0,
+    created_by      TEXT NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    config          TEXT NOT NULL DEFAULT '{}',
+    revision        INTEGER NOT NULL DEFAULT 0,
+    status_changed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events(created_at);
+CREATE INDEX IF NOT EXISTS idx_events_tag ON events(tag, created_at);
+
+CREATE TABLE IF NOT EXISTS interactions (
+    id                  TEXT PRIMARY KEY,
+    event_id            TEXT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id             TEXT NOT NULL,
+    type                TEXT NOT NULL CHECK (type IN ('VOTE', 'LINEUP', 'MEMO')),
+    user_display_name   TEXT,
+    user_picture_url    TEXT,
+    status              TEXT,
+    timestamp           TIMESTAMP NOT NULL,
+    payload             TEXT NOT NULL DEFAULT '{}',
+    revision            INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_interactions_event_timestamp ON interactions(event_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_interactions_event_user_type ON interactions(event_id, user_id, type);
+CREATE INDEX IF NOT EXISTS idx_interactions_event_revision ON interactions(event_id, revision);
+
+-- Every interaction write advances its event's revision and stamps it on the row, like
+-- the Postgres trigger. The update trigger ignores writes to revision itself.
+CREATE TRIGGER IF NOT EXISTS interactions_revision_insert
+    AFTER INSERT ON interactions
+BEGIN
+    UPDATE events SET revision = revision + 1 WHERE event_id = NEW.event_id;
+    UPDATE interactions SET revision = (SELECT revision FROM events WHERE event_id = NEW.event_id)
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS interactions_revision_update
+    AFTER UPDATE OF user_display_name, user_picture_url, status, timestamp, payload ON interactions
+BEGIN
+    UPDATE events SET revision = revision + 1 WHERE event_id = NEW.event_id;
+    UPDATE interactions SET revision = (SELECT revision FROM events WHERE event_id = NEW.event_id)
+    WHERE id = NEW.id;
+END;
+
+CREATE TABLE IF NOT EXISTS users (
+    line_user_id        TEXT PRIMARY KEY,
+    line_display_name   TEXT,
+    picture_url         TEXT,
+    custom_name         TEXT,
+    role                TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    created_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+
+CREATE TABLE IF NOT EXISTS event_organizers (
+    event_id        TEXT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL,
+    added_by        TEXT NOT NULL,
+    added_at        TIMESTAMP NOT NULL,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_organizers_user ON event_organizers(user_id);
+
+CREATE TABLE IF NOT EXISTS event_nicknames (
+    event_id        TEXT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL,
+    nickname        TEXT NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_nicknames_user ON event_nicknames(user_id);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id        TEXT NOT NULL,
+    record_id       TEXT,
+    actor           TEXT NOT NULL,
+    action          TEXT NOT NULL,
+    before          TEXT,
+    after           TEXT,
+    created_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_event_created ON audit_log(event_id, created_at);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id                  TEXT PRIMARY KEY,
+    user_id             TEXT NOT NULL,
+    refresh_token_hash  TEXT NOT NULL UNIQUE,
+    previous_token_hash TEXT,
+    created_at          TIMESTAMP NOT NULL,
+    last_used_at        TIMESTAMP NOT NULL,
+    expires_at          TIMESTAMP NOT NULL,
+    revoked_at          TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_token ON sessions(previous_token_hash);
+
+CREATE TABLE IF NOT EXISTS signing_keys (
+    kid             TEXT PRIMARY KEY,
+    algorithm       TEXT NOT NULL CHECK (algorithm IN ('RS256', 'EdDSA')),
+    private_key     BLOB NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    expires_at      TIMESTAMP NOT NULL
+);
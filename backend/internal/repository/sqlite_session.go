@@ -0,0 +1,101 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// SQLiteSessionRepository implements SessionRepository using SQLite
+type SQLiteSessionRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteSessionRepository creates a new SQLiteSessionRepository
+func NewSQLiteSessionRepository(client *SQLiteClient) *SQLiteSessionRepository {
+	return &SQLiteSessionRepository{client: client}
+}
+
+func (r *SQLiteSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, refresh_token_hash, created_at, last_used_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		session.ID, session.UserID, session.RefreshTokenHash,
+		sqliteTime(session.CreatedAt), sqliteTime(session.LastUsedAt), sqliteTime(session.ExpiresAt))
+	return err
+}
+
+func (r *SQLiteSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, refresh_token_hash, COALESCE(previous_token_hash, ''),
+		       created_at, last_used_at, expires_at, revoked_at
+		FROM sessions WHERE refresh_token_hash = ? OR previous_token_hash = ?
+		LIMIT 1
+	`
+	var session models.Session
+	var revokedAt sql.NullTime
+	err := r.client.DB.QueryRowContext(ctx, query, tokenHash, tokenHash).Scan(
+		&session.ID, &session.UserID, &session.RefreshTokenHash, &session.PreviousTokenHash,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return &session, nil
+}
+
+func (r *SQLiteSessionRepository) Rotate(ctx context.Context, sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	now := sqliteTime(time.Now())
+	query := `
+		UPDATE sessions
+		SET refresh_token_hash = ?, previous_token_hash = ?, expires_at = ?, last_used_at = ?
+		WHERE id = ? AND refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?
+	`
+	result, err := r.client.DB.ExecContext(ctx, query, newHash, oldHash, sqliteTime(expiresAt), now, sessionID, oldHash, now)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteSessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	_, err := r.client.DB.ExecContext(ctx, query, sqliteTime(time.Now()), sessionID)
+	return err
+}
+
+func (r *SQLiteSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := r.client.DB.ExecContext(ctx, query, sqliteTime(time.Now()), userID)
+	return err
+}
+
+func (r *SQLiteSessionRepository) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM sessions WHERE id = ? AND revoked_at IS NULL AND expires_at > ?
+		)
+	`
+	var active bool
+	err := r.client.DB.QueryRowContext(ctx, query, sessionID, sqliteTime(time.Now())).Scan(&active)
+	return active, err
+}
@@ -0,0 +1,59 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// SQLiteSigningKeyRepository implements SigningKeyRepository using SQLite
+type SQLiteSigningKeyRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteSigningKeyRepository creates a new SQLiteSigningKeyRepository
+func NewSQLiteSigningKeyRepository(client *SQLiteClient) *SQLiteSigningKeyRepository {
+	return &SQLiteSigningKeyRepository{client: client}
+}
+
+func (r *SQLiteSigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		key.ID, key.Algorithm, key.PrivateKey, sqliteTime(key.CreatedAt), sqliteTime(key.ExpiresAt))
+	return err
+}
+
+func (r *SQLiteSigningKeyRepository) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key, created_at, expires_at
+		FROM signing_keys WHERE expires_at > ?
+		ORDER BY created_at DESC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, sqliteTime(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.SigningKey, 0)
+	for rows.Next() {
+		var key models.SigningKey
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.ExpiresAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *SQLiteSigningKeyRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.client.DB.ExecContext(ctx, `DELETE FROM signing_keys WHERE expires_at <= ?`, sqliteTime(before))
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build !sqlite
+
+package repository
+
+import "testing"
+
+// openSQLiteContract skips the SQLite contract run in builds without the sqlite tag
+func openSQLiteContract(t *testing.T) *contractEnv {
+	t.Skip("built without -tags sqlite")
+	return nil
+}
@@ -0,0 +1,145 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// newTestSQLiteClient opens a fresh database file in the test's temporary directory
+func newTestSQLiteClient(t *testing.T) *SQLiteClient {
+	t.Helper()
+
+	client, err := NewSQLiteClient(&SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func openSQLiteContract(t *testing.T) *contractEnv {
+	client := newTestSQLiteClient(t)
+	return &contractEnv{
+		Repositories: &Repositories{
+			Events:       NewSQLiteEventRepository(client),
+			Interactions: NewSQLiteInteractionRepository(client),
+			Users:        NewSQLiteUserRepository(client),
+			Audit:        NewSQLiteAuditRepository(client),
+			Sessions:     NewSQLiteSessionRepository(client),
+			SigningKeys:  NewSQLiteSigningKeyRepository(client),
+			Close:        func() error { return nil },
+		},
+		prefix: newContractPrefix(),
+		corruptPayload: func(t *testing.T, eventID, recordID string) {
+			_, err := client.DB.Exec(`UPDATE interactions SET payload = '"not an object"' WHERE id = ?`, recordID)
+			if err != nil {
+				t.Fatalf("corrupt payload: %v", err)
+			}
+		},
+	}
+}
+
+func TestNewRepositoriesSelectsSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "eventmanager.db")
+	t.Setenv("DB_TYPE", DBTypeSQLite)
+	t.Setenv("SQLITE_PATH", path)
+
+	repos, err := NewRepositories(LoadConfigFromEnv())
+	if err != nil {
+		t.Fatalf("NewRepositories: %v", err)
+	}
+	if _, ok := repos.Events.(*SQLiteEventRepository); !ok {
+		t.Fatalf("Events is %T, want *SQLiteEventRepository", repos.Events)
+	}
+	event := &models.Event{EventID: "ev1", Type: models.EventTypeMemo, Title: "kept", CreatedAt: time.Now()}
+	if err := repos.Events.Create(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening applies the schema again and keeps the data
+	client, err := NewSQLiteClient(&SQLiteConfig{Path: path})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer client.Close()
+	got, err := NewSQLiteEventRepository(client).GetByID(context.Background(), "ev1")
+	if err != nil || got.Title != "kept" {
+		t.Fatalf("after reopen: %+v, %v", got, err)
+	}
+}
+
+func TestSQLiteTimestampsSortAsText(t *testing.T) {
+	// A later time must format to a larger string, whatever its zone or precision
+	earlier := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := time.Date(2030, 1, 2, 12, 4, 5, 100, time.FixedZone("JST", 9*3600))
+	if !later.After(earlier) || sqliteTime(later) <= sqliteTime(earlier) {
+		t.Fatalf("%s does not sort after %s", sqliteTime(later), sqliteTime(earlier))
+	}
+}
+
+func TestSQLiteRegisterLineUpConcurrent(t *testing.T) {
+	client := newTestSQLiteClient(t)
+	events := NewSQLiteEventRepository(client)
+	repo := NewSQLiteInteractionRepository(client)
+	ctx := context.Background()
+	if err := events.Create(ctx, &models.Event{
+		EventID:   "ev1",
+		Type:      models.EventTypeLineUp,
+		CreatedAt: time.Now(),
+		Config:    models.EventConfig{MaxParticipants: 10, WaitlistLimit: 5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.RegisterLineUp(ctx, "ev1", &models.Interaction{
+				UserID:    fmt.Sprintf("u%d", i),
+				Type:      models.InteractionTypeLineUp,
+				Count:     1,
+				Timestamp: time.Now(),
+			}, false)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil && !errors.Is(err, ErrWaitlistFull) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	records, err := repo.GetByEventID(ctx, "ev1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, rec := range records {
+		counts[rec.Status]++
+	}
+	if counts["SUCCESS"] != 10 || counts["WAITLIST"] != 5 || len(records) != 15 {
+		t.Fatalf("statuses = %v (%d records), want 10 SUCCESS and 5 WAITLIST", counts, len(records))
+	}
+	if revision, _ := repo.GetRevision(ctx, "ev1"); revision != 15 {
+		t.Fatalf("revision = %d, want 15", revision)
+	}
+}
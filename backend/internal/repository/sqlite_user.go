@@ -0,0 +1,196 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// SQLiteUserRepository implements UserRepository using SQLite
+type SQLiteUserRepository struct {
+	client *SQLiteClient
+}
+
+// NewSQLiteUserRepository creates a new SQLiteUserRepository
+func NewSQLiteUserRepository(client *SQLiteClient) *SQLiteUserRepository {
+	return &SQLiteUserRepository{client: client}
+}
+
+const sqliteUserColumns = `line_user_id, COALESCE(line_display_name, ''), COALESCE(picture_url, ''), COALESCE(custom_name, ''), role, created_at`
+
+// scanSQLiteUser reads a row selected with sqliteUserColumns
+func scanSQLiteUser(row interface{ Scan(...interface{}) error }) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.LineUserID, &user.LineDisplayName, &user.PictureURL, &user.CustomName, &user.Role, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) Create(ctx context.Context, user *models.User) error {
+	query := `
+		INSERT INTO users (line_user_id, line_display_name, picture_url, custom_name, role, created_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?)
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		user.LineUserID, user.LineDisplayName, user.PictureURL, user.CustomName, user.Role, sqliteTime(user.CreatedAt))
+	return err
+}
+
+func (r *SQLiteUserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	query := `SELECT ` + sqliteUserColumns + ` FROM users WHERE line_user_id = ?`
+	return scanSQLiteUser(r.client.DB.QueryRowContext(ctx, query, userID))
+}
+
+func (r *SQLiteUserRepository) Update(ctx context.Context, user *models.User) error {
+	query := `
+		UPDATE users
+		SET line_display_name = ?, picture_url = ?, custom_name = NULLIF(?, ''), role = ?
+		WHERE line_user_id = ?
+	`
+	_, err := r.client.DB.ExecContext(ctx, query,
+		user.LineDisplayName, user.PictureURL, user.CustomName, user.Role, user.LineUserID)
+	return err
+}
+
+func (r *SQLiteUserRepository) UpdateFields(ctx context.Context, userID string, updates map[string]interface{}) error {
+	user, err := r.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range updates {
+		switch key {
+		case "lineDisplayName":
+			user.LineDisplayName = value.(string)
+		case "pictureUrl":
+			user.PictureURL = value.(string)
+		case "customName":
+			user.CustomName = value.(string)
+		case "role":
+			user.Role = value.(string)
+		}
+	}
+
+	return r.Update(ctx, user)
+}
+
+func (r *SQLiteUserRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.client.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE line_user_id = ?)`, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *SQLiteUserRepository) ListByRole(ctx context.Context, role string) ([]*models.User, error) {
+	query := `SELECT ` + sqliteUserColumns + ` FROM users WHERE role = ? ORDER BY created_at ASC`
+	rows, err := r.client.DB.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		user, err := scanSQLiteUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *SQLiteUserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	var count int
+	err := r.client.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE role = ?`, role).Scan(&count)
+	return count, err
+}
+
+// RevokeAdmin counts admins inside a write transaction so concurrent revocations cannot
+// remove the last admin
+func (r *SQLiteUserRepository) RevokeAdmin(ctx context.Context, userID string) error {
+	tx, err := r.client.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var admins int
+	var isAdmin bool
+	query := `SELECT COUNT(*), COALESCE(SUM(line_user_id = ?), 0) > 0 FROM users WHERE role = 'admin'`
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&admins, &isAdmin); err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return ErrNotAdmin
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET role = 'user' WHERE line_user_id = ?`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteUserRepository) GetEventNickname(ctx context.Context, eventID, userID string) (string, error) {
+	var nickname string
+	err := r.client.DB.QueryRowContext(ctx,
+		`SELECT nickname FROM event_nicknames WHERE event_id = ? AND user_id = ?`, eventID, userID).Scan(&nickname)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return nickname, err
+}
+
+func (r *SQLiteUserRepository) SetEventNickname(ctx context.Context, eventID, userID, nickname string) error {
+	if nickname == "" {
+		_, err := r.client.DB.ExecContext(ctx,
+			`DELETE FROM event_nicknames WHERE event_id = ? AND user_id = ?`, eventID, userID)
+		return err
+	}
+
+	query := `
+		INSERT INTO event_nicknames (event_id, user_id, nickname, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET
+			nickname = excluded.nickname,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.client.DB.ExecContext(ctx, query, eventID, userID, nickname, sqliteTime(time.Now()))
+	return err
+}
+
+func (r *SQLiteUserRepository) ListEventNicknames(ctx context.Context, userID string) ([]*models.EventNickname, error) {
+	query := `
+		SELECT event_id, user_id, nickname, updated_at
+		FROM event_nicknames WHERE user_id = ?
+		ORDER BY updated_at DESC
+	`
+	rows, err := r.client.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nicknames := make([]*models.EventNickname, 0)
+	for rows.Next() {
+		var n models.EventNickname
+		if err := rows.Scan(&n.EventID, &n.UserID, &n.Nickname, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		nicknames = append(nicknames, &n)
+	}
+
+	return nicknames, rows.Err()
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// ErrForbidden is returned when the user may act on an event but not manage it
+var ErrForbidden = errors.New("only admins and event organizers can manage this event")
+
+// canManageEvent reports whether the viewer is an admin, the event's creator or a
+// co-organizer of the event
+func canManageEvent(ctx context.Context, events repository.EventRepository, event *models.Event, viewer Viewer) (bool, error) {
+	if viewer.IsAdmin {
+		return true, nil
+	}
+	if viewer.UserID == "" {
+		return false, nil
+	}
+	if event.CreatedBy == viewer.UserID {
+		return true, nil
+	}
+	return events.IsOrganizer(ctx, event.EventID, viewer.UserID)
+}
+
+// authorizeManage loads the event and returns ErrForbidden unless the viewer may manage it
+func authorizeManage(ctx context.Context, events repository.EventRepository, eventID string, viewer Viewer) (*models.Event, error) {
+	event, err := events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := canManageEvent(ctx, events, event, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrForbidden
+	}
+
+	return event, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func TestAuthorizeManage(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5})
+	event.CreatedBy = "owner"
+	events := newTestEventRepo(event)
+	addTestOrganizer(t, events, "ev1", "co")
+	svc := newTestEventService(events)
+	ctx := context.Background()
+
+	for _, viewer := range []Viewer{
+		{UserID: "root", IsAdmin: true},
+		{UserID: "owner"},
+		{UserID: "co"},
+	} {
+		if err := svc.AuthorizeManage(ctx, "ev1", viewer); err != nil {
+			t.Errorf("AuthorizeManage(%+v) = %v, want nil", viewer, err)
+		}
+	}
+
+	for _, viewer := range []Viewer{{UserID: "stranger"}, {}} {
+		if err := svc.AuthorizeManage(ctx, "ev1", viewer); !errors.Is(err, ErrForbidden) {
+			t.Errorf("AuthorizeManage(%+v) = %v, want ErrForbidden", viewer, err)
+		}
+	}
+
+	if err := svc.AuthorizeManage(ctx, "missing", Viewer{UserID: "root", IsAdmin: true}); err == nil || errors.Is(err, ErrForbidden) {
+		t.Errorf("AuthorizeManage(missing) = %v, want not-found error", err)
+	}
+}
+
+func TestGetEventStatusOrganizerSeesIdentities(t *testing.T) {
+	hidden := false
+	event := voteEvent("ev1", 1, "a", "b")
+	event.Config.ShowVoters = &hidden
+	event.CreatedBy = "owner"
+	events := newTestEventRepo(event)
+	addTestOrganizer(t, events, "ev1", "co")
+	svc, _ := newTestInteractionService(events, newTestUserRepo(profileUser("alice", "alice name", "")))
+	ctx := context.Background()
+
+	if _, err := svc.HandleAction(ctx, "ev1", &models.Interaction{
+		UserID:          "alice",
+		Type:            models.InteractionTypeVote,
+		SelectedOptions: []string{"a"},
+	}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+
+	for _, uid := range []string{"owner", "co"} {
+		records := recordsFor(t, svc, "ev1", Viewer{UserID: uid})
+		if len(records) != 1 || records[0]["userDisplayName"] != "alice name" {
+			t.Errorf("%s got redacted records %v", uid, records)
+		}
+	}
+
+	records := recordsFor(t, svc, "ev1", Viewer{UserID: "bob"})
+	if len(records) != 1 || records[0]["userDisplayName"] != "" {
+		t.Errorf("bob got unredacted records %v", records)
+	}
+}
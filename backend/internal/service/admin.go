@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var ErrUserIDRequired = errors.New("userId is required")
+
+// isListedAdmin reports whether userID appears in the comma-separated ADMIN_LIST
+func isListedAdmin(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, id := range strings.Split(os.Getenv("ADMIN_LIST"), ",") {
+		if strings.TrimSpace(id) == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldBootstrapAdmin reports whether a user listed in ADMIN_LIST should become admin:
+// only while the database has no admin at all
+func (s *AuthService) shouldBootstrapAdmin(ctx context.Context, userID string) bool {
+	if !isListedAdmin(userID) {
+		return false
+	}
+
+	admins, err := s.Repo.CountByRole(ctx, RoleAdmin)
+	if err != nil {
+		log.Printf("[Admin] Failed to count admins: %v", err)
+		return false
+	}
+	return admins == 0
+}
+
+// CurrentRole returns the user's role as stored in the database ("user" if unknown)
+func (s *AuthService) CurrentRole(ctx context.Context, userID string) (string, error) {
+	user, err := s.Repo.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RoleUser, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+func (s *AuthService) ListAdmins(ctx context.Context) ([]*models.User, error) {
+	return s.Repo.ListByRole(ctx, RoleAdmin)
+}
+
+// GrantAdmin makes a LINE user an admin. Users who have never logged in are created
+// so the role is in place on their first login.
+func (s *AuthService) GrantAdmin(ctx context.Context, userID, actor string) (*models.User, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, ErrUserIDRequired
+	}
+
+	exists, err := s.Repo.Exists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		user := &models.User{
+			LineUserID: userID,
+			Role:       RoleAdmin,
+			CreatedAt:  time.Now(),
+		}
+		if err := s.Repo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+		log.Printf("[Admin] %s granted admin to new user %s", actor, userID)
+		return user, nil
+	}
+
+	if err := s.Repo.UpdateFields(ctx, userID, map[string]interface{}{"role": RoleAdmin}); err != nil {
+		return nil, err
+	}
+	log.Printf("[Admin] %s granted admin to %s", actor, userID)
+
+	return s.Repo.GetByID(ctx, userID)
+}
+
+// RevokeAdmin demotes an admin to a regular user and ends their sessions so they log in
+// again as a regular user. The last admin cannot be revoked.
+func (s *AuthService) RevokeAdmin(ctx context.Context, userID, actor string) error {
+	userID = strings.TrimSpace(userID)
+	if err := s.Repo.RevokeAdmin(ctx, userID); err != nil {
+		return err
+	}
+	log.Printf("[Admin] %s revoked admin from %s", actor, userID)
+
+	if err := s.Sessions.RevokeAllForUser(ctx, userID); err != nil {
+		// The role is already gone and RoleMiddleware reloads it on every request
+		log.Printf("[Admin] Failed to revoke sessions of %s: %v", userID, err)
+	}
+	return nil
+}
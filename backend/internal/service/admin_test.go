@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func TestUpsertUserBootstrapsFirstAdmin(t *testing.T) {
+	t.Setenv("ADMIN_LIST", "Uadmin, Uother")
+	users := newTestUserRepo()
+	svc := NewAuthService(users, repository.NewMemorySessionRepository(), nil)
+	ctx := context.Background()
+
+	// Substrings of a listed ID do not match
+	user, err := svc.upsertUser(ctx, &Identity{UserID: "Uadm", DisplayName: "partial"})
+	if err != nil || user.Role != RoleUser {
+		t.Fatalf("partial ID = %+v, %v; want role user", user, err)
+	}
+
+	user, err = svc.upsertUser(ctx, &Identity{UserID: "Uadmin", DisplayName: "boss"})
+	if err != nil || user.Role != RoleAdmin {
+		t.Fatalf("listed ID = %+v, %v; want role admin", user, err)
+	}
+
+	// Once an admin exists, ADMIN_LIST grants nothing more
+	user, err = svc.upsertUser(ctx, &Identity{UserID: "Uother", DisplayName: "other"})
+	if err != nil || user.Role != RoleUser {
+		t.Fatalf("second listed ID = %+v, %v; want role user", user, err)
+	}
+}
+
+func TestUpsertUserKeepsStoredRole(t *testing.T) {
+	t.Setenv("ADMIN_LIST", "")
+	users := newTestUserRepo(&models.User{LineUserID: "U1", Role: RoleAdmin, CreatedAt: time.Now()})
+	svc := NewAuthService(users, repository.NewMemorySessionRepository(), nil)
+
+	user, err := svc.upsertUser(context.Background(), &Identity{UserID: "U1", DisplayName: "renamed", PictureURL: "p"})
+	if err != nil {
+		t.Fatalf("upsertUser: %v", err)
+	}
+	stored, _ := users.GetByID(context.Background(), "U1")
+	if user.Role != RoleAdmin || stored.Role != RoleAdmin || stored.LineDisplayName != "renamed" {
+		t.Errorf("user = %+v, stored = %+v", user, stored)
+	}
+}
+
+func TestGrantAndRevokeAdmin(t *testing.T) {
+	users := newTestUserRepo(&models.User{LineUserID: "root", Role: RoleAdmin, CreatedAt: time.Now()})
+	svc := NewAuthService(users, repository.NewMemorySessionRepository(), nil)
+	ctx := context.Background()
+
+	// Users who never logged in are created with the role
+	granted, err := svc.GrantAdmin(ctx, "newbie", "root")
+	if err != nil || granted.Role != RoleAdmin {
+		t.Fatalf("GrantAdmin = %+v, %v", granted, err)
+	}
+	if role, _ := svc.CurrentRole(ctx, "newbie"); role != RoleAdmin {
+		t.Errorf("CurrentRole(newbie) = %s, want admin", role)
+	}
+	if role, _ := svc.CurrentRole(ctx, "unknown"); role != RoleUser {
+		t.Errorf("CurrentRole(unknown) = %s, want user", role)
+	}
+
+	admins, _ := svc.ListAdmins(ctx)
+	if len(admins) != 2 {
+		t.Fatalf("ListAdmins = %d, want 2", len(admins))
+	}
+
+	if err := svc.RevokeAdmin(ctx, "root", "newbie"); err != nil {
+		t.Fatalf("RevokeAdmin: %v", err)
+	}
+	if err := svc.RevokeAdmin(ctx, "root", "newbie"); !errors.Is(err, repository.ErrNotAdmin) {
+		t.Errorf("revoke twice = %v, want ErrNotAdmin", err)
+	}
+	if err := svc.RevokeAdmin(ctx, "newbie", "newbie"); !errors.Is(err, repository.ErrLastAdmin) {
+		t.Errorf("revoke last = %v, want ErrLastAdmin", err)
+	}
+}
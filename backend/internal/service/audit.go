@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// Audit actions
+const (
+	AuditEventCreate     = "EVENT_CREATE"
+	AuditEventUpdate     = "EVENT_UPDATE"
+	AuditEventStatus     = "EVENT_STATUS"
+	AuditEventArchive    = "EVENT_ARCHIVE"
+	AuditLineUpReconcile = "LINEUP_RECONCILE"
+	AuditOrganizerAdd    = "ORGANIZER_ADD"
+	AuditOrganizerRemove = "ORGANIZER_REMOVE"
+	AuditOwnerTransfer   = "OWNER_TRANSFER"
+	AuditVote            = "VOTE"
+	AuditLineUpRegister  = "LINEUP_REGISTER"
+	AuditLineUpCancel    = "LINEUP_CANCEL"
+	AuditLineUpPromote   = "LINEUP_PROMOTE"
+	AuditLineUpReorder   = "LINEUP_REORDER"
+	AuditGuestRegister   = "GUEST_REGISTER"
+	AuditMemoCreate      = "MEMO_CREATE"
+	AuditNoteUpdate      = "NOTE_UPDATE"
+	AuditMemoUpdate      = "MEMO_UPDATE"
+	AuditClap            = "CLAP"
+)
+
+// Actors for changes not made by a user
+const (
+	ActorScheduler = "system:scheduler"
+)
+
+// DefaultAuditLimit is the number of entries GET /events/:id/audit returns by default
+const DefaultAuditLimit = 100
+
+// AuditService records who changed what on an event
+type AuditService struct {
+	Repo   repository.AuditRepository
+	Events repository.EventRepository
+}
+
+func NewAuditService(repo repository.AuditRepository, events repository.EventRepository) *AuditService {
+	return &AuditService{Repo: repo, Events: events}
+}
+
+// Record appends an audit entry. before and after are stored as JSON (nil = none).
+// Failures are logged rather than returned so auditing never undoes a completed change.
+func (s *AuditService) Record(ctx context.Context, actor, eventID, recordID, action string, before, after interface{}) {
+	entry := &models.AuditEntry{
+		EventID:  eventID,
+		RecordID: recordID,
+		Actor:    actor,
+		Action:   action,
+		Before:   auditJSON(before),
+		After:    auditJSON(after),
+	}
+
+	if err := s.Repo.Create(ctx, entry); err != nil {
+		log.Printf("[Audit] Failed to record %s by %s on %s/%s: %v", action, actor, eventID, recordID, err)
+	}
+}
+
+// List returns the event's most recent audit entries. Only managers of the event may read it.
+func (s *AuditService) List(ctx context.Context, eventID string, limit int, viewer Viewer) ([]*models.AuditEntry, error) {
+	if _, err := authorizeManage(ctx, s.Events, eventID, viewer); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 1000 {
+		limit = DefaultAuditLimit
+	}
+
+	return s.Repo.ListByEvent(ctx, eventID, limit)
+}
+
+func auditJSON(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[Audit] Failed to encode %T: %v", v, err)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func auditActions(entries []*models.AuditEntry) []string {
+	actions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+func TestAuditRecordsLineUpChanges(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 1})
+	event.CreatedBy = "owner"
+	svc, _ := newTestInteractionService(newTestEventRepo(event), newTestUserRepo())
+	ctx := context.Background()
+
+	seedLineUp(t, svc, "ev1", 2)
+	if _, err := svc.HandleAction(ctx, "ev1", &models.Interaction{UserID: "u0", Type: models.InteractionTypeLineUp, Count: -1}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	if _, err := svc.Audit.List(ctx, "ev1", 0, Viewer{UserID: "u1"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("List by participant = %v, want ErrForbidden", err)
+	}
+
+	entries, err := svc.Audit.List(ctx, "ev1", 0, Viewer{UserID: "owner"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	// Most recent first
+	want := []string{AuditLineUpPromote, AuditLineUpCancel, AuditLineUpRegister, AuditLineUpRegister}
+	got := auditActions(entries)
+	if len(got) != len(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("actions = %v, want %v", got, want)
+		}
+	}
+
+	cancel := entries[1]
+	if cancel.Actor != "u0" || cancel.RecordID == "" {
+		t.Errorf("cancel entry = %+v", cancel)
+	}
+	var after models.Interaction
+	if err := json.Unmarshal(cancel.After, &after); err != nil || after.Status != "CANCELLED" {
+		t.Errorf("cancel after = %s (%v)", cancel.After, err)
+	}
+	if promote := entries[0]; promote.Actor != "u0" || promote.Before == nil {
+		t.Errorf("promote entry = %+v", promote)
+	}
+}
+
+func TestAuditRecordsEventUpdate(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 1})
+	event.Title = "before"
+	events := newTestEventRepo(event)
+	svc := newTestEventService(events)
+	ctx := context.Background()
+
+	edited := *event
+	edited.Title = "after"
+	if _, err := svc.UpdateEvent(ctx, &edited, "editor"); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if err := svc.UpdateEventStatus(ctx, "ev1", false, "editor"); err != nil {
+		t.Fatalf("UpdateEventStatus: %v", err)
+	}
+
+	entries, err := svc.Audit.List(ctx, "ev1", 10, Viewer{UserID: "root", IsAdmin: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := auditActions(entries); len(got) != 2 || got[0] != AuditEventStatus || got[1] != AuditEventUpdate {
+		t.Fatalf("actions = %v", got)
+	}
+
+	var before, after models.Event
+	json.Unmarshal(entries[1].Before, &before)
+	json.Unmarshal(entries[1].After, &after)
+	if before.Title != "before" || after.Title != "after" || entries[1].Actor != "editor" {
+		t.Errorf("update entry before=%s after=%s actor=%s", before.Title, after.Title, entries[1].Actor)
+	}
+}
@@ -2,29 +2,29 @@ package service
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"net/url"
-	"os"
-	"strings"
 	"time"
 
 	"event-manager/internal/models"
 	"event-manager/internal/repository"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type AuthService struct {
-	Repo repository.UserRepository
+	Repo      repository.UserRepository
+	Sessions  repository.SessionRepository
+	Keys      *KeyManager
+	Providers map[string]IdentityProvider
+
+	streamTickets redeemedTickets
 }
 
-func NewAuthService(repo repository.UserRepository) *AuthService {
-	return &AuthService{Repo: repo}
+func NewAuthService(repo repository.UserRepository, sessions repository.SessionRepository, keys *KeyManager, providers ...IdentityProvider) *AuthService {
+	s := &AuthService{Repo: repo, Sessions: sessions, Keys: keys, Providers: make(map[string]IdentityProvider)}
+	for _, p := range providers {
+		s.Providers[p.Name()] = p
+	}
+	return s
 }
 
 type LineTokenResponse struct {
@@ -36,127 +36,84 @@ type LineTokenResponse struct {
 	Name    string `json:"name"`
 	Picture string `json:"picture"`
 	Email   string `json:"email"`
+	Nonce   string `json:"nonce"`
 }
 
-func (s *AuthService) VerifyLineToken(idToken string) (*LineTokenResponse, error) {
-	clientID := os.Getenv("LINE_CHANNEL_ID")
-	log.Printf("[LINE VERIFY] Using LINE_CHANNEL_ID: %s", clientID)
-
-	data := url.Values{}
-	data.Set("id_token", idToken)
-	if clientID != "" {
-		data.Set("client_id", clientID)
+// Login verifies the credentials with the named provider (LINE when empty) and starts a session
+func (s *AuthService) Login(ctx context.Context, provider string, creds LoginCredentials) (*TokenPair, *models.User, error) {
+	if provider == "" {
+		provider = ProviderLine
+	}
+	idp, ok := s.Providers[provider]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
 	}
 
-	log.Printf("[LINE VERIFY] Sending request to LINE API...")
-	resp, err := http.PostForm("https://api.line.me/oauth2/v2.1/verify", data)
+	// 1. Verify credentials
+	identity, err := idp.Authenticate(ctx, creds)
 	if err != nil {
-		log.Printf("[LINE VERIFY] HTTP request failed: %v", err)
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to verify %s login: %w", provider, err)
 	}
-	defer resp.Body.Close()
-
-	log.Printf("[LINE VERIFY] Response status: %d", resp.StatusCode)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("[LINE VERIFY] LINE API returned non-200 status. Body: %s", string(body))
-		return nil, errors.New("invalid line token")
+	// 2. Check/Update User in database
+	user, err := s.upsertUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var tokenResp LineTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		log.Printf("[LINE VERIFY] Failed to decode response: %v", err)
-		return nil, err
+	// 3. Start a session and issue tokens
+	pair, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Printf("[LINE VERIFY] Successfully verified token for user: %s", tokenResp.Sub)
-	return &tokenResp, nil
+	return pair, user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, idToken string) (string, *models.User, error) {
-	// 1. Verify LINE Token
-	lineProfile, err := s.VerifyLineToken(idToken)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to verify line token: %w", err)
-	}
+// upsertUser creates or refreshes the user's profile. Roles stored in the database are
+// kept; ADMIN_LIST only grants admin while no admin exists yet.
+func (s *AuthService) upsertUser(ctx context.Context, identity *Identity) (*models.User, error) {
+	existingUser, err := s.Repo.GetByID(ctx, identity.UserID)
 
-	// 2. Check/Update User in database
-	existingUser, err := s.Repo.GetByID(ctx, lineProfile.Sub)
-
-	var user models.User
 	if err != nil {
 		// User doesn't exist, create new user
-		user = models.User{
-			LineUserID:      lineProfile.Sub,
-			LineDisplayName: lineProfile.Name,
-			PictureURL:      lineProfile.Picture,
-			Role:            "user",
+		user := models.User{
+			LineUserID:      identity.UserID,
+			LineDisplayName: identity.DisplayName,
+			PictureURL:      identity.PictureURL,
+			Role:            RoleUser,
 			CreatedAt:       time.Now(),
 		}
-
-		// Check Admin Whitelist
-		adminList := os.Getenv("ADMIN_LIST")
-		log.Printf("[NEW USER] Checking ADMIN_LIST for user: %s", lineProfile.Sub)
-		log.Printf("[NEW USER] ADMIN_LIST: %s", adminList)
-		if strings.Contains(adminList, lineProfile.Sub) {
-			user.Role = "admin"
-			log.Printf("[NEW USER] User %s assigned role: admin", lineProfile.Sub)
-		} else {
-			log.Printf("[NEW USER] User %s assigned role: user", lineProfile.Sub)
+		if s.shouldBootstrapAdmin(ctx, user.LineUserID) {
+			user.Role = RoleAdmin
+			log.Printf("[NEW USER] User %s bootstrapped as admin from ADMIN_LIST", user.LineUserID)
 		}
 
 		if err := s.Repo.Create(ctx, &user); err != nil {
-			return "", nil, err
-		}
-	} else {
-		// Update existing user info
-		user = *existingUser
-
-		// Re-check admin role from ADMIN_LIST on every login
-		adminList := os.Getenv("ADMIN_LIST")
-		log.Printf("[EXISTING USER] Checking ADMIN_LIST for user: %s", lineProfile.Sub)
-		log.Printf("[EXISTING USER] ADMIN_LIST: %s", adminList)
-		log.Printf("[EXISTING USER] Current role in DB: %s", user.Role)
-
-		newRole := "user"
-		if strings.Contains(adminList, lineProfile.Sub) {
-			newRole = "admin"
-		}
-		log.Printf("[EXISTING USER] New role assigned: %s", newRole)
-
-		updates := map[string]interface{}{
-			"lineDisplayName": lineProfile.Name,
-			"pictureUrl":      lineProfile.Picture,
-			"role":            newRole,
-		}
-
-		if err := s.Repo.UpdateFields(ctx, lineProfile.Sub, updates); err != nil {
-			return "", nil, err
+			return nil, err
 		}
-
-		// Update local struct
-		user.LineDisplayName = lineProfile.Name
-		user.PictureURL = lineProfile.Picture
-		user.Role = newRole
+		return &user, nil
 	}
 
-	// 3. Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"uid":  user.LineUserID,
-		"role": user.Role,
-		"exp":  time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
-	})
-
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "default-secret-do-not-use-in-prod"
+	// Update existing user info
+	user := *existingUser
+	updates := map[string]interface{}{
+		"lineDisplayName": identity.DisplayName,
+		"pictureUrl":      identity.PictureURL,
+	}
+	if user.Role != RoleAdmin && s.shouldBootstrapAdmin(ctx, user.LineUserID) {
+		updates["role"] = RoleAdmin
+		user.Role = RoleAdmin
+		log.Printf("[EXISTING USER] User %s bootstrapped as admin from ADMIN_LIST", user.LineUserID)
 	}
 
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		return "", nil, err
+	if err := s.Repo.UpdateFields(ctx, user.LineUserID, updates); err != nil {
+		return nil, err
 	}
 
-	return tokenString, &user, nil
+	// Update local struct
+	user.LineDisplayName = identity.DisplayName
+	user.PictureURL = identity.PictureURL
+
+	return &user, nil
 }
@@ -10,15 +10,25 @@ type CachedEventStatus struct {
 	ExpiresAt time.Time
 }
 
-type CacheService struct {
-	cache map[string]*CachedEventStatus
+// CacheService caches event status per event and per view, so differently
+// redacted payloads of the same event never share an entry
+type CacheService interface {
+	Get(key, view string) (map[string]interface{}, bool)
+	Set(key, view string, data map[string]interface{})
+	// Invalidate drops every view cached for key
+	Invalidate(key string)
+}
+
+// MemoryCacheService is an in-process CacheService with a fixed TTL
+type MemoryCacheService struct {
+	cache map[string]map[string]*CachedEventStatus // key -> view -> status
 	mutex sync.RWMutex
 	ttl   time.Duration
 }
 
-func NewCacheService(ttl time.Duration) *CacheService {
-	cache := &CacheService{
-		cache: make(map[string]*CachedEventStatus),
+func NewMemoryCacheService(ttl time.Duration) *MemoryCacheService {
+	cache := &MemoryCacheService{
+		cache: make(map[string]map[string]*CachedEventStatus),
 		ttl:   ttl,
 	}
 
@@ -28,11 +38,11 @@ func NewCacheService(ttl time.Duration) *CacheService {
 	return cache
 }
 
-func (c *CacheService) Get(key string) (map[string]interface{}, bool) {
+func (c *MemoryCacheService) Get(key, view string) (map[string]interface{}, bool) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	cached, exists := c.cache[key]
+	cached, exists := c.cache[key][view]
 	if !exists {
 		return nil, false
 	}
@@ -45,32 +55,48 @@ func (c *CacheService) Get(key string) (map[string]interface{}, bool) {
 	return cached.Data, true
 }
 
-func (c *CacheService) Set(key string, data map[string]interface{}) {
+func (c *MemoryCacheService) Set(key, view string, data map[string]interface{}) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.cache[key] = &CachedEventStatus{
+	if c.cache[key] == nil {
+		c.cache[key] = make(map[string]*CachedEventStatus)
+	}
+	c.cache[key][view] = &CachedEventStatus{
 		Data:      data,
 		ExpiresAt: time.Now().Add(c.ttl),
 	}
 }
 
-func (c *CacheService) Invalidate(key string) {
+func (c *MemoryCacheService) Invalidate(key string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	delete(c.cache, key)
 }
 
-func (c *CacheService) cleanupExpired() {
+// Clear drops every cached entry
+func (c *MemoryCacheService) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cache = make(map[string]map[string]*CachedEventStatus)
+}
+
+func (c *MemoryCacheService) cleanupExpired() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for range ticker.C {
 		c.mutex.Lock()
 		now := time.Now()
-		for key, cached := range c.cache {
-			if now.After(cached.ExpiresAt) {
+		for key, views := range c.cache {
+			for view, cached := range views {
+				if now.After(cached.ExpiresAt) {
+					delete(views, view)
+				}
+			}
+			if len(views) == 0 {
 				delete(c.cache, key)
 			}
 		}
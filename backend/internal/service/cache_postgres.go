@@ -0,0 +1,107 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// cacheInvalidateChannel is the Postgres NOTIFY channel shared by all replicas
+const cacheInvalidateChannel = "event_status_invalidate"
+
+// PostgresCacheService keeps entries in a local MemoryCacheService and uses Postgres
+// LISTEN/NOTIFY to invalidate the same key on every other replica
+type PostgresCacheService struct {
+	local      *MemoryCacheService
+	db         *sql.DB
+	listener   *pq.Listener
+	instanceID string
+	onRemote   func(key string)
+}
+
+// NewPostgresCacheService connects to Postgres and starts listening for invalidations.
+// onRemote, if set, is called with the key of every invalidation from another replica
+// (used to wake local status streams).
+func NewPostgresCacheService(connStr string, ttl time.Duration, onRemote func(key string)) (*PostgresCacheService, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache connection: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect cache: %w", err)
+	}
+	db.SetMaxOpenConns(2)
+
+	listener := pq.NewListener(connStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("[Cache] Listener event %d: %v", ev, err)
+		}
+	})
+	if err := listener.Listen(cacheInvalidateChannel); err != nil {
+		listener.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", cacheInvalidateChannel, err)
+	}
+
+	c := &PostgresCacheService{
+		local:      NewMemoryCacheService(ttl),
+		db:         db,
+		listener:   listener,
+		instanceID: uuid.New().String(),
+		onRemote:   onRemote,
+	}
+	go c.listen()
+
+	return c, nil
+}
+
+func (c *PostgresCacheService) Get(key, view string) (map[string]interface{}, bool) {
+	return c.local.Get(key, view)
+}
+
+func (c *PostgresCacheService) Set(key, view string, data map[string]interface{}) {
+	c.local.Set(key, view, data)
+}
+
+// Invalidate drops the key locally and tells the other replicas to do the same
+func (c *PostgresCacheService) Invalidate(key string) {
+	c.local.Invalidate(key)
+
+	if _, err := c.db.Exec(`SELECT pg_notify($1, $2)`, cacheInvalidateChannel, c.instanceID+"|"+key); err != nil {
+		log.Printf("[Cache] Failed to broadcast invalidation for %s: %v", key, err)
+	}
+}
+
+// Close stops listening and releases the connections
+func (c *PostgresCacheService) Close() error {
+	c.listener.Close()
+	return c.db.Close()
+}
+
+func (c *PostgresCacheService) listen() {
+	for n := range c.listener.Notify {
+		// A nil notification means the connection was re-established and
+		// notifications may have been missed
+		if n == nil {
+			log.Printf("[Cache] Listener reconnected, clearing local cache")
+			c.local.Clear()
+			continue
+		}
+
+		origin, key, ok := strings.Cut(n.Extra, "|")
+		if !ok || origin == c.instanceID {
+			continue
+		}
+
+		c.local.Invalidate(key)
+		if c.onRemote != nil {
+			c.onRemote(key)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgresCacheService_InvalidatesOtherReplicas(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+
+	a, err := NewPostgresCacheService(dsn, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("cache a: %v", err)
+	}
+	defer a.Close()
+
+	remote := make(chan string, 1)
+	b, err := NewPostgresCacheService(dsn, time.Minute, func(key string) { remote <- key })
+	if err != nil {
+		t.Fatalf("cache b: %v", err)
+	}
+	defer b.Close()
+
+	key := uuid.New().String()
+	b.Set(key, "full", map[string]interface{}{"view": "full"})
+	if _, ok := b.Get(key, "full"); !ok {
+		t.Fatal("expected hit before invalidation")
+	}
+
+	// The NOTIFY goes out on a's connection and must reach b's listener
+	a.Invalidate(key)
+
+	select {
+	case got := <-remote:
+		if got != key {
+			t.Fatalf("onRemote key = %q, want %q", got, key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the invalidation to reach the second cache")
+	}
+	if _, ok := b.Get(key, "full"); ok {
+		t.Fatal("expected miss after remote invalidation")
+	}
+}
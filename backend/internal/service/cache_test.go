@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheService_ViewsAreSeparate(t *testing.T) {
+	c := NewMemoryCacheService(time.Minute)
+
+	c.Set("evt", "full", map[string]interface{}{"view": "full"})
+	c.Set("evt", "user:u1", map[string]interface{}{"view": "u1"})
+
+	got, ok := c.Get("evt", "user:u1")
+	if !ok || got["view"] != "u1" {
+		t.Fatalf("Get(user:u1) = %v, %v", got, ok)
+	}
+	if _, ok := c.Get("evt", "user:u2"); ok {
+		t.Fatal("expected miss for uncached view")
+	}
+
+	c.Invalidate("evt")
+	if _, ok := c.Get("evt", "full"); ok {
+		t.Fatal("expected miss after Invalidate")
+	}
+	if _, ok := c.Get("evt", "user:u1"); ok {
+		t.Fatal("expected every view dropped by Invalidate")
+	}
+}
+
+func TestMemoryCacheService_Expiry(t *testing.T) {
+	c := NewMemoryCacheService(-time.Second)
+
+	c.Set("evt", "full", map[string]interface{}{})
+	if _, ok := c.Get("evt", "full"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+}
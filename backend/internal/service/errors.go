@@ -0,0 +1,21 @@
+package service
+
+// ActionError is an action rejected by event rules, carrying a machine-readable code
+// so the client can tell the reasons apart
+type ActionError struct {
+	Code    string
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *ActionError) Error() string {
+	return e.Message
+}
+
+// Errors returned when an action falls outside EventConfig.StartTime / EndTime
+var (
+	ErrEventNotStarted = &ActionError{Code: "EVENT_NOT_STARTED", Message: "event has not started yet"}
+	ErrEventEnded      = &ActionError{Code: "EVENT_ENDED", Message: "event has ended"}
+)
+
+var ErrEventNotActive = &ActionError{Code: "EVENT_NOT_ACTIVE", Message: "event is not active"}
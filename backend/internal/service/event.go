@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"event-manager/internal/models"
@@ -11,11 +12,21 @@ import (
 )
 
 type EventService struct {
-	Repo repository.EventRepository
+	Repo         repository.EventRepository
+	Interactions repository.InteractionRepository
+	Cache        CacheService
+	Hub          *StatusHub
+	Audit        *AuditService
 }
 
-func NewEventService(repo repository.EventRepository) *EventService {
-	return &EventService{Repo: repo}
+func NewEventService(repo repository.EventRepository, interactions repository.InteractionRepository, cache CacheService, hub *StatusHub, audit *AuditService) *EventService {
+	return &EventService{
+		Repo:         repo,
+		Interactions: interactions,
+		Cache:        cache,
+		Hub:          hub,
+		Audit:        audit,
+	}
 }
 
 func (s *EventService) CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
@@ -23,22 +34,46 @@ func (s *EventService) CreateEvent(ctx context.Context, event *models.Event) (*m
 	event.CreatedAt = time.Now()
 	event.IsActive = true
 
+	if event.Type == models.EventTypeVote {
+		syncVoteOptions(&event.Config, nil)
+	}
+
 	if err := s.Repo.Create(ctx, event); err != nil {
 		return nil, err
 	}
 
+	s.Audit.Record(ctx, event.CreatedBy, event.EventID, "", AuditEventCreate, nil, event)
+
 	return event, nil
 }
 
+// AuthorizeManage returns ErrForbidden unless the viewer is an admin, the event's
+// creator or a co-organizer
+func (s *EventService) AuthorizeManage(ctx context.Context, eventID string, viewer Viewer) error {
+	_, err := authorizeManage(ctx, s.Repo, eventID, viewer)
+	return err
+}
+
 func (s *EventService) GetEvent(ctx context.Context, eventID string) (*models.Event, error) {
 	return s.Repo.GetByID(ctx, eventID)
 }
 
-func (s *EventService) UpdateEventStatus(ctx context.Context, eventID string, isActive bool) error {
-	return s.Repo.UpdateStatus(ctx, eventID, isActive)
+func (s *EventService) UpdateEventStatus(ctx context.Context, eventID string, isActive bool, actor string) error {
+	existingEvent, err := s.Repo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.UpdateStatus(ctx, eventID, isActive); err != nil {
+		return err
+	}
+
+	s.Audit.Record(ctx, actor, eventID, "", AuditEventStatus,
+		map[string]bool{"isActive": existingEvent.IsActive}, map[string]bool{"isActive": isActive})
+	return nil
 }
 
-func (s *EventService) UpdateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
+func (s *EventService) UpdateEvent(ctx context.Context, event *models.Event, actor string) (*models.Event, error) {
 	// Get existing event to preserve createdAt and createdBy
 	existingEvent, err := s.Repo.GetByID(ctx, event.EventID)
 	if err != nil {
@@ -49,20 +84,83 @@ func (s *EventService) UpdateEvent(ctx context.Context, event *models.Event) (*m
 	event.CreatedAt = existingEvent.CreatedAt
 	event.CreatedBy = existingEvent.CreatedBy
 
-	// Update the event
-	if err := s.Repo.Update(ctx, event); err != nil {
+	// Stamp a manual open/close so the scheduler's catch-up leaves it alone
+	event.StatusChangedAt = existingEvent.StatusChangedAt
+	if event.IsActive != existingEvent.IsActive {
+		now := time.Now()
+		event.StatusChangedAt = &now
+	}
+
+	// Keep option IDs stable across renames
+	if event.Type == models.EventTypeVote {
+		syncVoteOptions(&event.Config, voteOptionsOf(existingEvent.Config))
+	}
+
+	// A capacity change re-sorts LINEUP statuses in the same transaction as the update
+	var changes []repository.LineUpStatusChange
+	if lineUpCapacityChanged(existingEvent, event) {
+		changes, err = s.Interactions.UpdateLineUpEvent(ctx, event)
+	} else {
+		err = s.Repo.Update(ctx, event)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	s.Audit.Record(ctx, actor, event.EventID, "", AuditEventUpdate, existingEvent, event)
+	for _, change := range changes {
+		log.Printf("[UpdateEvent] Record %s (user %s): %s -> %s", change.RecordID, change.UserID, change.From, change.To)
+		s.Audit.Record(ctx, actor, event.EventID, change.RecordID, AuditLineUpReconcile,
+			map[string]string{"status": change.From}, map[string]string{"status": change.To})
+	}
+
+	// Cached status embeds option labels and LINEUP statuses
+	s.Cache.Invalidate(event.EventID)
+	s.Hub.Publish(event.EventID)
+
 	return event, nil
 }
 
+// PreviewEventUpdate returns the LINEUP status changes UpdateEvent would make for the
+// proposed event, without writing anything
+func (s *EventService) PreviewEventUpdate(ctx context.Context, event *models.Event) ([]repository.LineUpStatusChange, error) {
+	existingEvent, err := s.Repo.GetByID(ctx, event.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingEvent.Type != models.EventTypeLineUp {
+		return []repository.LineUpStatusChange{}, nil
+	}
+
+	return s.Interactions.ReconcileLineUp(ctx, event.EventID, event.Config.MaxParticipants, true)
+}
+
+func lineUpCapacityChanged(before, after *models.Event) bool {
+	if before.Type != models.EventTypeLineUp {
+		return false
+	}
+	return before.Config.MaxParticipants != after.Config.MaxParticipants ||
+		before.Config.WaitlistLimit != after.Config.WaitlistLimit
+}
+
 func (s *EventService) ListEvents(ctx context.Context, limit int) ([]*models.Event, error) {
 	return s.Repo.List(ctx, limit)
 }
 
-func (s *EventService) ArchiveEvent(ctx context.Context, eventID string, isArchived bool) error {
-	return s.Repo.UpdateArchived(ctx, eventID, isArchived)
+func (s *EventService) ArchiveEvent(ctx context.Context, eventID string, isArchived bool, actor string) error {
+	existingEvent, err := s.Repo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.UpdateArchived(ctx, eventID, isArchived); err != nil {
+		return err
+	}
+
+	s.Audit.Record(ctx, actor, eventID, "", AuditEventArchive,
+		map[string]bool{"isArchived": existingEvent.IsArchived}, map[string]bool{"isArchived": isArchived})
+	return nil
 }
 
 func (s *EventService) GetEventByTag(ctx context.Context, tag string) (*models.Event, error) {
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// seedLineUp registers n users (u0..u{n-1}) in timestamp order
+func seedLineUp(t *testing.T, svc *InteractionService, eventID string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, err := svc.HandleAction(context.Background(), eventID, &models.Interaction{
+			UserID: fmt.Sprintf("u%d", i),
+			Type:   models.InteractionTypeLineUp,
+			Count:  1,
+		})
+		if err != nil {
+			t.Fatalf("register u%d: %v", i, err)
+		}
+	}
+}
+
+func statusByUser(t *testing.T, repo *repository.MemoryInteractionRepository, eventID string) map[string]string {
+	t.Helper()
+	records, err := repo.GetByEventID(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetByEventID: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, rec := range records {
+		statuses[rec.UserID] = rec.Status
+	}
+	return statuses
+}
+
+func TestUpdateEventReconcilesLineUpCapacity(t *testing.T) {
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 2}))
+	interactionSvc, repo := newTestInteractionService(events, newTestUserRepo())
+	eventSvc := NewEventService(events, repo, interactionSvc.Cache, interactionSvc.Hub, interactionSvc.Audit)
+	seedLineUp(t, interactionSvc, "ev1", 4)
+
+	// Raise capacity: u2 is promoted, u3 stays waitlisted
+	raised := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 3})
+	preview, err := eventSvc.PreviewEventUpdate(context.Background(), raised)
+	if err != nil {
+		t.Fatalf("PreviewEventUpdate: %v", err)
+	}
+	if len(preview) != 1 || preview[0].UserID != "u2" || preview[0].To != "SUCCESS" {
+		t.Fatalf("preview = %+v, want u2 -> SUCCESS", preview)
+	}
+	if got := statusByUser(t, repo, "ev1")["u2"]; got != "WAITLIST" {
+		t.Fatalf("preview wrote changes: u2 = %s", got)
+	}
+
+	if _, err := eventSvc.UpdateEvent(context.Background(), raised, "admin"); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	want := map[string]string{"u0": "SUCCESS", "u1": "SUCCESS", "u2": "SUCCESS", "u3": "WAITLIST"}
+	for uid, status := range statusByUser(t, repo, "ev1") {
+		if want[uid] != status {
+			t.Errorf("after raise %s = %s, want %s", uid, status, want[uid])
+		}
+	}
+
+	// Lower capacity: latest SUCCESS registrations are demoted
+	lowered := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 1})
+	if _, err := eventSvc.UpdateEvent(context.Background(), lowered, "admin"); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	want = map[string]string{"u0": "SUCCESS", "u1": "WAITLIST", "u2": "WAITLIST", "u3": "WAITLIST"}
+	for uid, status := range statusByUser(t, repo, "ev1") {
+		if want[uid] != status {
+			t.Errorf("after lower %s = %s, want %s", uid, status, want[uid])
+		}
+	}
+}
+
+func TestUpdateEventWithoutCapacityChangeKeepsStatuses(t *testing.T) {
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 1}))
+	interactionSvc, repo := newTestInteractionService(events, newTestUserRepo())
+	eventSvc := NewEventService(events, repo, NewMemoryCacheService(30*time.Second), NewStatusHub(), NewAuditService(repository.NewMemoryAuditRepository(), events))
+	seedLineUp(t, interactionSvc, "ev1", 2)
+
+	// Force an out-of-order state that only a capacity change should fix
+	records, _ := repo.GetByEventID(context.Background(), "ev1")
+	repo.Update(context.Background(), "ev1", records[1].ID, map[string]interface{}{"status": "SUCCESS"})
+
+	renamed := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 1})
+	renamed.Title = "renamed"
+	if _, err := eventSvc.UpdateEvent(context.Background(), renamed, "admin"); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if got := statusByUser(t, repo, "ev1")["u1"]; got != "SUCCESS" {
+		t.Errorf("u1 = %s, want untouched SUCCESS", got)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatusHub is an in-process pub/sub that tells stream subscribers when an event's
+// status changed. Notifications carry no payload; subscribers re-read the status.
+type StatusHub struct {
+	mutex       sync.Mutex
+	epoch       string // Distinguishes sequence numbers across restarts
+	sequences   map[string]uint64
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func NewStatusHub() *StatusHub {
+	return &StatusHub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		sequences:   make(map[string]uint64),
+		subscribers: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel signalled after every change to eventID. Signals are
+// coalesced: a slow subscriber sees at least one signal after the latest change.
+// Call cancel to unsubscribe.
+func (h *StatusHub) Subscribe(eventID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mutex.Lock()
+	if h.subscribers[eventID] == nil {
+		h.subscribers[eventID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[eventID][ch] = struct{}{}
+	h.mutex.Unlock()
+
+	cancel := func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		delete(h.subscribers[eventID], ch)
+		if len(h.subscribers[eventID]) == 0 {
+			delete(h.subscribers, eventID)
+		}
+	}
+	return ch, cancel
+}
+
+// Publish records a change to eventID and signals its subscribers
+func (h *StatusHub) Publish(eventID string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.sequences[eventID]++
+	for ch := range h.subscribers[eventID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Revision returns an opaque ID for the current state of eventID, used as the SSE event ID
+func (h *StatusHub) Revision(eventID string) string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return fmt.Sprintf("%s-%d", h.epoch, h.sequences[eventID])
+}
+
+// IsCurrent reports whether lastEventID (from a reconnecting client) matches the current revision
+func (h *StatusHub) IsCurrent(eventID, lastEventID string) bool {
+	return strings.TrimSpace(lastEventID) == h.Revision(eventID)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+)
+
+func TestStatusHubPublishSubscribe(t *testing.T) {
+	hub := NewStatusHub()
+	updates, cancel := hub.Subscribe("ev1")
+	other, cancelOther := hub.Subscribe("ev2")
+	defer cancelOther()
+
+	before := hub.Revision("ev1")
+	hub.Publish("ev1")
+	hub.Publish("ev1") // coalesced with the first signal
+
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("no signal after Publish")
+	}
+	select {
+	case <-updates:
+		t.Fatal("signals were not coalesced")
+	default:
+	}
+	select {
+	case <-other:
+		t.Fatal("subscriber of another event was signalled")
+	default:
+	}
+
+	if hub.IsCurrent("ev1", before) {
+		t.Error("revision did not advance")
+	}
+	if !hub.IsCurrent("ev1", hub.Revision("ev1")) {
+		t.Error("current revision not recognised")
+	}
+
+	cancel()
+	hub.Publish("ev1")
+	select {
+	case <-updates:
+		t.Fatal("signal after unsubscribe")
+	default:
+	}
+}
+
+func TestHandleActionPublishesChange(t *testing.T) {
+	svc, _ := newTestInteractionService(newTestEventRepo(voteEvent("ev1", 1, "a")), newTestUserRepo())
+	updates, cancel := svc.Hub.Subscribe("ev1")
+	defer cancel()
+
+	if _, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+		UserID:          "u1",
+		Type:            models.InteractionTypeVote,
+		SelectedOptions: []string{"a"},
+	}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+
+	select {
+	case <-updates:
+	default:
+		t.Fatal("HandleAction did not notify stream subscribers")
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+const (
+	ProviderLine = "line"
+	ProviderDev  = "dev"
+
+	// DevUserPrefix keeps development users apart from real LINE user IDs
+	DevUserPrefix = "dev:"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	ErrDevUserRequired = errors.New("userId is required for development login")
+)
+
+// LoginCredentials is what a client sends to log in. Each provider reads the fields it needs.
+type LoginCredentials struct {
+	// LINE
+	IDToken string
+	Nonce   string
+
+	// Development login
+	UserID      string
+	DisplayName string
+	PictureURL  string
+}
+
+// Identity is a verified user profile returned by an IdentityProvider
+type Identity struct {
+	UserID      string
+	DisplayName string
+	PictureURL  string
+}
+
+// IdentityProvider verifies login credentials and returns who the user is
+type IdentityProvider interface {
+	Name() string
+	Authenticate(ctx context.Context, creds LoginCredentials) (*Identity, error)
+}
+
+// LineIdentityProvider logs users in with a LIFF ID token
+type LineIdentityProvider struct {
+	Verifier *LineTokenVerifier
+}
+
+func NewLineIdentityProvider(channelID string) *LineIdentityProvider {
+	return &LineIdentityProvider{Verifier: NewLineTokenVerifier(channelID)}
+}
+
+func (p *LineIdentityProvider) Name() string {
+	return ProviderLine
+}
+
+func (p *LineIdentityProvider) Authenticate(ctx context.Context, creds LoginCredentials) (*Identity, error) {
+	if creds.IDToken == "" {
+		return nil, errors.New("idToken is required")
+	}
+	profile, err := p.Verifier.Verify(ctx, creds.IDToken, creds.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		UserID:      profile.Sub,
+		DisplayName: profile.Name,
+		PictureURL:  profile.Picture,
+	}, nil
+}
+
+// DevIdentityProvider trusts whatever user the client names. It exists so local development
+// and automated tests can obtain a JWT without LINE, and must never be enabled in production.
+type DevIdentityProvider struct{}
+
+func NewDevIdentityProvider() *DevIdentityProvider {
+	return &DevIdentityProvider{}
+}
+
+func (p *DevIdentityProvider) Name() string {
+	return ProviderDev
+}
+
+func (p *DevIdentityProvider) Authenticate(ctx context.Context, creds LoginCredentials) (*Identity, error) {
+	userID := strings.TrimSpace(creds.UserID)
+	if userID == "" {
+		return nil, ErrDevUserRequired
+	}
+	if !strings.HasPrefix(userID, DevUserPrefix) {
+		userID = DevUserPrefix + userID
+	}
+
+	displayName := strings.TrimSpace(creds.DisplayName)
+	if displayName == "" {
+		displayName = strings.TrimPrefix(userID, DevUserPrefix)
+	}
+
+	return &Identity{
+		UserID:      userID,
+		DisplayName: displayName,
+		PictureURL:  creds.PictureURL,
+	}, nil
+}
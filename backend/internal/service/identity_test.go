@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-manager/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func parseTestJWT(t *testing.T, svc *AuthService, token string) jwt.MapClaims {
+	t.Helper()
+	claims, err := svc.Keys.Parse(token)
+	if err != nil {
+		t.Fatalf("parse issued token: %v", err)
+	}
+	return claims
+}
+
+func TestDevLogin(t *testing.T) {
+	t.Setenv("ADMIN_LIST", "dev:boss")
+	users := newTestUserRepo()
+	svc := NewAuthService(users, repository.NewMemorySessionRepository(), newTestKeyManager(t, AlgEdDSA), NewDevIdentityProvider())
+	ctx := context.Background()
+
+	tokens, user, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "alice", DisplayName: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.LineUserID != "dev:alice" || user.LineDisplayName != "Alice" || user.Role != RoleUser {
+		t.Fatalf("user = %+v", user)
+	}
+	if claims := parseTestJWT(t, svc, tokens.AccessToken); claims["uid"] != "dev:alice" {
+		t.Fatalf("uid claim = %v", claims["uid"])
+	}
+
+	// Dev users go through the same ADMIN_LIST bootstrap as LINE users
+	_, user, err = svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "dev:boss"})
+	if err != nil || user.Role != RoleAdmin || user.LineDisplayName != "boss" {
+		t.Fatalf("admin login = %+v, %v", user, err)
+	}
+
+	if _, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "  "}); !errors.Is(err, ErrDevUserRequired) {
+		t.Fatalf("empty user: err = %v, want ErrDevUserRequired", err)
+	}
+}
+
+func TestLoginRequiresRegisteredProvider(t *testing.T) {
+	key := newTestLineKey(t)
+	line := &LineIdentityProvider{Verifier: newTestVerifier(StaticLineKeys{"k1": &key.PublicKey}, "http://127.0.0.1:0")}
+	svc := NewAuthService(newTestUserRepo(), repository.NewMemorySessionRepository(), newTestKeyManager(t, AlgEdDSA), line)
+	ctx := context.Background()
+
+	// Without DEV_LOGIN the dev provider is not registered
+	if _, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "alice"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("dev login: err = %v, want ErrUnknownProvider", err)
+	}
+
+	// An empty provider means LINE
+	_, user, err := svc.Login(ctx, "", LoginCredentials{IDToken: signLineToken(t, key, "k1", lineClaims(nil))})
+	if err != nil || user.LineUserID != "U123" || user.LineDisplayName != "Alice" {
+		t.Fatalf("line login = %+v, %v", user, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"event-manager/internal/models"
@@ -15,63 +16,111 @@ type InteractionService struct {
 	Repo   repository.InteractionRepository
 	Events repository.EventRepository
 	Users  repository.UserRepository
-	Cache  *CacheService
+	Cache  CacheService
+	Hub    *StatusHub
+	Audit  *AuditService
+
+	// PseudonymKey keys the pseudonyms that replace user IDs in redacted statuses
+	PseudonymKey []byte
 }
 
 // NewInteractionService creates an InteractionService with repository
-func NewInteractionService(repo repository.InteractionRepository, events repository.EventRepository, users repository.UserRepository, cache *CacheService) *InteractionService {
+func NewInteractionService(repo repository.InteractionRepository, events repository.EventRepository, users repository.UserRepository, cache CacheService, hub *StatusHub, audit *AuditService) *InteractionService {
 	return &InteractionService{
 		Repo:   repo,
 		Events: events,
 		Users:  users,
 		Cache:  cache,
+		Hub:    hub,
+		Audit:  audit,
 	}
 }
 
-func (s *InteractionService) HandleAction(ctx context.Context, eventID string, action *models.Interaction) error {
+// notifyChanged drops cached status for the event and wakes its stream subscribers
+func (s *InteractionService) notifyChanged(eventID string) {
+	s.Cache.Invalidate(eventID)
+	s.Hub.Publish(eventID)
+}
+
+// ActionResult describes the outcome of HandleAction
+type ActionResult struct {
+	RecordID string
+	Status   string                // LINEUP: SUCCESS | WAITLIST | CANCELLED
+	Promoted []*models.Interaction // LINEUP: registrations promoted from WAITLIST by a cancellation
+}
+
+func (s *InteractionService) HandleAction(ctx context.Context, eventID string, action *models.Interaction) (*ActionResult, error) {
 	action.Timestamp = time.Now()
 
-	var err error
+	// Names and pictures come from the user's profile, never from the client payload
+	name, picture, err := resolveIdentity(ctx, s.Users, eventID, action.UserID)
+	if err != nil {
+		return nil, err
+	}
+	action.UserDisplayName = name
+	action.UserPictureUrl = picture
+
+	var result *ActionResult
 	switch action.Type {
 	case models.InteractionTypeVote:
-		err = s.handleVote(ctx, eventID, action)
+		result, err = s.handleVote(ctx, eventID, action)
 	case models.InteractionTypeLineUp:
-		err = s.handleLineUp(ctx, eventID, action)
+		result, err = s.handleLineUp(ctx, eventID, action)
 	case models.InteractionTypeMemo:
-		err = s.handleMemo(ctx, eventID, action)
+		result, err = s.handleMemo(ctx, eventID, action)
 	default:
-		return errors.New("unknown action type")
+		return nil, errors.New("unknown action type")
 	}
 
-	// Invalidate cache after successful write
+	// Invalidate cache and notify streams after successful write
 	if err == nil {
-		s.Cache.Invalidate(eventID)
+		s.notifyChanged(eventID)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *InteractionService) handleVote(ctx context.Context, eventID string, action *models.Interaction) error {
+func (s *InteractionService) handleVote(ctx context.Context, eventID string, action *models.Interaction) (*ActionResult, error) {
+	event, err := s.Events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEventWindow(event, action.Timestamp); err != nil {
+		return nil, err
+	}
+
+	// Stores resolved option IDs in action.SelectedOptions
+	if err := validateVote(event, action); err != nil {
+		return nil, err
+	}
+
 	// Use composite ID: eventID_userID to ensure one vote per user per event
 	recordID := eventID + "_" + action.UserID
-	return s.Repo.CreateWithID(ctx, eventID, recordID, action)
+	previous, _ := s.Repo.GetByID(ctx, eventID, recordID)
+	if err := s.Repo.CreateWithID(ctx, eventID, recordID, action); err != nil {
+		return nil, err
+	}
+
+	action.ID = recordID
+	s.Audit.Record(ctx, action.UserID, eventID, recordID, AuditVote, previous, action)
+
+	return &ActionResult{RecordID: recordID}, nil
 }
 
-func (s *InteractionService) handleLineUp(ctx context.Context, eventID string, action *models.Interaction) error {
+func (s *InteractionService) handleLineUp(ctx context.Context, eventID string, action *models.Interaction) (*ActionResult, error) {
 	// Get event
 	event, err := s.Events.GetByID(ctx, eventID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !event.IsActive {
-		return errors.New("event is not active")
+		return nil, ErrEventNotActive
 	}
 
-	// Get all interactions for this event
-	allInteractions, err := s.Repo.GetByEventID(ctx, eventID)
-	if err != nil {
-		return err
+	if err := checkEventWindow(event, action.Timestamp); err != nil {
+		return nil, err
 	}
 
 	// Check if user is admin
@@ -79,105 +128,135 @@ func (s *InteractionService) handleLineUp(ctx context.Context, eventID string, a
 	isAdmin := user != nil && user.Role == "admin"
 
 	if action.Count > 0 {
-		// +1 Registration
-		userActiveCount := 0
-		totalActiveCount := 0
-		waitlistCount := 0
-
-		for _, rec := range allInteractions {
-			if rec.Type == models.InteractionTypeLineUp && rec.Status != "CANCELLED" {
-				totalActiveCount++
-				if rec.UserID == action.UserID {
-					userActiveCount++
-				}
-				if rec.Status == "WAITLIST" {
-					waitlistCount++
-				}
-			}
-		}
-
-		// Check user limit (admin bypasses)
-		maxPerUser := event.Config.MaxCountPerUser
-		if !isAdmin && maxPerUser > 0 && userActiveCount >= maxPerUser {
-			return errors.New("registration limit reached")
+		// +1 Registration: the repository checks capacity against the event config it
+		// reads under the event lock. Admin bypasses the per-user limit.
+		action.Timestamp = time.Now()
+		id, err := s.Repo.RegisterLineUp(ctx, eventID, action, isAdmin)
+		if err != nil {
+			return nil, err
 		}
 
-		// Determine status based on capacity
-		if totalActiveCount >= event.Config.MaxParticipants {
-			if event.Config.WaitlistLimit > 0 {
-				if waitlistCount >= event.Config.WaitlistLimit {
-					return errors.New("waitlist is full")
-				}
-			}
-			action.Status = "WAITLIST"
-		} else {
-			action.Status = "SUCCESS"
-		}
+		action.ID = id
+		s.Audit.Record(ctx, action.UserID, eventID, id, AuditLineUpRegister, nil, action)
 
-		action.Timestamp = time.Now()
-		_, err := s.Repo.Create(ctx, eventID, action)
-		return err
+		return &ActionResult{RecordID: id, Status: action.Status}, nil
 
 	} else if action.Count < 0 {
-		// -1 Cancellation (LIFO - Last In, First Out)
-		var latestRecord *models.Interaction
-		var latestTime time.Time
-
-		for _, rec := range allInteractions {
-			if rec.UserID == action.UserID &&
-				rec.Type == models.InteractionTypeLineUp &&
-				rec.Status != "CANCELLED" {
-				if latestRecord == nil || rec.Timestamp.After(latestTime) {
-					latestRecord = rec
-					latestTime = rec.Timestamp
-				}
-			}
+		// -1 Cancellation (LIFO - Last In, First Out); freed seats go to the oldest waitlisted
+		cancelled, promoted, err := s.Repo.CancelLineUp(ctx, eventID, action.UserID)
+		if err != nil {
+			return nil, err
 		}
 
-		if latestRecord == nil {
-			return errors.New("no active registration found")
-		}
+		s.auditCancellation(ctx, action.UserID, eventID, cancelled, promoted)
 
-		now := time.Now()
-		return s.Repo.Update(ctx, eventID, latestRecord.ID, map[string]interface{}{
-			"status":      "CANCELLED",
-			"cancelledAt": now,
-		})
+		return &ActionResult{RecordID: cancelled.ID, Status: cancelled.Status, Promoted: promoted}, nil
 	}
 
-	return errors.New("invalid count value")
+	return nil, errors.New("invalid count value")
 }
 
-func (s *InteractionService) handleMemo(ctx context.Context, eventID string, action *models.Interaction) error {
+func (s *InteractionService) handleMemo(ctx context.Context, eventID string, action *models.Interaction) (*ActionResult, error) {
+	// Get event config
+	event, err := s.Events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEventWindow(event, action.Timestamp); err != nil {
+		return nil, err
+	}
+
 	// Get user's memo count
 	userMemos, err := s.Repo.GetByUserAndType(ctx, eventID, action.UserID, models.InteractionTypeMemo)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Get event config
-	event, err := s.Events.GetByID(ctx, eventID)
+	if len(userMemos) >= event.Config.MaxCommentsPerUser {
+		return nil, errors.New("max comments reached")
+	}
+
+	id, err := s.Repo.Create(ctx, eventID, action)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(userMemos) >= event.Config.MaxCommentsPerUser {
-		return errors.New("max comments reached")
+	action.ID = id
+	s.Audit.Record(ctx, action.UserID, eventID, id, AuditMemoCreate, nil, action)
+
+	return &ActionResult{RecordID: id}, nil
+}
+
+// auditCancellation records a LINEUP cancellation and the waitlist promotions it caused
+func (s *InteractionService) auditCancellation(ctx context.Context, actor, eventID string, cancelled *models.Interaction, promoted []*models.Interaction) {
+	s.Audit.Record(ctx, actor, eventID, cancelled.ID, AuditLineUpCancel, nil, cancelled)
+	for _, rec := range promoted {
+		log.Printf("[LineUp] Promoted %s (user %s) from WAITLIST in event %s", rec.ID, rec.UserID, eventID)
+		s.Audit.Record(ctx, actor, eventID, rec.ID, AuditLineUpPromote,
+			map[string]string{"status": "WAITLIST"}, rec)
 	}
+}
 
-	_, err = s.Repo.Create(ctx, eventID, action)
-	return err
+// checkEventWindow rejects actions before Config.StartTime or after Config.EndTime (zero = unbounded)
+func checkEventWindow(event *models.Event, now time.Time) error {
+	if !event.Config.StartTime.IsZero() && now.Before(event.Config.StartTime) {
+		return ErrEventNotStarted
+	}
+	if !event.Config.EndTime.IsZero() && !now.Before(event.Config.EndTime) {
+		return ErrEventEnded
+	}
+	return nil
+}
+
+// Viewer identifies who an event status is built for or who performs a management action
+type Viewer struct {
+	UserID  string
+	IsAdmin bool
+}
+
+// redactsFor reports whether identities in the event's status are hidden from the viewer.
+// Organizers always see the full roster.
+func (s *InteractionService) redactsFor(ctx context.Context, event *models.Event, viewer Viewer) (bool, error) {
+	if !redactsIdentities(event) {
+		return false, nil
+	}
+	manager, err := canManageEvent(ctx, s.Events, event, viewer)
+	return !manager, err
 }
 
-func (s *InteractionService) GetEventStatus(ctx context.Context, eventID string) (map[string]interface{}, error) {
+func (s *InteractionService) GetEventStatus(ctx context.Context, eventID string, viewer Viewer) (map[string]interface{}, error) {
 	log.Printf("[GetEventStatus] Fetching status for event: %s", eventID)
 
+	// The event config decides what the viewer may see
+	event, err := s.Events.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("[GetEventStatus] ERROR getting event: %v", err)
+		return nil, err
+	}
+
+	redact, err := s.redactsFor(ctx, event, viewer)
+	if err != nil {
+		return nil, err
+	}
+	view := "full"
+	if redact {
+		view = "user:" + viewer.UserID
+	}
+
 	// Check cache first
-	if cached, found := s.Cache.Get(eventID); found {
-		log.Printf("[GetEventStatus] Cache HIT for event: %s", eventID)
+	if cached, found := s.Cache.Get(eventID, view); found {
+		log.Printf("[GetEventStatus] Cache HIT for event: %s (%s)", eventID, view)
 		return cached, nil
 	}
-	log.Printf("[GetEventStatus] Cache MISS for event: %s", eventID)
+	log.Printf("[GetEventStatus] Cache MISS for event: %s (%s)", eventID, view)
+
+	// Read the revision before the records so it never runs ahead of them
+	revision, err := s.Repo.GetRevision(ctx, eventID)
+	if err != nil {
+		log.Printf("[GetEventStatus] ERROR getting revision: %v", err)
+		return nil, err
+	}
 
 	// Fetch all records
 	interactions, err := s.Repo.GetByEventID(ctx, eventID)
@@ -188,36 +267,84 @@ func (s *InteractionService) GetEventStatus(ctx context.Context, eventID string)
 
 	log.Printf("[GetEventStatus] Successfully fetched %d total records", len(interactions))
 
-	// Build result
-	result := make(map[string]interface{})
-	list := make([]map[string]interface{}, 0, len(interactions))
+	result := s.buildStatus(event, viewer, redact, interactions, revision)
+
+	log.Printf("[GetEventStatus] Returning %d records for event: %s", len(interactions), eventID)
 
+	// Cache the result
+	s.Cache.Set(eventID, view, result)
+
+	return result, nil
+}
+
+// GetEventStatusSince returns only the records created, updated or cancelled after
+// revision since, plus the revision to pass on the next call. Not cached.
+func (s *InteractionService) GetEventStatusSince(ctx context.Context, eventID string, viewer Viewer, since int64) (map[string]interface{}, error) {
+	event, err := s.Events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.Repo.GetRevision(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	interactions, err := s.Repo.GetByEventIDSince(ctx, eventID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	redact, err := s.redactsFor(ctx, event, viewer)
+	if err != nil {
+		return nil, err
+	}
+	result := s.buildStatus(event, viewer, redact, interactions, revision)
+	result["since"] = since
+	return result, nil
+}
+
+// buildStatus renders records for the viewer. The returned revision is the highest of
+// the given revision and the records' own, so no record is sent twice on the next call.
+func (s *InteractionService) buildStatus(event *models.Event, viewer Viewer, redact bool, interactions []*models.Interaction, revision int64) map[string]interface{} {
+	// Votes are stored as option IDs; resolve them to the current labels
+	voteOptions := voteOptionsOf(event.Config)
+
+	list := make([]map[string]interface{}, 0, len(interactions))
 	for _, rec := range interactions {
 		recMap := map[string]interface{}{
-			"id":              rec.ID,
-			"type":            rec.Type,
-			"userId":          rec.UserID,
-			"userDisplayName": rec.UserDisplayName,
-			"userPictureUrl":  rec.UserPictureUrl,
-			"timestamp":       rec.Timestamp,
-			"status":          rec.Status,
-			"selectedOptions": rec.SelectedOptions,
-			"count":           rec.Count,
-			"note":            rec.Note,
-			"content":         rec.Content,
-			"clapCount":       rec.ClapCount,
+			"id":                rec.ID,
+			"type":              rec.Type,
+			"userId":            rec.UserID,
+			"userDisplayName":   rec.UserDisplayName,
+			"userPictureUrl":    rec.UserPictureUrl,
+			"timestamp":         rec.Timestamp,
+			"queuedAt":          rec.QueueTime(),
+			"revision":          rec.Revision,
+			"status":            rec.Status,
+			"selectedOptions":   optionLabels(voteOptions, rec.SelectedOptions),
+			"selectedOptionIds": rec.SelectedOptions,
+			"count":             rec.Count,
+			"note":              rec.Note,
+			"promotedAt":        rec.PromotedAt,
+			"isGuest":           strings.HasPrefix(rec.UserID, GuestUserPrefix),
+			"content":           rec.Content,
+			"clapCount":         rec.ClapCount,
+		}
+		if redact && rec.UserID != viewer.UserID {
+			redactIdentity(s.PseudonymKey, event, rec, recMap)
 		}
 		list = append(list, recMap)
-	}
 
-	log.Printf("[GetEventStatus] Returning %d records for event: %s", len(list), eventID)
-
-	result["records"] = list
-
-	// Cache the result
-	s.Cache.Set(eventID, result)
+		if rec.Revision > revision {
+			revision = rec.Revision
+		}
+	}
 
-	return result, nil
+	return map[string]interface{}{
+		"records":  list,
+		"revision": revision,
+	}
 }
 
 func (s *InteractionService) UpdateRegistrationNote(ctx context.Context, eventID, recordID, userID, note string) error {
@@ -236,7 +363,9 @@ func (s *InteractionService) UpdateRegistrationNote(ctx context.Context, eventID
 	})
 
 	if err == nil {
-		s.Cache.Invalidate(eventID)
+		s.Audit.Record(ctx, userID, eventID, recordID, AuditNoteUpdate,
+			map[string]string{"note": record.Note}, map[string]string{"note": note})
+		s.notifyChanged(eventID)
 	}
 
 	return err
@@ -258,13 +387,15 @@ func (s *InteractionService) UpdateMemoContent(ctx context.Context, eventID, rec
 	})
 
 	if err == nil {
-		s.Cache.Invalidate(eventID)
+		s.Audit.Record(ctx, userID, eventID, recordID, AuditMemoUpdate,
+			map[string]string{"content": record.Content}, map[string]string{"content": content})
+		s.notifyChanged(eventID)
 	}
 
 	return err
 }
 
-func (s *InteractionService) IncrementClapCount(ctx context.Context, eventID, recordID string) error {
+func (s *InteractionService) IncrementClapCount(ctx context.Context, eventID, recordID, userID string) error {
 	// Get current clap count
 	record, err := s.Repo.GetByID(ctx, eventID, recordID)
 	if err != nil {
@@ -281,7 +412,9 @@ func (s *InteractionService) IncrementClapCount(ctx context.Context, eventID, re
 	})
 
 	if err == nil {
-		s.Cache.Invalidate(eventID)
+		s.Audit.Record(ctx, userID, eventID, recordID, AuditClap,
+			map[string]int{"clapCount": record.ClapCount}, map[string]int{"clapCount": newCount})
+		s.notifyChanged(eventID)
 	}
 
 	return err
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func newTestInteractionService(events *repository.MemoryEventRepository, users *repository.MemoryUserRepository) (*InteractionService, *repository.MemoryInteractionRepository) {
+	repo := repository.NewMemoryInteractionRepository(events)
+	return NewInteractionService(repo, events, users, NewMemoryCacheService(30*time.Second), NewStatusHub(), NewAuditService(repository.NewMemoryAuditRepository(), events)), repo
+}
+
+// profileUser is a logged-in user whose name and picture HandleAction stores on records
+func profileUser(id, name, picture string) *models.User {
+	return &models.User{LineUserID: id, LineDisplayName: name, PictureURL: picture, Role: RoleUser}
+}
+
+func lineUpEvent(id string, config models.EventConfig) *models.Event {
+	return &models.Event{
+		EventID:  id,
+		Type:     models.EventTypeLineUp,
+		IsActive: true,
+		Config:   config,
+	}
+}
+
+func countStatuses(t *testing.T, repo *repository.MemoryInteractionRepository, eventID string) map[string]int {
+	t.Helper()
+	records, err := repo.GetByEventID(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetByEventID: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, rec := range records {
+		counts[rec.Status]++
+	}
+	return counts
+}
+
+func TestHandleLineUpConcurrentNoOverbooking(t *testing.T) {
+	const (
+		attempts        = 500
+		maxParticipants = 50
+		waitlistLimit   = 20
+	)
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{
+		MaxParticipants: maxParticipants,
+		WaitlistLimit:   waitlistLimit,
+		MaxCountPerUser: 1,
+	}))
+	svc, repo := newTestInteractionService(events, newTestUserRepo())
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	rejected := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+				UserID: fmt.Sprintf("user-%d", i),
+				Type:   models.InteractionTypeLineUp,
+				Count:  1,
+			})
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, repository.ErrWaitlistFull) {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			rejected++
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	counts := countStatuses(t, repo, "ev1")
+	if counts["SUCCESS"] != maxParticipants {
+		t.Errorf("SUCCESS = %d, want %d", counts["SUCCESS"], maxParticipants)
+	}
+	if counts["WAITLIST"] != waitlistLimit {
+		t.Errorf("WAITLIST = %d, want %d", counts["WAITLIST"], waitlistLimit)
+	}
+	if rejected != attempts-maxParticipants-waitlistLimit {
+		t.Errorf("rejected = %d, want %d", rejected, attempts-maxParticipants-waitlistLimit)
+	}
+}
+
+func TestHandleLineUpPerUserLimit(t *testing.T) {
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 10, MaxCountPerUser: 2}))
+	users := newTestUserRepo(&models.User{LineUserID: "admin", Role: "admin"})
+	svc, _ := newTestInteractionService(events, users)
+
+	register := func(uid string) error {
+		_, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+			UserID: uid,
+			Type:   models.InteractionTypeLineUp,
+			Count:  1,
+		})
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := register("u1"); err != nil {
+			t.Fatalf("register %d: %v", i, err)
+		}
+	}
+	if err := register("u1"); !errors.Is(err, repository.ErrRegistrationLimitReached) {
+		t.Errorf("third registration err = %v, want ErrRegistrationLimitReached", err)
+	}
+
+	// Admin bypasses MaxCountPerUser
+	for i := 0; i < 3; i++ {
+		if err := register("admin"); err != nil {
+			t.Fatalf("admin register %d: %v", i, err)
+		}
+	}
+}
+
+func TestHandleLineUpInactiveEvent(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 10})
+	event.IsActive = false
+	svc, _ := newTestInteractionService(newTestEventRepo(event), newTestUserRepo())
+
+	_, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+		UserID: "u1",
+		Type:   models.InteractionTypeLineUp,
+		Count:  1,
+	})
+	if err == nil {
+		t.Fatal("expected error for inactive event")
+	}
+}
+
+func TestHandleLineUpCancelPromotesWaitlist(t *testing.T) {
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 2}))
+	svc, repo := newTestInteractionService(events, newTestUserRepo())
+
+	lineUp := func(uid string, count int) *ActionResult {
+		t.Helper()
+		result, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+			UserID: uid,
+			Type:   models.InteractionTypeLineUp,
+			Count:  count,
+		})
+		if err != nil {
+			t.Fatalf("%s count=%d: %v", uid, count, err)
+		}
+		return result
+	}
+
+	lineUp("a", 1)
+	lineUp("b", 1)
+	if r := lineUp("c", 1); r.Status != "WAITLIST" {
+		t.Fatalf("c status = %s, want WAITLIST", r.Status)
+	}
+	lineUp("d", 1)
+
+	result := lineUp("a", -1)
+	if result.Status != "CANCELLED" {
+		t.Errorf("cancel status = %s, want CANCELLED", result.Status)
+	}
+	if len(result.Promoted) != 1 || result.Promoted[0].UserID != "c" {
+		t.Fatalf("promoted = %+v, want only user c", result.Promoted)
+	}
+	if result.Promoted[0].PromotedAt == nil {
+		t.Error("promotedAt not set")
+	}
+
+	counts := countStatuses(t, repo, "ev1")
+	if counts["SUCCESS"] != 2 || counts["WAITLIST"] != 1 || counts["CANCELLED"] != 1 {
+		t.Errorf("statuses = %v", counts)
+	}
+
+	// Cancelling a waitlisted registration frees no seat
+	if result := lineUp("d", -1); len(result.Promoted) != 0 {
+		t.Errorf("promoted = %+v, want none", result.Promoted)
+	}
+}
+
+func TestHandleLineUpCancelWithoutRegistration(t *testing.T) {
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 2}))
+	svc, _ := newTestInteractionService(events, newTestUserRepo())
+
+	_, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+		UserID: "a",
+		Type:   models.InteractionTypeLineUp,
+		Count:  -1,
+	})
+	if !errors.Is(err, repository.ErrNoActiveRegistration) {
+		t.Errorf("err = %v, want ErrNoActiveRegistration", err)
+	}
+}
+
+func TestGetEventStatusSince(t *testing.T) {
+	event := &models.Event{
+		EventID:  "ev1",
+		Type:     models.EventTypeMemo,
+		IsActive: true,
+		Config:   models.EventConfig{MaxCommentsPerUser: 10},
+	}
+	svc, _ := newTestInteractionService(newTestEventRepo(event), newTestUserRepo())
+	ctx := context.Background()
+	viewer := Viewer{UserID: "u1"}
+
+	post := func(content string) string {
+		t.Helper()
+		result, err := svc.HandleAction(ctx, "ev1", &models.Interaction{UserID: "u1", Type: models.InteractionTypeMemo, Content: content})
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		return result.RecordID
+	}
+
+	first := post("one")
+	post("two")
+
+	full, err := svc.GetEventStatus(ctx, "ev1", viewer)
+	if err != nil {
+		t.Fatalf("GetEventStatus: %v", err)
+	}
+	cursor := full["revision"].(int64)
+
+	post("three")
+	if err := svc.UpdateMemoContent(ctx, "ev1", first, "u1", "one (edited)"); err != nil {
+		t.Fatalf("UpdateMemoContent: %v", err)
+	}
+
+	delta, err := svc.GetEventStatusSince(ctx, "ev1", viewer, cursor)
+	if err != nil {
+		t.Fatalf("GetEventStatusSince: %v", err)
+	}
+	records := delta["records"].([]map[string]interface{})
+	if len(records) != 2 || records[0]["content"] != "three" || records[1]["content"] != "one (edited)" {
+		t.Fatalf("delta records = %v", records)
+	}
+
+	next := delta["revision"].(int64)
+	if next <= cursor {
+		t.Errorf("revision did not advance: %d -> %d", cursor, next)
+	}
+
+	empty, _ := svc.GetEventStatusSince(ctx, "ev1", viewer, next)
+	if n := len(empty["records"].([]map[string]interface{})); n != 0 {
+		t.Errorf("records after latest revision = %d, want 0", n)
+	}
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	DefaultKeyRotation = 30 * 24 * time.Hour
+	DefaultKeyGrace    = 24 * time.Hour
+
+	// keyReloadInterval limits how often an unknown kid triggers a reload, e.g. right after
+	// another replica rotated
+	keyReloadInterval = 10 * time.Second
+)
+
+var (
+	ErrWeakJWTSecret = errors.New("JWT_SECRET must be set to a random value of at least 32 characters")
+	ErrUnknownKey    = errors.New("unknown signing key")
+)
+
+// insecureSecrets are placeholder values from old templates that must not be used
+var insecureSecrets = map[string]bool{
+	"default-secret-do-not-use-in-prod": true,
+	"your-secret-key-here":              true,
+	"your-jwt-secret-change-this":       true,
+}
+
+// KeyManagerConfig configures access token signing
+type KeyManagerConfig struct {
+	Algorithm string        // RS256 or EdDSA
+	Rotation  time.Duration // How long a key signs before a new one replaces it
+	Grace     time.Duration // How long a replaced key keeps verifying tokens
+	Secret    string        // Encrypts private keys at rest
+}
+
+// LoadKeyManagerConfigFromEnv reads JWT_SECRET, JWT_SIGNING_ALG, JWT_KEY_ROTATION and
+// JWT_KEY_GRACE. Missing or placeholder secrets are an error rather than a silent default.
+func LoadKeyManagerConfigFromEnv() (KeyManagerConfig, error) {
+	cfg := KeyManagerConfig{
+		Algorithm: os.Getenv("JWT_SIGNING_ALG"),
+		Rotation:  DefaultKeyRotation,
+		Grace:     DefaultKeyGrace,
+		Secret:    os.Getenv("JWT_SECRET"),
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgEdDSA
+	}
+	for env, d := range map[string]*time.Duration{"JWT_KEY_ROTATION": &cfg.Rotation, "JWT_KEY_GRACE": &cfg.Grace} {
+		if v := os.Getenv(env); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", env, err)
+			}
+			*d = parsed
+		}
+	}
+	return cfg, nil
+}
+
+type signingKey struct {
+	id        string
+	alg       string
+	private   interface{} // *rsa.PrivateKey or ed25519.PrivateKey
+	public    interface{} // *rsa.PublicKey or ed25519.PublicKey
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// KeyManager signs access tokens with the newest key and verifies them against every
+// unexpired key by kid. Keys are shared through the database so all replicas agree.
+type KeyManager struct {
+	Repo      repository.SigningKeyRepository
+	Algorithm string
+	Rotation  time.Duration
+	Grace     time.Duration
+
+	aead cipher.AEAD
+
+	mu         sync.RWMutex
+	keys       []*signingKey // Newest first
+	loadedAt   time.Time
+	reloadLock sync.Mutex
+}
+
+func NewKeyManager(repo repository.SigningKeyRepository, cfg KeyManagerConfig) (*KeyManager, error) {
+	if len(cfg.Secret) < 32 || insecureSecrets[cfg.Secret] {
+		return nil, ErrWeakJWTSecret
+	}
+	if cfg.Algorithm != AlgRS256 && cfg.Algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported JWT_SIGNING_ALG %q (use %s or %s)", cfg.Algorithm, AlgRS256, AlgEdDSA)
+	}
+	if cfg.Rotation <= 0 {
+		return nil, errors.New("key rotation interval must be positive")
+	}
+	if cfg.Grace < AccessTokenTTL {
+		return nil, fmt.Errorf("key grace period must be at least the access token lifetime (%s)", AccessTokenTTL)
+	}
+
+	sum := sha256.Sum256([]byte(cfg.Secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyManager{
+		Repo:      repo,
+		Algorithm: cfg.Algorithm,
+		Rotation:  cfg.Rotation,
+		Grace:     cfg.Grace,
+		aead:      aead,
+	}, nil
+}
+
+// Load reads the keys from the database and creates a new signing key if there is none
+// or the newest one is due for rotation
+func (m *KeyManager) Load(ctx context.Context) error {
+	if err := m.reload(ctx); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	due := len(m.keys) == 0 || time.Since(m.keys[0].createdAt) >= m.Rotation || m.keys[0].alg != m.Algorithm
+	m.mu.RUnlock()
+
+	if due {
+		return m.Rotate(ctx)
+	}
+	return nil
+}
+
+// Rotate creates a new signing key. Tokens signed by earlier keys stay valid until their
+// keys expire.
+func (m *KeyManager) Rotate(ctx context.Context) error {
+	var der []byte
+	var err error
+	switch m.Algorithm {
+	case AlgRS256:
+		var key *rsa.PrivateKey
+		if key, err = rsa.GenerateKey(rand.Reader, 2048); err == nil {
+			der, err = x509.MarshalPKCS8PrivateKey(key)
+		}
+	case AlgEdDSA:
+		var key ed25519.PrivateKey
+		if _, key, err = ed25519.GenerateKey(rand.Reader); err == nil {
+			der, err = x509.MarshalPKCS8PrivateKey(key)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("generate signing key: %w", err)
+	}
+
+	now := time.Now()
+	key := &models.SigningKey{
+		ID:        uuid.New().String(),
+		Algorithm: m.Algorithm,
+		CreatedAt: now,
+		ExpiresAt: now.Add(m.Rotation + m.Grace),
+	}
+	if key.PrivateKey, err = m.seal(key.ID, der); err != nil {
+		return err
+	}
+	if err := m.Repo.Create(ctx, key); err != nil {
+		return err
+	}
+	log.Printf("[Keys] Created %s signing key %s", key.Algorithm, key.ID)
+
+	return m.reload(ctx)
+}
+
+// Run keeps the key set current: it picks up keys created by other replicas, rotates
+// when due and deletes expired keys
+func (m *KeyManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Load(ctx); err != nil {
+				log.Printf("[Keys] Failed to refresh signing keys: %v", err)
+				continue
+			}
+			if err := m.Repo.DeleteExpired(ctx, time.Now()); err != nil {
+				log.Printf("[Keys] Failed to delete expired keys: %v", err)
+			}
+		}
+	}
+}
+
+func (m *KeyManager) reload(ctx context.Context) error {
+	stored, err := m.Repo.ListUnexpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0, len(stored))
+	for _, s := range stored {
+		key, err := m.open(s)
+		if err != nil {
+			// Usually a JWT_SECRET change; the key cannot be used but others may still work
+			log.Printf("[Keys] Skipping signing key %s: %v", s.ID, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Sign signs the claims with the newest key, setting the kid header
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	m.mu.RLock()
+	if len(m.keys) == 0 {
+		m.mu.RUnlock()
+		return "", errors.New("no signing key loaded")
+	}
+	key := m.keys[0]
+	m.mu.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.alg), claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// Parse verifies a token signed by any unexpired key and returns its claims
+func (m *KeyManager) Parse(tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.keyfunc,
+		jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (m *KeyManager) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key := m.find(kid)
+	if key == nil && m.reloadForKid() {
+		key = m.find(kid)
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return key.public, nil
+}
+
+func (m *KeyManager) find(kid string) *signingKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	now := time.Now()
+	for _, key := range m.keys {
+		if key.id == kid && now.Before(key.expiresAt) {
+			return key
+		}
+	}
+	return nil
+}
+
+// reloadForKid reloads the keys if that has not been done recently, so tokens signed by a
+// key another replica just created are accepted
+func (m *KeyManager) reloadForKid() bool {
+	m.reloadLock.Lock()
+	defer m.reloadLock.Unlock()
+
+	m.mu.RLock()
+	recent := time.Since(m.loadedAt) < keyReloadInterval
+	m.mu.RUnlock()
+	if recent {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.reload(ctx); err != nil {
+		log.Printf("[Keys] Failed to reload signing keys: %v", err)
+		return false
+	}
+	return true
+}
+
+// JSONWebKey is a public key in JWK format
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the public keys of every unexpired key, newest first
+func (m *KeyManager) JWKS() JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JSONWebKey, 0, len(m.keys))}
+	for _, key := range m.keys {
+		jwk := JSONWebKey{Kid: key.id, Alg: key.alg, Use: "sig"}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// seal encrypts a private key, binding it to its kid
+func (m *KeyManager) seal(kid string, der []byte) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return m.aead.Seal(nonce, nonce, der, []byte(kid)), nil
+}
+
+func (m *KeyManager) open(stored *models.SigningKey) (*signingKey, error) {
+	n := m.aead.NonceSize()
+	if len(stored.PrivateKey) < n {
+		return nil, errors.New("encrypted key too short")
+	}
+	der, err := m.aead.Open(nil, stored.PrivateKey[:n], stored.PrivateKey[n:], []byte(stored.ID))
+	if err != nil {
+		return nil, errors.New("cannot decrypt key with the current JWT_SECRET")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		id:        stored.ID,
+		alg:       stored.Algorithm,
+		private:   parsed,
+		createdAt: stored.CreatedAt,
+		expiresAt: stored.ExpiresAt,
+	}
+	switch priv := parsed.(type) {
+	case *rsa.PrivateKey:
+		if stored.Algorithm != AlgRS256 {
+			return nil, fmt.Errorf("RSA key stored as %s", stored.Algorithm)
+		}
+		key.public = &priv.PublicKey
+	case ed25519.PrivateKey:
+		if stored.Algorithm != AlgEdDSA {
+			return nil, fmt.Errorf("Ed25519 key stored as %s", stored.Algorithm)
+		}
+		key.public = priv.Public()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestKeyManagerWithRepo(t *testing.T, repo repository.SigningKeyRepository, alg string) *KeyManager {
+	t.Helper()
+	keys, err := NewKeyManager(repo, KeyManagerConfig{
+		Algorithm: alg,
+		Rotation:  time.Hour,
+		Grace:     time.Hour,
+		Secret:    testJWTSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func newTestKeyManager(t *testing.T, alg string) *KeyManager {
+	return newTestKeyManagerWithRepo(t, repository.NewMemorySigningKeyRepository(), alg)
+}
+
+// expiredKeyRepo hides one key from ListUnexpired as if it had expired
+type expiredKeyRepo struct {
+	repository.SigningKeyRepository
+	expired string
+}
+
+func (r *expiredKeyRepo) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	keys, err := r.SigningKeyRepository.ListUnexpired(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	unexpired := keys[:0]
+	for _, k := range keys {
+		if k.ID != r.expired {
+			unexpired = append(unexpired, k)
+		}
+	}
+	return unexpired, nil
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"uid": "U1", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func TestNewKeyManagerRejectsInsecureConfig(t *testing.T) {
+	for name, cfg := range map[string]KeyManagerConfig{
+		"empty secret":       {Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: time.Hour},
+		"short secret":       {Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: time.Hour, Secret: "short"},
+		"placeholder secret": {Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: time.Hour, Secret: "default-secret-do-not-use-in-prod"},
+	} {
+		if _, err := NewKeyManager(repository.NewMemorySigningKeyRepository(), cfg); !errors.Is(err, ErrWeakJWTSecret) {
+			t.Errorf("%s: err = %v, want ErrWeakJWTSecret", name, err)
+		}
+	}
+
+	for name, cfg := range map[string]KeyManagerConfig{
+		"HS256":       {Algorithm: "HS256", Rotation: time.Hour, Grace: time.Hour, Secret: testJWTSecret},
+		"short grace": {Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: time.Minute, Secret: testJWTSecret},
+	} {
+		if _, err := NewKeyManager(repository.NewMemorySigningKeyRepository(), cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestKeyManagerSignAndParse(t *testing.T) {
+	for _, alg := range []string{AlgEdDSA, AlgRS256} {
+		keys := newTestKeyManager(t, alg)
+
+		token, err := keys.Sign(testClaims())
+		if err != nil {
+			t.Fatalf("%s: Sign: %v", alg, err)
+		}
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		if err != nil || parsed.Header["alg"] != alg || parsed.Header["kid"] != keys.JWKS().Keys[0].Kid {
+			t.Fatalf("%s: header = %v, %v", alg, parsed.Header, err)
+		}
+
+		claims, err := keys.Parse(token)
+		if err != nil || claims["uid"] != "U1" {
+			t.Fatalf("%s: Parse = %v, %v", alg, claims, err)
+		}
+
+		// Tampered and unsigned tokens are rejected
+		if _, err := keys.Parse(token[:len(token)-4] + "AAAA"); err == nil {
+			t.Errorf("%s: tampered token accepted", alg)
+		}
+		none, _ := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		if _, err := keys.Parse(none); err == nil {
+			t.Errorf("%s: unsigned token accepted", alg)
+		}
+	}
+}
+
+func TestKeyManagerRotationKeepsOldKeysDuringGrace(t *testing.T) {
+	repo := repository.NewMemorySigningKeyRepository()
+	keys := newTestKeyManagerWithRepo(t, repo, AlgEdDSA)
+	ctx := context.Background()
+
+	oldToken, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKid := keys.JWKS().Keys[0].Kid
+
+	if err := keys.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[1].Kid != oldKid || jwks.Keys[0].Kty != "OKP" || jwks.Keys[0].X == "" {
+		t.Fatalf("JWKS after rotation = %+v", jwks)
+	}
+
+	newToken, _ := keys.Sign(testClaims())
+	if parsed, _, _ := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{}); parsed.Header["kid"] == oldKid {
+		t.Fatal("new tokens are still signed with the old key")
+	}
+	if _, err := keys.Parse(oldToken); err != nil {
+		t.Fatalf("token signed before rotation rejected during grace: %v", err)
+	}
+
+	// Once the old key expires it no longer verifies and drops out of the JWKS
+	keys.Repo = &expiredKeyRepo{SigningKeyRepository: repo, expired: oldKid}
+	if err := keys.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Parse(oldToken); err == nil {
+		t.Fatal("token of an expired key accepted")
+	}
+	if n := len(keys.JWKS().Keys); n != 1 {
+		t.Fatalf("JWKS has %d keys after expiry, want 1", n)
+	}
+}
+
+func TestKeyManagerSharesKeysBetweenReplicas(t *testing.T) {
+	repo := repository.NewMemorySigningKeyRepository()
+	a := newTestKeyManagerWithRepo(t, repo, AlgEdDSA)
+	b := newTestKeyManagerWithRepo(t, repo, AlgEdDSA)
+	if a.JWKS().Keys[0].Kid != b.JWKS().Keys[0].Kid {
+		t.Fatal("second replica created its own key instead of loading the shared one")
+	}
+
+	// A key rotated on one replica is picked up by the other when it sees the new kid
+	if err := a.Rotate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	token, _ := a.Sign(testClaims())
+	b.mu.Lock()
+	b.loadedAt = time.Time{}
+	b.mu.Unlock()
+	if _, err := b.Parse(token); err != nil {
+		t.Fatalf("replica rejected token signed with a freshly rotated key: %v", err)
+	}
+
+	// Keys encrypted with another secret cannot be used
+	other, err := NewKeyManager(repo, KeyManagerConfig{Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: time.Hour, Secret: strings.Repeat("x", 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(other.JWKS().Keys); n != 0 {
+		t.Fatalf("decrypted %d keys with the wrong secret", n)
+	}
+}
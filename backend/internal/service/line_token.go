@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	LineIssuer    = "https://access.line.me"
+	LineJWKSURL   = "https://api.line.me/oauth2/v2.1/certs"
+	LineVerifyURL = "https://api.line.me/oauth2/v2.1/verify"
+)
+
+var (
+	// ErrLineKeyUnavailable means the token could not be checked locally (the JWKS could not
+	// be fetched or does not contain the token's kid), so the remote endpoint is asked instead
+	ErrLineKeyUnavailable = errors.New("line signing key unavailable")
+	ErrInvalidLineToken   = errors.New("invalid line token")
+)
+
+// LineKeySource resolves the public key LINE used to sign an ID token
+type LineKeySource interface {
+	PublicKey(ctx context.Context, kid string) (*ecdsa.PublicKey, error)
+}
+
+// StaticLineKeys is a fixed set of keys by kid, used in tests to sign tokens locally
+type StaticLineKeys map[string]*ecdsa.PublicKey
+
+func (k StaticLineKeys) PublicKey(ctx context.Context, kid string) (*ecdsa.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kid %q", ErrLineKeyUnavailable, kid)
+	}
+	return key, nil
+}
+
+// JWKSKeySource fetches LINE's ES256 keys and caches them for TTL. An unknown kid triggers
+// a refetch (at most once per MinRefresh) so key rotations are picked up early.
+type JWKSKeySource struct {
+	URL        string
+	Client     *http.Client
+	TTL        time.Duration
+	MinRefresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*ecdsa.PublicKey
+	fetchedAt time.Time
+}
+
+func NewJWKSKeySource(jwksURL string, client *http.Client, ttl time.Duration) *JWKSKeySource {
+	return &JWKSKeySource{
+		URL:        jwksURL,
+		Client:     client,
+		TTL:        ttl,
+		MinRefresh: time.Minute,
+	}
+}
+
+func (s *JWKSKeySource) PublicKey(ctx context.Context, kid string) (*ecdsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.fetchedAt)
+	if key, ok := s.keys[kid]; ok && age < s.TTL {
+		return key, nil
+	}
+	if s.keys == nil || age >= s.MinRefresh {
+		keys, err := s.fetch(ctx)
+		if err != nil {
+			if key, ok := s.keys[kid]; ok {
+				// Keep using a stale key rather than failing while LINE is unreachable
+				log.Printf("[LINE VERIFY] JWKS refresh failed, using cached key: %v", err)
+				return key, nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrLineKeyUnavailable, err)
+		}
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kid %q", ErrLineKeyUnavailable, kid)
+	}
+	return key, nil
+}
+
+func (s *JWKSKeySource) fetch(ctx context.Context) (map[string]*ecdsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks returned status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*ecdsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "EC" || k.Crv != "P-256" {
+			continue
+		}
+		key, err := parseP256Key(k.X, k.Y)
+		if err != nil {
+			log.Printf("[LINE VERIFY] Skipping JWKS key %s: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	log.Printf("[LINE VERIFY] Loaded %d keys from JWKS", len(keys))
+	return keys, nil
+}
+
+func parseP256Key(x, y string) (*ecdsa.PublicKey, error) {
+	xb, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yb, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+	if len(xb) != 32 || len(yb) != 32 {
+		return nil, errors.New("invalid P-256 coordinate length")
+	}
+
+	// ecdh rejects points that are not on the curve
+	point := append(append([]byte{4}, xb...), yb...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xb),
+		Y:     new(big.Int).SetBytes(yb),
+	}, nil
+}
+
+type lineIDTokenClaims struct {
+	jwt.RegisteredClaims
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+	Email   string `json:"email"`
+	Nonce   string `json:"nonce"`
+}
+
+// LineTokenVerifier checks LINE ID tokens locally (ES256 signature, iss, aud, exp, nonce)
+// and falls back to LINE's verify endpoint when no signing key is available
+type LineTokenVerifier struct {
+	ChannelID string
+	Keys      LineKeySource
+	Client    *http.Client
+	VerifyURL string
+}
+
+func NewLineTokenVerifier(channelID string) *LineTokenVerifier {
+	if channelID == "" {
+		log.Printf("[LINE VERIFY] LINE_CHANNEL_ID is not set; every LINE login will be rejected")
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &LineTokenVerifier{
+		ChannelID: channelID,
+		Keys:      NewJWKSKeySource(LineJWKSURL, client, 24*time.Hour),
+		Client:    client,
+		VerifyURL: LineVerifyURL,
+	}
+}
+
+// Verify returns the token's profile. An empty nonce skips the nonce check. Without a
+// ChannelID the audience cannot be checked, so every token is rejected.
+func (v *LineTokenVerifier) Verify(ctx context.Context, idToken, nonce string) (*LineTokenResponse, error) {
+	if v.ChannelID == "" {
+		log.Printf("[LINE VERIFY] Token rejected: LINE_CHANNEL_ID is not set")
+		return nil, ErrInvalidLineToken
+	}
+
+	profile, err := v.verifyLocal(ctx, idToken, nonce)
+	if errors.Is(err, ErrLineKeyUnavailable) {
+		log.Printf("[LINE VERIFY] Local verification unavailable, falling back to LINE API: %v", err)
+		return v.verifyRemote(ctx, idToken, nonce)
+	}
+	if err != nil {
+		log.Printf("[LINE VERIFY] Token rejected: %v", err)
+		return nil, ErrInvalidLineToken
+	}
+	log.Printf("[LINE VERIFY] Verified token locally for user: %s", profile.Sub)
+	return profile, nil
+}
+
+func (v *LineTokenVerifier) verifyLocal(ctx context.Context, idToken, nonce string) (*LineTokenResponse, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(LineIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithAudience(v.ChannelID),
+	}
+
+	var claims lineIDTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.Keys.PublicKey(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+
+	profile := &LineTokenResponse{
+		Iss:     claims.Issuer,
+		Sub:     claims.Subject,
+		Exp:     claims.ExpiresAt.Unix(),
+		Name:    claims.Name,
+		Picture: claims.Picture,
+		Email:   claims.Email,
+		Nonce:   claims.Nonce,
+	}
+	if len(claims.Audience) > 0 {
+		profile.Aud = claims.Audience[0]
+	}
+	if claims.IssuedAt != nil {
+		profile.Iat = claims.IssuedAt.Unix()
+	}
+	return profile, nil
+}
+
+func (v *LineTokenVerifier) verifyRemote(ctx context.Context, idToken, nonce string) (*LineTokenResponse, error) {
+	data := url.Values{}
+	data.Set("id_token", idToken)
+	data.Set("client_id", v.ChannelID)
+	if nonce != "" {
+		data.Set("nonce", nonce)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.VerifyURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		log.Printf("[LINE VERIFY] HTTP request failed: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("[LINE VERIFY] LINE API returned status %d. Body: %s", resp.StatusCode, string(body))
+		return nil, ErrInvalidLineToken
+	}
+
+	var tokenResp LineTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		log.Printf("[LINE VERIFY] Failed to decode response: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[LINE VERIFY] Successfully verified token remotely for user: %s", tokenResp.Sub)
+	return &tokenResp, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testChannelID = "1234567890"
+
+func newTestLineKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signLineToken(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func lineClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":     LineIssuer,
+		"sub":     "U123",
+		"aud":     testChannelID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+		"iat":     time.Now().Unix(),
+		"name":    "Alice",
+		"picture": "https://example.com/alice.png",
+		"nonce":   "n-1",
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func newTestVerifier(keys LineKeySource, verifyURL string) *LineTokenVerifier {
+	return &LineTokenVerifier{
+		ChannelID: testChannelID,
+		Keys:      keys,
+		Client:    http.DefaultClient,
+		VerifyURL: verifyURL,
+	}
+}
+
+func TestLineTokenVerifierLocal(t *testing.T) {
+	key := newTestLineKey(t)
+	other := newTestLineKey(t)
+	keys := StaticLineKeys{"k1": &key.PublicKey}
+
+	// Any request to the remote endpoint fails the test: these tokens must be decided locally
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected remote verify call")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer remote.Close()
+	v := newTestVerifier(keys, remote.URL)
+	ctx := context.Background()
+
+	profile, err := v.Verify(ctx, signLineToken(t, key, "k1", lineClaims(nil)), "n-1")
+	if err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if profile.Sub != "U123" || profile.Name != "Alice" || profile.Aud != testChannelID {
+		t.Fatalf("profile = %+v", profile)
+	}
+
+	// An empty nonce skips the nonce check
+	if _, err := v.Verify(ctx, signLineToken(t, key, "k1", lineClaims(nil)), ""); err != nil {
+		t.Fatalf("no nonce: %v", err)
+	}
+
+	rejected := map[string]string{
+		"wrong audience":  signLineToken(t, key, "k1", lineClaims(jwt.MapClaims{"aud": "other"})),
+		"wrong issuer":    signLineToken(t, key, "k1", lineClaims(jwt.MapClaims{"iss": "https://evil.example"})),
+		"expired":         signLineToken(t, key, "k1", lineClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+		"missing exp":     signLineToken(t, key, "k1", lineClaims(jwt.MapClaims{"exp": nil})),
+		"wrong nonce":     signLineToken(t, key, "k1", lineClaims(jwt.MapClaims{"nonce": "n-2"})),
+		"wrong signature": signLineToken(t, other, "k1", lineClaims(nil)),
+	}
+	for name, token := range rejected {
+		if _, err := v.Verify(ctx, token, "n-1"); !errors.Is(err, ErrInvalidLineToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidLineToken", name, err)
+		}
+	}
+
+	// HS256 tokens must not be accepted even if the claims are right
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, lineClaims(nil))
+	hs.Header["kid"] = "k1"
+	hsToken, _ := hs.SignedString([]byte("secret"))
+	if _, err := v.Verify(ctx, hsToken, "n-1"); !errors.Is(err, ErrInvalidLineToken) {
+		t.Errorf("HS256: err = %v, want ErrInvalidLineToken", err)
+	}
+}
+
+func TestLineTokenVerifierFallsBackToRemote(t *testing.T) {
+	key := newTestLineKey(t)
+	token := signLineToken(t, key, "unknown", lineClaims(nil))
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.PostForm.Get("id_token") != token || r.PostForm.Get("client_id") != testChannelID || r.PostForm.Get("nonce") != "n-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(LineTokenResponse{Sub: "U123", Name: "Alice", Aud: testChannelID})
+	}))
+	defer remote.Close()
+
+	v := newTestVerifier(StaticLineKeys{}, remote.URL)
+	profile, err := v.Verify(context.Background(), token, "n-1")
+	if err != nil || profile.Sub != "U123" {
+		t.Fatalf("fallback = %+v, %v", profile, err)
+	}
+
+	if _, err := v.Verify(context.Background(), token, "n-2"); !errors.Is(err, ErrInvalidLineToken) {
+		t.Fatalf("remote rejection: err = %v, want ErrInvalidLineToken", err)
+	}
+}
+
+func TestLineTokenVerifierRequiresChannelID(t *testing.T) {
+	key := newTestLineKey(t)
+	token := signLineToken(t, key, "k1", lineClaims(nil))
+
+	// Neither the local check nor the remote endpoint may accept a token for an unknown audience
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected remote verify call")
+		json.NewEncoder(w).Encode(LineTokenResponse{Sub: "U123"})
+	}))
+	defer remote.Close()
+
+	for name, keys := range map[string]LineKeySource{
+		"local":  StaticLineKeys{"k1": &key.PublicKey},
+		"remote": StaticLineKeys{},
+	} {
+		v := newTestVerifier(keys, remote.URL)
+		v.ChannelID = ""
+		if _, err := v.Verify(context.Background(), token, "n-1"); !errors.Is(err, ErrInvalidLineToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidLineToken", name, err)
+		}
+	}
+}
+
+func TestJWKSKeySource(t *testing.T) {
+	key := newTestLineKey(t)
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "EC",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	source := NewJWKSKeySource(server.URL, http.DefaultClient, time.Hour)
+	ctx := context.Background()
+
+	got, err := source.PublicKey(ctx, "k1")
+	if err != nil || !got.Equal(&key.PublicKey) {
+		t.Fatalf("PublicKey(k1) = %v, %v", got, err)
+	}
+	if _, err := source.PublicKey(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("fetches = %d, want 1 (cached)", n)
+	}
+
+	// Unknown kids do not refetch more than once per MinRefresh
+	if _, err := source.PublicKey(ctx, "k2"); !errors.Is(err, ErrLineKeyUnavailable) {
+		t.Fatalf("unknown kid: err = %v, want ErrLineKeyUnavailable", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("fetches = %d, want 1 (rate limited)", n)
+	}
+
+	// The verifier accepts tokens signed with a key served by the JWKS
+	v := newTestVerifier(source, "http://127.0.0.1:0")
+	if _, err := v.Verify(ctx, signLineToken(t, key, "k1", lineClaims(nil)), "n-1"); err != nil {
+		t.Fatalf("verify with JWKS: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"event-manager/internal/models"
+)
+
+// Errors returned by co-organizer management
+var (
+	ErrOrganizerIDRequired = errors.New("userId is required")
+	ErrAlreadyOwner        = errors.New("user is already the event owner")
+	ErrOwnerOnly           = errors.New("only the event owner or an admin can transfer ownership")
+)
+
+// EventOrganizers lists who may manage an event besides admins
+type EventOrganizers struct {
+	Owner      string                   `json:"owner"`
+	Organizers []*models.EventOrganizer `json:"organizers"`
+}
+
+// ListOrganizers returns the event's owner and co-organizers. Only managers may list them.
+func (s *EventService) ListOrganizers(ctx context.Context, eventID string, viewer Viewer) (*EventOrganizers, error) {
+	event, err := authorizeManage(ctx, s.Repo, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	organizers, err := s.Repo.ListOrganizers(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventOrganizers{Owner: event.CreatedBy, Organizers: organizers}, nil
+}
+
+// AddOrganizer grants a LINE user the same rights on the event as its creator.
+// Any manager of the event may add organizers.
+func (s *EventService) AddOrganizer(ctx context.Context, eventID, userID string, viewer Viewer) error {
+	event, err := authorizeManage(ctx, s.Repo, eventID, viewer)
+	if err != nil {
+		return err
+	}
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return ErrOrganizerIDRequired
+	}
+	if userID == event.CreatedBy {
+		return ErrAlreadyOwner
+	}
+
+	if err := s.Repo.AddOrganizer(ctx, eventID, userID, viewer.UserID); err != nil {
+		return err
+	}
+
+	s.Audit.Record(ctx, viewer.UserID, eventID, "", AuditOrganizerAdd, nil, map[string]string{"userId": userID})
+	return nil
+}
+
+// RemoveOrganizer revokes a co-organizer. Any manager of the event may remove organizers,
+// including themselves; the owner can only change through TransferOwnership.
+func (s *EventService) RemoveOrganizer(ctx context.Context, eventID, userID string, viewer Viewer) error {
+	if _, err := authorizeManage(ctx, s.Repo, eventID, viewer); err != nil {
+		return err
+	}
+
+	userID = strings.TrimSpace(userID)
+	if err := s.Repo.RemoveOrganizer(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	s.Audit.Record(ctx, viewer.UserID, eventID, "", AuditOrganizerRemove, map[string]string{"userId": userID}, nil)
+	return nil
+}
+
+// TransferOwnership makes another LINE user the event's CreatedBy. Only the current owner
+// or an admin may transfer; the previous owner stays on as a co-organizer.
+func (s *EventService) TransferOwnership(ctx context.Context, eventID, newOwner string, viewer Viewer) error {
+	event, err := s.Repo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if !viewer.IsAdmin && (viewer.UserID == "" || viewer.UserID != event.CreatedBy) {
+		return ErrOwnerOnly
+	}
+
+	newOwner = strings.TrimSpace(newOwner)
+	if newOwner == "" {
+		return ErrOrganizerIDRequired
+	}
+
+	if err := s.Repo.TransferOwnership(ctx, eventID, newOwner, viewer.UserID); err != nil {
+		return err
+	}
+
+	s.Audit.Record(ctx, viewer.UserID, eventID, "", AuditOwnerTransfer,
+		map[string]string{"owner": event.CreatedBy}, map[string]string{"owner": newOwner})
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func newTestEventService(events *repository.MemoryEventRepository) *EventService {
+	return NewEventService(events, repository.NewMemoryInteractionRepository(events), NewMemoryCacheService(30*time.Second), NewStatusHub(), NewAuditService(repository.NewMemoryAuditRepository(), events))
+}
+
+func TestOrganizerManagement(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5})
+	event.CreatedBy = "owner"
+	svc := newTestEventService(newTestEventRepo(event))
+	ctx := context.Background()
+	owner := Viewer{UserID: "owner"}
+
+	if err := svc.AddOrganizer(ctx, "ev1", "co", Viewer{UserID: "stranger"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("stranger AddOrganizer = %v, want ErrForbidden", err)
+	}
+	if err := svc.AddOrganizer(ctx, "ev1", "owner", owner); !errors.Is(err, ErrAlreadyOwner) {
+		t.Fatalf("AddOrganizer(owner) = %v, want ErrAlreadyOwner", err)
+	}
+	if err := svc.AddOrganizer(ctx, "ev1", " ", owner); !errors.Is(err, ErrOrganizerIDRequired) {
+		t.Fatalf("AddOrganizer(blank) = %v, want ErrOrganizerIDRequired", err)
+	}
+	if err := svc.AddOrganizer(ctx, "ev1", "co", owner); err != nil {
+		t.Fatalf("AddOrganizer: %v", err)
+	}
+
+	// Co-organizers have the owner's management rights
+	if err := svc.AddOrganizer(ctx, "ev1", "co2", Viewer{UserID: "co"}); err != nil {
+		t.Fatalf("co AddOrganizer: %v", err)
+	}
+	list, err := svc.ListOrganizers(ctx, "ev1", Viewer{UserID: "co2"})
+	if err != nil {
+		t.Fatalf("ListOrganizers: %v", err)
+	}
+	if list.Owner != "owner" || len(list.Organizers) != 2 || list.Organizers[1].AddedBy != "co" {
+		t.Fatalf("ListOrganizers = %+v", list)
+	}
+
+	if err := svc.RemoveOrganizer(ctx, "ev1", "co2", Viewer{UserID: "co"}); err != nil {
+		t.Fatalf("RemoveOrganizer: %v", err)
+	}
+	if err := svc.RemoveOrganizer(ctx, "ev1", "co2", owner); !errors.Is(err, repository.ErrOrganizerNotFound) {
+		t.Fatalf("RemoveOrganizer(again) = %v, want ErrOrganizerNotFound", err)
+	}
+	if err := svc.AuthorizeManage(ctx, "ev1", Viewer{UserID: "co2"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("removed organizer still authorized: %v", err)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5})
+	event.CreatedBy = "owner"
+	events := newTestEventRepo(event)
+	addTestOrganizer(t, events, "ev1", "co")
+	svc := newTestEventService(events)
+	ctx := context.Background()
+
+	if err := svc.TransferOwnership(ctx, "ev1", "co", Viewer{UserID: "co"}); !errors.Is(err, ErrOwnerOnly) {
+		t.Fatalf("organizer TransferOwnership = %v, want ErrOwnerOnly", err)
+	}
+
+	if err := svc.TransferOwnership(ctx, "ev1", "co", Viewer{UserID: "owner"}); err != nil {
+		t.Fatalf("TransferOwnership: %v", err)
+	}
+
+	list, err := svc.ListOrganizers(ctx, "ev1", Viewer{UserID: "co"})
+	if err != nil {
+		t.Fatalf("ListOrganizers: %v", err)
+	}
+	if list.Owner != "co" || len(list.Organizers) != 1 || list.Organizers[0].UserID != "owner" {
+		t.Fatalf("after transfer = owner %s, organizers %+v", list.Owner, list.Organizers)
+	}
+
+	// Admins can transfer any event
+	if err := svc.TransferOwnership(ctx, "ev1", "new", Viewer{UserID: "root", IsAdmin: true}); err != nil {
+		t.Fatalf("admin TransferOwnership: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"event-manager/internal/models"
+)
+
+// redactsIdentities reports whether non-admin viewers must not see who other records belong to:
+// VOTE events with ShowVoters explicitly false and events in PrivacyMode
+func redactsIdentities(event *models.Event) bool {
+	if event.Config.PrivacyMode {
+		return true
+	}
+	return event.Type == models.EventTypeVote && event.Config.ShowVoters != nil && !*event.Config.ShowVoters
+}
+
+// redactIdentity strips another user's identity from a status record. The user ID is
+// replaced with a per-event pseudonym so clients can still tell records apart.
+// In PrivacyMode the display name is masked the same way the LINEUP page masks it.
+func redactIdentity(key []byte, event *models.Event, rec *models.Interaction, recMap map[string]interface{}) {
+	recMap["userId"] = pseudonym(key, event.EventID, rec.UserID)
+	recMap["userPictureUrl"] = ""
+	if event.Config.PrivacyMode {
+		recMap["userDisplayName"] = maskName(rec.UserDisplayName)
+	} else {
+		recMap["userDisplayName"] = ""
+	}
+}
+
+// DerivePseudonymKey derives the pseudonym key from the server secret (JWT_SECRET), so it
+// is stable across restarts and replicas without being the secret itself
+func DerivePseudonymKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("event-manager pseudonyms"))
+	return mac.Sum(nil)
+}
+
+// pseudonym is keyed so that knowing a user ID and the event ID is not enough to find
+// that user's records
+func pseudonym(key []byte, eventID, userID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(eventID + ":" + userID))
+	return "anon-" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+func maskName(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return ""
+	}
+	if len(runes) <= 2 {
+		return string(runes[0]) + "*"
+	}
+	if len(runes) > 4 {
+		runes = runes[:4]
+	}
+	return string(runes) + "..."
+}
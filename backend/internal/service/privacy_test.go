@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func recordsFor(t *testing.T, svc *InteractionService, eventID string, viewer Viewer) []map[string]interface{} {
+	t.Helper()
+	status, err := svc.GetEventStatus(context.Background(), eventID, viewer)
+	if err != nil {
+		t.Fatalf("GetEventStatus: %v", err)
+	}
+	return status["records"].([]map[string]interface{})
+}
+
+func TestGetEventStatusRedactsAnonymousVotes(t *testing.T) {
+	hidden := false
+	event := voteEvent("ev1", 1, "a", "b")
+	event.Config.ShowVoters = &hidden
+	svc, _ := newTestInteractionService(newTestEventRepo(event), newTestUserRepo(
+		profileUser("alice", "alice name", "https://example.com/alice"),
+		profileUser("bob", "bob name", "https://example.com/bob"),
+	))
+	ctx := context.Background()
+
+	for _, uid := range []string{"alice", "bob"} {
+		if _, err := svc.HandleAction(ctx, "ev1", &models.Interaction{
+			UserID:          uid,
+			Type:            models.InteractionTypeVote,
+			SelectedOptions: []string{"a"},
+		}); err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+
+	// Populate the cache with the admin view first; it must not leak to users
+	for _, rec := range recordsFor(t, svc, "ev1", Viewer{UserID: "root", IsAdmin: true}) {
+		if rec["userDisplayName"] == "" {
+			t.Errorf("admin view redacted %v", rec["id"])
+		}
+	}
+
+	for _, rec := range recordsFor(t, svc, "ev1", Viewer{UserID: "alice"}) {
+		switch rec["userId"] {
+		case "alice":
+			if rec["userDisplayName"] != "alice name" {
+				t.Errorf("own record redacted: %v", rec)
+			}
+		case "bob":
+			t.Errorf("bob's user ID leaked to alice")
+		default:
+			if rec["userDisplayName"] != "" || rec["userPictureUrl"] != "" {
+				t.Errorf("other record not redacted: %v", rec)
+			}
+		}
+	}
+}
+
+func TestGetEventStatusPrivacyModeMasksNames(t *testing.T) {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5, PrivacyMode: true})
+	svc, _ := newTestInteractionService(newTestEventRepo(event), newTestUserRepo(profileUser("bob", "Bobby Tables", "https://example.com/bob")))
+
+	_, err := svc.HandleAction(context.Background(), "ev1", &models.Interaction{
+		UserID: "bob",
+		Type:   models.InteractionTypeLineUp,
+		Count:  1,
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	rec := recordsFor(t, svc, "ev1", Viewer{UserID: "alice"})[0]
+	if rec["userDisplayName"] != "Bobb..." || rec["userPictureUrl"] != "" || rec["userId"] == "bob" {
+		t.Errorf("record not masked: %v", rec)
+	}
+
+	rec = recordsFor(t, svc, "ev1", Viewer{UserID: "bob"})[0]
+	if rec["userDisplayName"] != "Bobby Tables" || rec["userId"] != "bob" {
+		t.Errorf("own record masked: %v", rec)
+	}
+}
+
+func TestGetEventStatusPublicEventSharesView(t *testing.T) {
+	svc, _ := newTestInteractionService(newTestEventRepo(voteEvent("ev1", 1, "a")), newTestUserRepo())
+	recordsFor(t, svc, "ev1", Viewer{UserID: "alice"})
+
+	if _, found := svc.Cache.Get("ev1", "full"); !found {
+		t.Error("public event status not cached under the shared view")
+	}
+}
+
+func TestPseudonymDependsOnKey(t *testing.T) {
+	key := DerivePseudonymKey("first-secret-at-least-32-characters-long")
+	other := DerivePseudonymKey("other-secret-at-least-32-characters-long")
+
+	p := pseudonym(key, "ev1", "alice")
+	if p != pseudonym(key, "ev1", "alice") {
+		t.Error("pseudonym is not stable")
+	}
+	if p == pseudonym(other, "ev1", "alice") {
+		t.Error("pseudonym does not depend on the key")
+	}
+	if p == pseudonym(key, "ev2", "alice") || p == pseudonym(key, "ev1", "bob") {
+		t.Error("pseudonym does not depend on the event and user")
+	}
+}
+
+func TestMaskName(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"A":            "A*",
+		"小明":           "小*",
+		"Bobby Tables": "Bobb...",
+		"王小明同學":        "王小明同...",
+	}
+	for in, want := range tests {
+		if got := maskName(in); got != want {
+			t.Errorf("maskName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// maxCustomNameLength matches users.custom_name and event_nicknames.nickname
+const maxCustomNameLength = 100
+
+var ErrNameTooLong = errors.New("name is too long")
+
+// ProfileService manages how users are shown to others: a custom name replacing their LINE
+// display name, and optional nicknames for single events
+type ProfileService struct {
+	Users  repository.UserRepository
+	Events repository.EventRepository
+}
+
+func NewProfileService(users repository.UserRepository, events repository.EventRepository) *ProfileService {
+	return &ProfileService{Users: users, Events: events}
+}
+
+// Profile is the current user's profile as returned by GET /me
+type Profile struct {
+	*models.User
+	DisplayName    string            `json:"displayName"`
+	EventNicknames map[string]string `json:"eventNicknames"` // eventID -> nickname
+}
+
+// ProfileUpdate holds the fields of PATCH /me. A nil CustomName is left unchanged and an
+// empty one reverts to the LINE display name; an empty nickname removes it.
+type ProfileUpdate struct {
+	CustomName     *string
+	EventNicknames map[string]string
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, userID string) (*Profile, error) {
+	user, err := s.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	nicknames, err := s.Users.ListEventNicknames(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{
+		User:           user,
+		DisplayName:    user.DisplayName(),
+		EventNicknames: make(map[string]string, len(nicknames)),
+	}
+	for _, n := range nicknames {
+		profile.EventNicknames[n.EventID] = n.Nickname
+	}
+	return profile, nil
+}
+
+// UpdateProfile validates every change before writing any. Nicknames for unknown events
+// fail with sql.ErrNoRows.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, update ProfileUpdate) (*Profile, error) {
+	var customName string
+	if update.CustomName != nil {
+		customName = strings.TrimSpace(*update.CustomName)
+		if len([]rune(customName)) > maxCustomNameLength {
+			return nil, ErrNameTooLong
+		}
+	}
+
+	nicknames := make(map[string]string, len(update.EventNicknames))
+	for eventID, nickname := range update.EventNicknames {
+		nickname = strings.TrimSpace(nickname)
+		if len([]rune(nickname)) > maxCustomNameLength {
+			return nil, ErrNameTooLong
+		}
+		if _, err := s.Events.GetByID(ctx, eventID); err != nil {
+			return nil, err
+		}
+		nicknames[eventID] = nickname
+	}
+
+	if update.CustomName != nil {
+		if err := s.Users.UpdateFields(ctx, userID, map[string]interface{}{"customName": customName}); err != nil {
+			return nil, err
+		}
+		log.Printf("[Profile] %s set custom name %q", userID, customName)
+	}
+	for eventID, nickname := range nicknames {
+		if err := s.Users.SetEventNickname(ctx, eventID, userID, nickname); err != nil {
+			return nil, err
+		}
+		log.Printf("[Profile] %s set nickname %q for event %s", userID, nickname, eventID)
+	}
+
+	return s.GetProfile(ctx, userID)
+}
+
+// resolveIdentity returns the name and picture to store on the user's records in an event:
+// their nickname for the event, else their custom name, else their LINE display name
+func resolveIdentity(ctx context.Context, users repository.UserRepository, eventID, userID string) (string, string, error) {
+	user, err := users.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	nickname, err := users.GetEventNickname(ctx, eventID, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if nickname != "" {
+		return nickname, user.PictureURL, nil
+	}
+	return user.DisplayName(), user.PictureURL, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func TestUpdateProfile(t *testing.T) {
+	users := newTestUserRepo(profileUser("alice", "LINE Alice", "https://example.com/alice"))
+	events := newTestEventRepo(lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5}))
+	svc := NewProfileService(users, events)
+	ctx := context.Background()
+
+	profile, err := svc.GetProfile(ctx, "alice")
+	if err != nil || profile.DisplayName != "LINE Alice" || len(profile.EventNicknames) != 0 {
+		t.Fatalf("initial profile = %+v, %v", profile, err)
+	}
+
+	custom := "  Ali  "
+	profile, err = svc.UpdateProfile(ctx, "alice", ProfileUpdate{
+		CustomName:     &custom,
+		EventNicknames: map[string]string{"ev1": "Captain"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.CustomName != "Ali" || profile.DisplayName != "Ali" || profile.EventNicknames["ev1"] != "Captain" {
+		t.Fatalf("updated profile = %+v", profile)
+	}
+
+	// Omitted fields are unchanged; empty values clear
+	profile, err = svc.UpdateProfile(ctx, "alice", ProfileUpdate{EventNicknames: map[string]string{"ev1": ""}})
+	if err != nil || profile.CustomName != "Ali" || len(profile.EventNicknames) != 0 {
+		t.Fatalf("after clearing nickname = %+v, %v", profile, err)
+	}
+	empty := ""
+	if profile, err = svc.UpdateProfile(ctx, "alice", ProfileUpdate{CustomName: &empty}); err != nil || profile.DisplayName != "LINE Alice" {
+		t.Fatalf("after clearing custom name = %+v, %v", profile, err)
+	}
+
+	// Invalid updates change nothing
+	long := strings.Repeat("名", maxCustomNameLength+1)
+	if _, err := svc.UpdateProfile(ctx, "alice", ProfileUpdate{CustomName: &custom, EventNicknames: map[string]string{"ev1": long}}); !errors.Is(err, ErrNameTooLong) {
+		t.Fatalf("long nickname: err = %v, want ErrNameTooLong", err)
+	}
+	if _, err := svc.UpdateProfile(ctx, "alice", ProfileUpdate{CustomName: &custom, EventNicknames: map[string]string{"missing": "x"}}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown event: err = %v, want sql.ErrNoRows", err)
+	}
+	if profile, _ := svc.GetProfile(ctx, "alice"); profile.CustomName != "" {
+		t.Fatalf("rejected update was partly applied: %+v", profile)
+	}
+}
+
+func TestHandleActionUsesProfileName(t *testing.T) {
+	users := newTestUserRepo(profileUser("alice", "LINE Alice", "https://example.com/alice"))
+	events := newTestEventRepo(
+		lineUpEvent("ev1", models.EventConfig{MaxParticipants: 5}),
+		lineUpEvent("ev2", models.EventConfig{MaxParticipants: 5}),
+	)
+	svc, repo := newTestInteractionService(events, users)
+	profiles := NewProfileService(users, events)
+	ctx := context.Background()
+
+	custom := "Ali"
+	if _, err := profiles.UpdateProfile(ctx, "alice", ProfileUpdate{
+		CustomName:     &custom,
+		EventNicknames: map[string]string{"ev2": "Captain"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"ev1": "Ali", "ev2": "Captain"}
+	for eventID, name := range want {
+		// Whatever the client claims is ignored
+		result, err := svc.HandleAction(ctx, eventID, &models.Interaction{
+			UserID:          "alice",
+			UserDisplayName: "Mallory",
+			UserPictureUrl:  "https://evil.example/x.png",
+			Type:            models.InteractionTypeLineUp,
+			Count:           1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec, _ := repo.GetByID(ctx, eventID, result.RecordID)
+		if rec.UserDisplayName != name || rec.UserPictureUrl != "https://example.com/alice" {
+			t.Errorf("%s record = %q / %q, want %q", eventID, rec.UserDisplayName, rec.UserPictureUrl, name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// Service tests run against the in-memory repositories; these helpers seed them
+
+func newTestEventRepo(events ...*models.Event) *repository.MemoryEventRepository {
+	repo := repository.NewMemoryEventRepository(nil)
+	for _, e := range events {
+		if err := repo.Create(context.Background(), e); err != nil {
+			panic(err)
+		}
+		if e.IsArchived {
+			repo.UpdateArchived(context.Background(), e.EventID, true)
+		}
+		if e.StatusChangedAt != nil {
+			repo.Update(context.Background(), e)
+		}
+	}
+	return repo
+}
+
+func newTestUserRepo(users ...*models.User) *repository.MemoryUserRepository {
+	repo := repository.NewMemoryUserRepository()
+	for _, u := range users {
+		if err := repo.Create(context.Background(), u); err != nil {
+			panic(err)
+		}
+	}
+	return repo
+}
+
+func addTestOrganizer(t *testing.T, events *repository.MemoryEventRepository, eventID, userID string) {
+	t.Helper()
+	if err := events.AddOrganizer(context.Background(), eventID, userID, "test"); err != nil {
+		t.Fatalf("AddOrganizer: %v", err)
+	}
+}
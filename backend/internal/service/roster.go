@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// GuestUserPrefix marks registrations an organizer added for someone without a LINE account
+const GuestUserPrefix = "guest:"
+
+// maxGuestNameLength matches interactions.user_display_name
+const maxGuestNameLength = 100
+
+// Errors returned by roster management
+var (
+	ErrNotLineUpEvent    = errors.New("roster management is only available for LINEUP events")
+	ErrGuestNameRequired = errors.New("guest name is required")
+	ErrGuestNameTooLong  = errors.New("guest name is too long")
+	ErrNotWaitlisted     = errors.New("registration is not on the waitlist")
+	ErrNoSeatsConfigured = errors.New("event has no seats to promote into")
+)
+
+// authorizeRoster loads a LINEUP event the viewer may manage
+func (s *InteractionService) authorizeRoster(ctx context.Context, eventID string, viewer Viewer) (*models.Event, error) {
+	event, err := authorizeManage(ctx, s.Events, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type != models.EventTypeLineUp {
+		return nil, ErrNotLineUpEvent
+	}
+	return event, nil
+}
+
+// RegisterGuest adds a named registration for someone without a LINE account. Seats and
+// waitlist limits apply as for any registration; the event's time window does not.
+func (s *InteractionService) RegisterGuest(ctx context.Context, eventID, name, note string, viewer Viewer) (*ActionResult, error) {
+	_, err := s.authorizeRoster(ctx, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrGuestNameRequired
+	}
+	if len([]rune(name)) > maxGuestNameLength {
+		return nil, ErrGuestNameTooLong
+	}
+
+	guest := &models.Interaction{
+		UserID:          GuestUserPrefix + uuid.New().String()[:8],
+		UserDisplayName: name,
+		Type:            models.InteractionTypeLineUp,
+		Timestamp:       time.Now(),
+		Count:           1,
+		Note:            note,
+		AddedBy:         viewer.UserID,
+	}
+	// Guests are added by organizers, so the per-user limit does not apply
+	id, err := s.Repo.RegisterLineUp(ctx, eventID, guest, true)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Roster] %s registered guest %q as %s (%s) in event %s", viewer.UserID, name, id, guest.Status, eventID)
+	guest.ID = id
+	s.Audit.Record(ctx, viewer.UserID, eventID, id, AuditGuestRegister, nil, guest)
+	s.notifyChanged(eventID)
+
+	return &ActionResult{RecordID: id, Status: guest.Status}, nil
+}
+
+// CancelRegistration cancels any active registration on behalf of its owner; freed seats
+// go to the oldest waitlisted registrations
+func (s *InteractionService) CancelRegistration(ctx context.Context, eventID, recordID string, viewer Viewer) (*ActionResult, error) {
+	_, err := s.authorizeRoster(ctx, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, promoted, err := s.Repo.CancelLineUpRecord(ctx, eventID, recordID, viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Roster] %s cancelled %s (user %s) in event %s", viewer.UserID, cancelled.ID, cancelled.UserID, eventID)
+	s.auditCancellation(ctx, viewer.UserID, eventID, cancelled, promoted)
+	s.notifyChanged(eventID)
+
+	return &ActionResult{RecordID: cancelled.ID, Status: cancelled.Status, Promoted: promoted}, nil
+}
+
+// PromoteRegistration moves a waitlisted registration into the last SUCCESS seat. Capacity
+// is kept, so the registration that held that seat moves to the front of the waitlist.
+func (s *InteractionService) PromoteRegistration(ctx context.Context, eventID, recordID string, viewer Viewer) ([]repository.LineUpStatusChange, error) {
+	event, err := s.authorizeRoster(ctx, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if event.Config.MaxParticipants <= 0 {
+		return nil, ErrNoSeatsConfigured
+	}
+
+	active, err := s.activeRoster(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]string, 0, len(active))
+	found := false
+	success := 0
+	for _, rec := range active {
+		if rec.ID == recordID {
+			if rec.Status != "WAITLIST" {
+				return nil, ErrNotWaitlisted
+			}
+			found = true
+			continue
+		}
+		if rec.Status == "SUCCESS" {
+			success++
+		}
+		order = append(order, rec.ID)
+	}
+	if !found {
+		return nil, repository.ErrNoActiveRegistration
+	}
+
+	// Insert right after the SUCCESS block, within capacity
+	pos := success
+	if pos > event.Config.MaxParticipants-1 {
+		pos = event.Config.MaxParticipants - 1
+	}
+	order = append(order[:pos], append([]string{recordID}, order[pos:]...)...)
+
+	return s.reorder(ctx, event, rosterOrder(active), order, viewer)
+}
+
+// ReorderRoster puts the active registrations in the given order, which must list each of
+// them exactly once. The first MaxParticipants become SUCCESS and the rest WAITLIST.
+func (s *InteractionService) ReorderRoster(ctx context.Context, eventID string, recordIDs []string, viewer Viewer) ([]repository.LineUpStatusChange, error) {
+	event, err := s.authorizeRoster(ctx, eventID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.activeRoster(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reorder(ctx, event, rosterOrder(active), recordIDs, viewer)
+}
+
+// reorder applies a new queue order; previous is the order it replaces, for the audit log
+func (s *InteractionService) reorder(ctx context.Context, event *models.Event, previous, recordIDs []string, viewer Viewer) ([]repository.LineUpStatusChange, error) {
+	changes, err := s.Repo.ReorderLineUp(ctx, event.EventID, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Roster] %s reordered event %s", viewer.UserID, event.EventID)
+	for _, change := range changes {
+		log.Printf("[Roster] Record %s (user %s): %s -> %s", change.RecordID, change.UserID, change.From, change.To)
+	}
+	s.Audit.Record(ctx, viewer.UserID, event.EventID, "", AuditLineUpReorder,
+		map[string]interface{}{"order": previous},
+		map[string]interface{}{"order": recordIDs, "changes": changes})
+	s.notifyChanged(event.EventID)
+
+	return changes, nil
+}
+
+// activeRoster returns the event's active LINEUP registrations in queue order
+func (s *InteractionService) activeRoster(ctx context.Context, eventID string) ([]*models.Interaction, error) {
+	records, err := s.Repo.GetByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*models.Interaction, 0, len(records))
+	for _, rec := range records {
+		if rec.Type == models.InteractionTypeLineUp && rec.Status != "CANCELLED" {
+			active = append(active, rec)
+		}
+	}
+	repository.SortLineUpQueue(active)
+
+	return active, nil
+}
+
+func rosterOrder(active []*models.Interaction) []string {
+	ids := make([]string, 0, len(active))
+	for _, rec := range active {
+		ids = append(ids, rec.ID)
+	}
+	return ids
+}
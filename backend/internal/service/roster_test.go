@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func rosterEvent(maxParticipants, waitlistLimit int) *models.Event {
+	event := lineUpEvent("ev1", models.EventConfig{MaxParticipants: maxParticipants, WaitlistLimit: waitlistLimit})
+	event.CreatedBy = "owner"
+	return event
+}
+
+func recordIDByUser(t *testing.T, repo *repository.MemoryInteractionRepository, eventID, userID string) string {
+	t.Helper()
+	records, _ := repo.GetByEventID(context.Background(), eventID)
+	for _, rec := range records {
+		if rec.UserID == userID && rec.Status != "CANCELLED" {
+			return rec.ID
+		}
+	}
+	t.Fatalf("no active record for %s", userID)
+	return ""
+}
+
+func TestRegisterGuest(t *testing.T) {
+	svc, repo := newTestInteractionService(newTestEventRepo(rosterEvent(1, 1)), newTestUserRepo())
+	ctx := context.Background()
+	owner := Viewer{UserID: "owner"}
+
+	if _, err := svc.RegisterGuest(ctx, "ev1", "Guest", "", Viewer{UserID: "u0"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("non-organizer RegisterGuest = %v, want ErrForbidden", err)
+	}
+	if _, err := svc.RegisterGuest(ctx, "ev1", "  ", "", owner); !errors.Is(err, ErrGuestNameRequired) {
+		t.Fatalf("blank name = %v, want ErrGuestNameRequired", err)
+	}
+
+	first, err := svc.RegisterGuest(ctx, "ev1", "Grandma", "needs a chair", owner)
+	if err != nil || first.Status != "SUCCESS" {
+		t.Fatalf("RegisterGuest = %+v, %v", first, err)
+	}
+	second, err := svc.RegisterGuest(ctx, "ev1", "Grandpa", "", owner)
+	if err != nil || second.Status != "WAITLIST" {
+		t.Fatalf("second RegisterGuest = %+v, %v", second, err)
+	}
+	// Capacity rules still apply to organizers
+	if _, err := svc.RegisterGuest(ctx, "ev1", "Cousin", "", owner); !errors.Is(err, repository.ErrWaitlistFull) {
+		t.Fatalf("third RegisterGuest = %v, want ErrWaitlistFull", err)
+	}
+
+	rec, _ := repo.GetByID(ctx, "ev1", first.RecordID)
+	if !strings.HasPrefix(rec.UserID, GuestUserPrefix) || rec.UserDisplayName != "Grandma" || rec.AddedBy != "owner" {
+		t.Errorf("guest record = %+v", rec)
+	}
+}
+
+func TestCancelRegistrationPromotesWaitlist(t *testing.T) {
+	svc, repo := newTestInteractionService(newTestEventRepo(rosterEvent(1, 0)), newTestUserRepo())
+	ctx := context.Background()
+	seedLineUp(t, svc, "ev1", 2)
+
+	u0 := recordIDByUser(t, repo, "ev1", "u0")
+	if _, err := svc.CancelRegistration(ctx, "ev1", u0, Viewer{UserID: "u1"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("non-organizer CancelRegistration = %v, want ErrForbidden", err)
+	}
+
+	result, err := svc.CancelRegistration(ctx, "ev1", u0, Viewer{UserID: "owner"})
+	if err != nil {
+		t.Fatalf("CancelRegistration: %v", err)
+	}
+	if result.Status != "CANCELLED" || len(result.Promoted) != 1 || result.Promoted[0].UserID != "u1" {
+		t.Fatalf("result = %+v", result)
+	}
+	rec, _ := repo.GetByID(ctx, "ev1", u0)
+	if rec.CancelledBy != "owner" {
+		t.Errorf("CancelledBy = %q, want owner", rec.CancelledBy)
+	}
+
+	if _, err := svc.CancelRegistration(ctx, "ev1", u0, Viewer{UserID: "owner"}); !errors.Is(err, repository.ErrNoActiveRegistration) {
+		t.Errorf("cancel twice = %v, want ErrNoActiveRegistration", err)
+	}
+}
+
+func TestPromoteRegistrationKeepsCapacity(t *testing.T) {
+	svc, repo := newTestInteractionService(newTestEventRepo(rosterEvent(2, 0)), newTestUserRepo())
+	ctx := context.Background()
+	seedLineUp(t, svc, "ev1", 4)
+
+	if _, err := svc.PromoteRegistration(ctx, "ev1", recordIDByUser(t, repo, "ev1", "u0"), Viewer{UserID: "owner"}); !errors.Is(err, ErrNotWaitlisted) {
+		t.Fatalf("promote SUCCESS record = %v, want ErrNotWaitlisted", err)
+	}
+
+	changes, err := svc.PromoteRegistration(ctx, "ev1", recordIDByUser(t, repo, "ev1", "u3"), Viewer{UserID: "owner"})
+	if err != nil {
+		t.Fatalf("PromoteRegistration: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want u3 promoted and u1 moved to waitlist", changes)
+	}
+
+	want := map[string]string{"u0": "SUCCESS", "u3": "SUCCESS", "u1": "WAITLIST", "u2": "WAITLIST"}
+	got := statusByUser(t, repo, "ev1")
+	for uid, status := range want {
+		if got[uid] != status {
+			t.Errorf("%s = %s, want %s", uid, got[uid], status)
+		}
+	}
+
+	// u1 is now first in line for the next free seat
+	if _, err := svc.HandleAction(ctx, "ev1", &models.Interaction{UserID: "u0", Type: models.InteractionTypeLineUp, Count: -1}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if got := statusByUser(t, repo, "ev1"); got["u1"] != "SUCCESS" || got["u2"] != "WAITLIST" {
+		t.Errorf("after cancel = %v, want u1 promoted before u2", got)
+	}
+}
+
+func TestReorderRoster(t *testing.T) {
+	svc, repo := newTestInteractionService(newTestEventRepo(rosterEvent(1, 0)), newTestUserRepo())
+	ctx := context.Background()
+	seedLineUp(t, svc, "ev1", 3)
+
+	u0 := recordIDByUser(t, repo, "ev1", "u0")
+	u1 := recordIDByUser(t, repo, "ev1", "u1")
+	u2 := recordIDByUser(t, repo, "ev1", "u2")
+
+	if _, err := svc.ReorderRoster(ctx, "ev1", []string{u2, u0}, Viewer{UserID: "owner"}); !errors.Is(err, repository.ErrRosterMismatch) {
+		t.Fatalf("partial order = %v, want ErrRosterMismatch", err)
+	}
+	if _, err := svc.ReorderRoster(ctx, "ev1", []string{u2, u2, u0}, Viewer{UserID: "owner"}); !errors.Is(err, repository.ErrRosterMismatch) {
+		t.Fatalf("duplicate order = %v, want ErrRosterMismatch", err)
+	}
+
+	if _, err := svc.ReorderRoster(ctx, "ev1", []string{u2, u0, u1}, Viewer{UserID: "owner"}); err != nil {
+		t.Fatalf("ReorderRoster: %v", err)
+	}
+	if got := statusByUser(t, repo, "ev1"); got["u2"] != "SUCCESS" || got["u0"] != "WAITLIST" || got["u1"] != "WAITLIST" {
+		t.Errorf("statuses = %v", got)
+	}
+
+	active, err := svc.activeRoster(ctx, "ev1")
+	if err != nil {
+		t.Fatalf("activeRoster: %v", err)
+	}
+	for i, id := range []string{u2, u0, u1} {
+		if active[i].ID != id {
+			t.Fatalf("queue position %d = %s, want %s", i, active[i].ID, id)
+		}
+	}
+}
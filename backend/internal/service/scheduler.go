@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+// EventScheduler opens events at Config.StartTime and closes them at Config.EndTime.
+// Its first run catches up on transitions missed while the server was down, skipping events
+// whose IsActive was set after their latest transition. After that it only acts on
+// transitions as they happen, so an admin can still toggle IsActive by hand inside the window.
+type EventScheduler struct {
+	Repo       repository.EventRepository
+	Audit      *AuditService
+	interval   time.Duration
+	lastRun    time.Time
+	reconciled bool
+}
+
+func NewEventScheduler(repo repository.EventRepository, audit *AuditService, interval time.Duration) *EventScheduler {
+	return &EventScheduler{
+		Repo:     repo,
+		Audit:    audit,
+		interval: interval,
+		lastRun:  time.Now().Add(-interval),
+	}
+}
+
+// Run checks for start/end transitions every interval until ctx is cancelled
+func (s *EventScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil {
+			log.Printf("[Scheduler] ERROR: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies every StartTime/EndTime transition between the previous run and now.
+// The first call instead looks at every transition up to now (see missedTransition).
+func (s *EventScheduler) RunOnce(ctx context.Context, now time.Time) error {
+	from := s.lastRun
+	if !s.reconciled {
+		from = time.Time{}
+	}
+	events, err := s.Repo.ListScheduled(ctx, from, now)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		isActive := scheduledActive(event, s.lastRun, now)
+		if !s.reconciled {
+			var missed bool
+			if isActive, missed = missedTransition(event, now); !missed {
+				continue
+			}
+		}
+		if event.IsActive == isActive {
+			continue
+		}
+		if err := s.Repo.UpdateStatus(ctx, event.EventID, isActive); err != nil {
+			log.Printf("[Scheduler] Failed to set event %s active=%v: %v", event.EventID, isActive, err)
+			continue
+		}
+		log.Printf("[Scheduler] Event %s active=%v", event.EventID, isActive)
+		s.Audit.Record(ctx, ActorScheduler, event.EventID, "", AuditEventStatus,
+			map[string]bool{"isActive": event.IsActive}, map[string]bool{"isActive": isActive})
+	}
+
+	s.lastRun = now
+	s.reconciled = true
+	return nil
+}
+
+// missedTransition returns the IsActive value set by the event's latest StartTime or
+// EndTime at or before now. missed is false when neither has passed, or when IsActive was
+// set at or after that transition: by an admin, whose choice stands, or by a scheduler run
+// that already applied it.
+func missedTransition(event *models.Event, now time.Time) (isActive, missed bool) {
+	start, end := event.Config.StartTime, event.Config.EndTime
+	passed := func(t time.Time) bool {
+		return !t.IsZero() && !t.After(now)
+	}
+
+	var at time.Time
+	switch {
+	case passed(end) && (!passed(start) || !end.Before(start)):
+		at, isActive = end, false
+	case passed(start):
+		at, isActive = start, true
+	default:
+		return false, false
+	}
+
+	if event.StatusChangedAt != nil && !event.StatusChangedAt.Before(at) {
+		return false, false
+	}
+	return isActive, true
+}
+
+// scheduledActive returns the IsActive value implied by the transitions in (from, to].
+// When both passed in the same tick, the end wins.
+func scheduledActive(event *models.Event, from, to time.Time) bool {
+	passed := func(t time.Time) bool {
+		return !t.IsZero() && t.After(from) && !t.After(to)
+	}
+	if passed(event.Config.EndTime) {
+		return false
+	}
+	if passed(event.Config.StartTime) {
+		return true
+	}
+	return event.IsActive
+}
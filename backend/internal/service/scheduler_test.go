@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func TestEventSchedulerTransitions(t *testing.T) {
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	events := newTestEventRepo(&models.Event{
+		EventID:  "ev1",
+		Type:     models.EventTypeLineUp,
+		IsActive: false,
+		Config:   models.EventConfig{StartTime: start, EndTime: end},
+	})
+	scheduler := NewEventScheduler(events, NewAuditService(repository.NewMemoryAuditRepository(), events), time.Minute)
+	scheduler.lastRun = start.Add(-time.Minute)
+
+	isActive := func() bool {
+		e, _ := events.GetByID(context.Background(), "ev1")
+		return e.IsActive
+	}
+
+	if err := scheduler.RunOnce(context.Background(), start.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if isActive() {
+		t.Fatal("activated before StartTime")
+	}
+
+	scheduler.RunOnce(context.Background(), start)
+	if !isActive() {
+		t.Fatal("not activated at StartTime")
+	}
+
+	// Manual close inside the window is not overridden
+	events.UpdateStatus(context.Background(), "ev1", false)
+	scheduler.RunOnce(context.Background(), start.Add(time.Hour))
+	if isActive() {
+		t.Fatal("scheduler overrode manual status change")
+	}
+
+	events.UpdateStatus(context.Background(), "ev1", true)
+	scheduler.RunOnce(context.Background(), end.Add(time.Second))
+	if isActive() {
+		t.Fatal("not deactivated at EndTime")
+	}
+}
+
+func TestEventSchedulerCatchesUpOnStart(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := func(id string, isActive bool, start, end time.Time, changed time.Duration) *models.Event {
+		event := &models.Event{
+			EventID:  id,
+			Type:     models.EventTypeLineUp,
+			IsActive: isActive,
+			Config:   models.EventConfig{StartTime: start, EndTime: end},
+		}
+		if changed != 0 {
+			at := now.Add(changed)
+			event.StatusChangedAt = &at
+		}
+		return event
+	}
+	// Each transition happened while the server was down
+	events := newTestEventRepo(
+		scheduled("started", false, now.Add(-3*time.Hour), now.Add(time.Hour), -4*time.Hour),
+		scheduled("ended", true, now.Add(-3*time.Hour), now.Add(-time.Hour), 0),
+		scheduled("closed-by-hand", false, now.Add(-3*time.Hour), now.Add(time.Hour), -time.Hour),
+		scheduled("not-yet", true, now.Add(time.Hour), time.Time{}, 0),
+		scheduled("open-ended", false, time.Time{}, now.Add(time.Hour), 0),
+	)
+	audit := repository.NewMemoryAuditRepository()
+	scheduler := NewEventScheduler(events, NewAuditService(audit, events), time.Minute)
+
+	if err := scheduler.RunOnce(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	// Only missed transitions are applied; a manual change after the transition and
+	// events with no transition yet are left alone
+	want := map[string]bool{"started": true, "ended": false, "closed-by-hand": false, "not-yet": true, "open-ended": false}
+	changed := map[string]bool{"started": true, "ended": true}
+	for id, active := range want {
+		e, _ := events.GetByID(context.Background(), id)
+		if e.IsActive != active {
+			t.Errorf("%s: IsActive = %v, want %v", id, e.IsActive, active)
+		}
+		entries, _ := audit.ListByEvent(context.Background(), id, 10)
+		if changed[id] && (len(entries) != 1 || entries[0].Actor != ActorScheduler) {
+			t.Errorf("%s: audit = %v, want one scheduler entry", id, entries)
+		}
+		if !changed[id] && len(entries) != 0 {
+			t.Errorf("%s: audit = %v, want none", id, entries)
+		}
+	}
+
+	// A restart after the catch-up finds nothing left to apply
+	restarted := NewEventScheduler(events, NewAuditService(audit, events), time.Minute)
+	events.UpdateStatus(context.Background(), "ended", true)
+	restarted.RunOnce(context.Background(), now.Add(time.Minute))
+	if e, _ := events.GetByID(context.Background(), "ended"); !e.IsActive {
+		t.Error("restart reapplied a transition over a later manual reopen")
+	}
+
+	// Later runs only act on transitions, leaving manual changes alone
+	events.UpdateStatus(context.Background(), "started", false)
+	scheduler.RunOnce(context.Background(), now.Add(time.Minute))
+	if e, _ := events.GetByID(context.Background(), "started"); e.IsActive {
+		t.Error("second run overrode a manual status change")
+	}
+}
+
+func TestHandleActionOutsideWindow(t *testing.T) {
+	now := time.Now()
+	notStarted := lineUpEvent("early", models.EventConfig{MaxParticipants: 5, StartTime: now.Add(time.Hour)})
+	ended := &models.Event{
+		EventID:  "late",
+		Type:     models.EventTypeMemo,
+		IsActive: true,
+		Config:   models.EventConfig{MaxCommentsPerUser: 5, EndTime: now.Add(-time.Hour)},
+	}
+	open := &models.Event{
+		EventID:  "open",
+		Type:     models.EventTypeVote,
+		IsActive: true,
+		Config: models.EventConfig{
+			Options:   []string{"a"},
+			StartTime: now.Add(-time.Hour),
+			EndTime:   now.Add(time.Hour),
+		},
+	}
+	svc, _ := newTestInteractionService(newTestEventRepo(notStarted, ended, open), newTestUserRepo())
+
+	_, err := svc.HandleAction(context.Background(), "early", &models.Interaction{UserID: "u1", Type: models.InteractionTypeLineUp, Count: 1})
+	if err != ErrEventNotStarted {
+		t.Errorf("LINEUP before start: err = %v, want ErrEventNotStarted", err)
+	}
+
+	_, err = svc.HandleAction(context.Background(), "late", &models.Interaction{UserID: "u1", Type: models.InteractionTypeMemo, Content: "hi"})
+	if err != ErrEventEnded {
+		t.Errorf("MEMO after end: err = %v, want ErrEventEnded", err)
+	}
+
+	_, err = svc.HandleAction(context.Background(), "open", &models.Interaction{UserID: "u1", Type: models.InteractionTypeVote, SelectedOptions: []string{"a"}})
+	if err != nil {
+		t.Errorf("VOTE inside window: %v", err)
+	}
+}
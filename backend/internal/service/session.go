@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// TokenPair is returned by Login and Refresh. The refresh token is single use: each
+// refresh returns a new one.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"` // Access token lifetime in seconds
+}
+
+// hashRefreshToken returns the hex SHA-256 stored in place of the refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// startSession creates a session for the user and returns its first token pair
+func (s *AuthService) startSession(ctx context.Context, user *models.User) (*TokenPair, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		ID:               uuid.New().String(),
+		UserID:           user.LineUserID,
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+	}
+	if err := s.Sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(user, session.ID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Presenting the token that the
+// last refresh replaced revokes the whole session, since it may have been stolen.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, *models.User, error) {
+	if refreshToken == "" {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	hash := hashRefreshToken(refreshToken)
+
+	session, err := s.Sessions.GetByTokenHash(ctx, hash)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session.RefreshTokenHash != hash {
+		log.Printf("[Auth] Rotated refresh token reused for session %s (user %s); revoking session", session.ID, session.UserID)
+		if err := s.Sessions.Revoke(ctx, session.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if !session.Active(time.Now()) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// Reload the user so the new access token carries the current role
+	user, err := s.Repo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.Sessions.Rotate(ctx, session.ID, hash, hashRefreshToken(next), time.Now().Add(RefreshTokenTTL))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		// Revoked or refreshed concurrently with the same token
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.tokenPair(user, session.ID, next)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// Logout revokes the session the refresh token belongs to. Unknown tokens are ignored.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.Sessions.GetByTokenHash(ctx, hashRefreshToken(refreshToken))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.Sessions.Revoke(ctx, session.ID)
+}
+
+// SessionActive reports whether an access token's session may still be used
+func (s *AuthService) SessionActive(ctx context.Context, sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	return s.Sessions.IsActive(ctx, sessionID)
+}
+
+func (s *AuthService) tokenPair(user *models.User, sessionID, refreshToken string) (*TokenPair, error) {
+	accessToken, err := s.Keys.Sign(jwt.MapClaims{
+		"uid":  user.LineUserID,
+		"role": user.Role,
+		"sid":  sessionID,
+		"exp":  time.Now().Add(AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(AccessTokenTTL / time.Second),
+	}, nil
+}
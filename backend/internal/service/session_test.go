@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+)
+
+func newTestSessionAuth(t *testing.T, users ...*models.User) *AuthService {
+	return NewAuthService(newTestUserRepo(users...), repository.NewMemorySessionRepository(), newTestKeyManager(t, AlgEdDSA), NewDevIdentityProvider())
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	svc := newTestSessionAuth(t)
+	ctx := context.Background()
+
+	first, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, _ := parseTestJWT(t, svc, first.AccessToken)["sid"].(string)
+	if active, _ := svc.SessionActive(ctx, sid); !active {
+		t.Fatalf("session %q not active after login", sid)
+	}
+
+	second, user, err := svc.Refresh(ctx, first.RefreshToken)
+	if err != nil || user.LineUserID != "dev:alice" {
+		t.Fatalf("Refresh = %+v, %v", user, err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	if got := parseTestJWT(t, svc, second.AccessToken)["sid"]; got != sid {
+		t.Fatalf("refreshed sid = %v, want %s", got, sid)
+	}
+	if second.ExpiresIn != int(AccessTokenTTL/time.Second) {
+		t.Errorf("ExpiresIn = %d", second.ExpiresIn)
+	}
+
+	// The new token keeps working
+	third, _, err := svc.Refresh(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Older tokens are simply unknown
+	if _, _, err := svc.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("old token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if active, _ := svc.SessionActive(ctx, sid); !active {
+		t.Fatal("unknown token revoked the session")
+	}
+
+	// Replaying the token just rotated away revokes the session, including the latest tokens
+	if _, _, err := svc.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reused token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if active, _ := svc.SessionActive(ctx, sid); active {
+		t.Fatal("session still active after refresh token reuse")
+	}
+	if _, _, err := svc.Refresh(ctx, third.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("token of revoked session: err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	if _, _, err := svc.Refresh(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("unknown token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	svc := newTestSessionAuth(t)
+	ctx := context.Background()
+
+	tokens, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, _ := parseTestJWT(t, svc, tokens.AccessToken)["sid"].(string)
+
+	if err := svc.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := svc.SessionActive(ctx, sid); active {
+		t.Fatal("session still active after logout")
+	}
+	if _, _, err := svc.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh after logout: err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// Logging out twice, or with an unknown token, is harmless
+	if err := svc.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Logout(ctx, "unknown"); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := svc.SessionActive(ctx, ""); active {
+		t.Fatal("tokens without a session must not be accepted")
+	}
+}
+
+func TestRevokeAdminEndsSessions(t *testing.T) {
+	now := time.Now()
+	svc := newTestSessionAuth(t,
+		&models.User{LineUserID: "dev:a", Role: RoleAdmin, CreatedAt: now},
+		&models.User{LineUserID: "dev:b", Role: RoleAdmin, CreatedAt: now.Add(time.Second)},
+	)
+	ctx := context.Background()
+
+	tokens, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, _ := parseTestJWT(t, svc, tokens.AccessToken)["sid"].(string)
+
+	if err := svc.RevokeAdmin(ctx, "dev:b", "dev:a"); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := svc.SessionActive(ctx, sid); active {
+		t.Fatal("demoted admin's session still active")
+	}
+}
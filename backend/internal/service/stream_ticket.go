@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// StreamTicketTTL is how long a stream ticket may wait before it is redeemed
+const StreamTicketTTL = 30 * time.Second
+
+// StreamTicketAudience marks a signed token as a stream ticket; access tokens carry no
+// audience, so AuthMiddleware can tell the two apart
+const StreamTicketAudience = "event-stream"
+
+var ErrInvalidStreamTicket = errors.New("invalid or expired stream ticket")
+
+// StreamTicket lets EventSource, which cannot send headers, open one event's status
+// stream. It goes in the query string, so it is short lived, bound to the event and
+// redeemable once.
+type StreamTicket struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expiresIn"` // Seconds
+}
+
+// redeemedTickets remembers redeemed ticket IDs until the tickets expire. The zero value
+// is ready to use.
+type redeemedTickets struct {
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+// redeem records the ticket ID and reports whether it was not redeemed before
+func (r *redeemedTickets) redeem(id string, expiresAt, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids == nil {
+		r.ids = make(map[string]time.Time)
+	}
+	for other, exp := range r.ids {
+		if !now.Before(exp) {
+			delete(r.ids, other)
+		}
+	}
+	if _, used := r.ids[id]; used {
+		return false
+	}
+	r.ids[id] = expiresAt
+	return true
+}
+
+// IssueStreamTicket signs a ticket for the user's session to open the event's stream
+func (s *AuthService) IssueStreamTicket(userID, sessionID, eventID string) (*StreamTicket, error) {
+	ticket, err := s.Keys.Sign(jwt.MapClaims{
+		"aud": StreamTicketAudience,
+		"jti": uuid.New().String(),
+		"uid": userID,
+		"sid": sessionID,
+		"eid": eventID,
+		"exp": time.Now().Add(StreamTicketTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &StreamTicket{Ticket: ticket, ExpiresIn: int(StreamTicketTTL / time.Second)}, nil
+}
+
+// RedeemStreamTicket checks a ticket for the event and returns its user and session IDs.
+// Redemption is tracked per process, so with several replicas a ticket could be replayed
+// on another one until it expires.
+func (s *AuthService) RedeemStreamTicket(ctx context.Context, ticket, eventID string) (string, string, error) {
+	claims, err := s.Keys.Parse(ticket)
+	if err != nil {
+		return "", "", ErrInvalidStreamTicket
+	}
+	aud, _ := claims.GetAudience()
+	id, _ := claims["jti"].(string)
+	userID, _ := claims["uid"].(string)
+	sessionID, _ := claims["sid"].(string)
+	ticketEvent, _ := claims["eid"].(string)
+	exp, _ := claims.GetExpirationTime()
+	if len(aud) != 1 || aud[0] != StreamTicketAudience || id == "" || userID == "" || ticketEvent != eventID || exp == nil {
+		return "", "", ErrInvalidStreamTicket
+	}
+
+	active, err := s.SessionActive(ctx, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+	if !active {
+		return "", "", ErrInvalidStreamTicket
+	}
+	if !s.streamTickets.redeem(id, exp.Time, time.Now()) {
+		return "", "", ErrInvalidStreamTicket
+	}
+	return userID, sessionID, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStreamTicketIsSingleUseAndBoundToEvent(t *testing.T) {
+	svc := newTestSessionAuth(t)
+	ctx := context.Background()
+
+	pair, _, err := svc.Login(ctx, ProviderDev, LoginCredentials{UserID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, _ := parseTestJWT(t, svc, pair.AccessToken)["sid"].(string)
+
+	ticket, err := svc.IssueStreamTicket("dev:alice", sid, "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.ExpiresIn != int(StreamTicketTTL/time.Second) {
+		t.Errorf("ExpiresIn = %d", ticket.ExpiresIn)
+	}
+
+	if _, _, err := svc.RedeemStreamTicket(ctx, ticket.Ticket, "e2"); !errors.Is(err, ErrInvalidStreamTicket) {
+		t.Fatalf("other event: err = %v, want ErrInvalidStreamTicket", err)
+	}
+	userID, gotSID, err := svc.RedeemStreamTicket(ctx, ticket.Ticket, "e1")
+	if err != nil || userID != "dev:alice" || gotSID != sid {
+		t.Fatalf("Redeem = %q, %q, %v", userID, gotSID, err)
+	}
+	if _, _, err := svc.RedeemStreamTicket(ctx, ticket.Ticket, "e1"); !errors.Is(err, ErrInvalidStreamTicket) {
+		t.Fatalf("second redeem: err = %v, want ErrInvalidStreamTicket", err)
+	}
+
+	// An access token is not a ticket, and a ticket dies with its session
+	if _, _, err := svc.RedeemStreamTicket(ctx, pair.AccessToken, "e1"); !errors.Is(err, ErrInvalidStreamTicket) {
+		t.Fatalf("access token: err = %v, want ErrInvalidStreamTicket", err)
+	}
+	ticket, _ = svc.IssueStreamTicket("dev:alice", sid, "e1")
+	if err := svc.Logout(ctx, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.RedeemStreamTicket(ctx, ticket.Ticket, "e1"); !errors.Is(err, ErrInvalidStreamTicket) {
+		t.Fatalf("revoked session: err = %v, want ErrInvalidStreamTicket", err)
+	}
+}
+
+func TestRedeemedTicketsForgetExpiredIDs(t *testing.T) {
+	var r redeemedTickets
+	now := time.Now()
+	if !r.redeem("a", now.Add(time.Second), now) || r.redeem("a", now.Add(time.Second), now) {
+		t.Fatal("ticket a should redeem exactly once")
+	}
+	r.redeem("b", now.Add(time.Minute), now.Add(2*time.Second))
+	if len(r.ids) != 1 {
+		t.Fatalf("ids = %v, want only b", r.ids)
+	}
+}
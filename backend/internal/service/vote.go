@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"event-manager/internal/models"
+	"event-manager/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// voteOptionsOf returns the event's options with IDs. Events created before option IDs
+// existed use each label as its ID, which matches the labels their votes were stored with.
+func voteOptionsOf(config models.EventConfig) []models.VoteOption {
+	if len(config.VoteOptions) > 0 {
+		return config.VoteOptions
+	}
+	options := make([]models.VoteOption, 0, len(config.Options))
+	for _, label := range config.Options {
+		options = append(options, models.VoteOption{ID: label, Label: label})
+	}
+	return options
+}
+
+// syncVoteOptions rebuilds config.VoteOptions and config.Options from what the client sent.
+// An option keeps its ID from known only when the client names it: by sending voteOptions
+// with that ID (a rename) or by repeating its label unchanged. Every other label gets a new
+// ID, so votes for a removed option are never counted for a different one.
+func syncVoteOptions(config *models.EventConfig, known []models.VoteOption) {
+	knownIDs := make(map[string]bool, len(known))
+	for _, opt := range known {
+		knownIDs[opt.ID] = true
+	}
+	used := make(map[string]bool)
+	options := make([]models.VoteOption, 0)
+
+	if voteOptionsSent(config) {
+		for _, opt := range config.VoteOptions {
+			if !knownIDs[opt.ID] || used[opt.ID] {
+				opt.ID = uuid.New().String()[:8]
+			}
+			used[opt.ID] = true
+			options = append(options, opt)
+		}
+	} else {
+		// Labels only (the admin UI's textarea): match unchanged labels
+		for _, label := range config.Options {
+			opt := models.VoteOption{Label: label}
+			for _, k := range known {
+				if k.Label == label && !used[k.ID] {
+					opt.ID = k.ID
+					break
+				}
+			}
+			if opt.ID == "" {
+				opt.ID = uuid.New().String()[:8]
+			}
+			used[opt.ID] = true
+			options = append(options, opt)
+		}
+	}
+
+	labels := make([]string, len(options))
+	for i, opt := range options {
+		labels[i] = opt.Label
+	}
+	config.Options = labels
+	config.VoteOptions = options
+}
+
+// voteOptionsSent reports whether config.VoteOptions is what the client meant. Clients that
+// edit only the labels may echo the event's previous voteOptions; then Options wins.
+func voteOptionsSent(config *models.EventConfig) bool {
+	if len(config.VoteOptions) == 0 {
+		return false
+	}
+	if len(config.Options) == 0 {
+		return true
+	}
+	if len(config.Options) != len(config.VoteOptions) {
+		return false
+	}
+	for i, opt := range config.VoteOptions {
+		if config.Options[i] != opt.Label {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveSelection maps a client-sent value (label or option ID) to an option ID
+func resolveSelection(options []models.VoteOption, value string) (string, bool) {
+	for _, opt := range options {
+		if opt.Label == value {
+			return opt.ID, true
+		}
+	}
+	for _, opt := range options {
+		if opt.ID == value {
+			return opt.ID, true
+		}
+	}
+	return "", false
+}
+
+// optionLabels maps stored option IDs to current labels. Unknown values are returned as-is.
+func optionLabels(options []models.VoteOption, ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	labels := make([]string, 0, len(ids))
+	for _, id := range ids {
+		label := id
+		for _, opt := range options {
+			if opt.ID == id {
+				label = opt.Label
+				break
+			}
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// validateVote checks a VOTE payload against the event config and replaces
+// action.SelectedOptions with the resolved option IDs
+func validateVote(event *models.Event, action *models.Interaction) error {
+	if event.Type != models.EventTypeVote {
+		return &ActionError{
+			Code:    "EVENT_TYPE_MISMATCH",
+			Message: fmt.Sprintf("event type is %s, not VOTE", event.Type),
+		}
+	}
+
+	if !event.IsActive {
+		return ErrEventNotActive
+	}
+
+	if len(action.SelectedOptions) == 0 {
+		return &ActionError{Code: "NO_OPTION_SELECTED", Message: "at least one option must be selected"}
+	}
+
+	maxVotes := event.Config.MaxVotes
+	if maxVotes < 1 {
+		maxVotes = 1
+	}
+	if len(action.SelectedOptions) > maxVotes {
+		return &ActionError{
+			Code:    "TOO_MANY_OPTIONS",
+			Message: fmt.Sprintf("at most %d option(s) can be selected", maxVotes),
+			Details: map[string]interface{}{"maxVotes": maxVotes, "selected": len(action.SelectedOptions)},
+		}
+	}
+
+	options := voteOptionsOf(event.Config)
+	seen := make(map[string]bool)
+	ids := make([]string, 0, len(action.SelectedOptions))
+	for _, value := range action.SelectedOptions {
+		id, ok := resolveSelection(options, value)
+		if !ok {
+			return &ActionError{
+				Code:    "INVALID_OPTION",
+				Message: fmt.Sprintf("option %q does not exist", value),
+				Details: map[string]interface{}{"option": value},
+			}
+		}
+		if seen[id] {
+			return &ActionError{
+				Code:    "DUPLICATE_OPTION",
+				Message: fmt.Sprintf("option %q selected more than once", value),
+				Details: map[string]interface{}{"option": value},
+			}
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	action.SelectedOptions = ids
+	return nil
+}
+
+// VoteResults is the aggregated outcome of a VOTE event
+type VoteResults struct {
+	EventID     string         `json:"eventId"`
+	TotalVoters int            `json:"totalVoters"`
+	TotalVotes  int            `json:"totalVotes"`
+	Options     []OptionResult `json:"options"`
+}
+
+// OptionResult is the tally for one option. Percentage is relative to TotalVotes,
+// matching how the vote page renders its bars.
+type OptionResult struct {
+	ID         string             `json:"id"`
+	Label      string             `json:"label"`
+	Count      int                `json:"count"`
+	Percentage float64            `json:"percentage"`
+	Voters     []repository.Voter `json:"voters,omitempty"`
+}
+
+// ErrNotVoteEvent is returned when results are requested for a non-VOTE event
+var ErrNotVoteEvent = errors.New("event is not a VOTE event")
+
+// GetVoteResults returns per-option counts in option order. Voter lists are included
+// only when the event has ShowVoters enabled.
+func (s *InteractionService) GetVoteResults(ctx context.Context, eventID string) (*VoteResults, error) {
+	event, err := s.Events.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Type != models.EventTypeVote {
+		return nil, ErrNotVoteEvent
+	}
+
+	showVoters := event.Config.ShowVoters != nil && *event.Config.ShowVoters
+	summary, err := s.Repo.TallyVotes(ctx, eventID, showVoters)
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := make(map[string]repository.VoteTally, len(summary.Tallies))
+	for _, tally := range summary.Tallies {
+		tallies[tally.OptionID] = tally
+	}
+
+	results := &VoteResults{
+		EventID:     eventID,
+		TotalVoters: summary.TotalVoters,
+		Options:     make([]OptionResult, 0, len(event.Config.Options)),
+	}
+	for _, opt := range voteOptionsOf(event.Config) {
+		tally := tallies[opt.ID]
+		results.TotalVotes += tally.Count
+		result := OptionResult{ID: opt.ID, Label: opt.Label, Count: tally.Count}
+		if showVoters {
+			result.Voters = tally.Voters
+			if result.Voters == nil {
+				result.Voters = []repository.Voter{}
+			}
+		}
+		results.Options = append(results.Options, result)
+	}
+
+	if results.TotalVotes > 0 {
+		for i := range results.Options {
+			pct := float64(results.Options[i].Count) * 100 / float64(results.TotalVotes)
+			results.Options[i].Percentage = math.Round(pct*10) / 10
+		}
+	}
+
+	return results, nil
+}
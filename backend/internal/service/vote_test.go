@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-manager/internal/models"
+)
+
+func voteEvent(id string, maxVotes int, options ...string) *models.Event {
+	event := &models.Event{
+		EventID:  id,
+		Type:     models.EventTypeVote,
+		IsActive: true,
+		Config:   models.EventConfig{MaxVotes: maxVotes, Options: options},
+	}
+	syncVoteOptions(&event.Config, nil)
+	return event
+}
+
+func TestHandleVoteValidation(t *testing.T) {
+	inactive := voteEvent("closed", 1, "a", "b")
+	inactive.IsActive = false
+	events := newTestEventRepo(voteEvent("ev1", 2, "a", "b", "c"), inactive)
+	svc, _ := newTestInteractionService(events, newTestUserRepo())
+
+	tests := []struct {
+		name     string
+		eventID  string
+		selected []string
+		wantCode string
+	}{
+		{"valid labels", "ev1", []string{"a", "c"}, ""},
+		{"empty", "ev1", nil, "NO_OPTION_SELECTED"},
+		{"unknown option", "ev1", []string{"a", "z"}, "INVALID_OPTION"},
+		{"duplicate", "ev1", []string{"b", "b"}, "DUPLICATE_OPTION"},
+		{"too many", "ev1", []string{"a", "b", "c"}, "TOO_MANY_OPTIONS"},
+		{"inactive", "closed", []string{"a"}, "EVENT_NOT_ACTIVE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.HandleAction(context.Background(), tt.eventID, &models.Interaction{
+				UserID:          "u1",
+				Type:            models.InteractionTypeVote,
+				SelectedOptions: tt.selected,
+			})
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var actionErr *ActionError
+			if !errors.As(err, &actionErr) || actionErr.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVoteSurvivesOptionRename(t *testing.T) {
+	events := newTestEventRepo()
+	interactionSvc, repo := newTestInteractionService(events, newTestUserRepo())
+	eventSvc := NewEventService(events, repo, interactionSvc.Cache, interactionSvc.Hub, interactionSvc.Audit)
+	ctx := context.Background()
+
+	created, err := eventSvc.CreateEvent(ctx, &models.Event{
+		Type:   models.EventTypeVote,
+		Config: models.EventConfig{MaxVotes: 1, Options: []string{"Pizza", "Sushi"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	sushiID := created.Config.VoteOptions[1].ID
+
+	if _, err := interactionSvc.HandleAction(ctx, created.EventID, &models.Interaction{
+		UserID:          "u1",
+		Type:            models.InteractionTypeVote,
+		SelectedOptions: []string{"Sushi"},
+	}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+
+	// The client renames Sushi by its ID and adds Ramen
+	edited := *created
+	edited.Config.Options = nil
+	edited.Config.VoteOptions = []models.VoteOption{
+		created.Config.VoteOptions[0],
+		{ID: sushiID, Label: "Sushi Bar"},
+		{Label: "Ramen"},
+	}
+	updated, err := eventSvc.UpdateEvent(ctx, &edited, "admin")
+	if err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if updated.Config.VoteOptions[1].ID != sushiID {
+		t.Errorf("renamed option ID = %s, want %s", updated.Config.VoteOptions[1].ID, sushiID)
+	}
+
+	status, err := interactionSvc.GetEventStatus(ctx, created.EventID, Viewer{UserID: "u1"})
+	if err != nil {
+		t.Fatalf("GetEventStatus: %v", err)
+	}
+	records := status["records"].([]map[string]interface{})
+	labels := records[0]["selectedOptions"].([]string)
+	if len(labels) != 1 || labels[0] != "Sushi Bar" {
+		t.Errorf("selectedOptions = %v, want [Sushi Bar]", labels)
+	}
+}
+
+func TestSyncVoteOptionsLegacyEvent(t *testing.T) {
+	// Events saved before option IDs existed: labels act as IDs
+	config := models.EventConfig{Options: []string{"A", "B"}}
+	legacy := voteOptionsOf(config)
+
+	config.Options = nil
+	config.VoteOptions = []models.VoteOption{{ID: "B", Label: "B"}, {ID: "A", Label: "A renamed"}}
+	syncVoteOptions(&config, legacy)
+
+	if config.VoteOptions[0].ID != "B" {
+		t.Errorf("B kept ID %q, want B", config.VoteOptions[0].ID)
+	}
+	if config.VoteOptions[1].ID != "A" {
+		t.Errorf("renamed A got ID %q, want A", config.VoteOptions[1].ID)
+	}
+	if config.Options[1] != "A renamed" {
+		t.Errorf("Options = %v, want the new labels", config.Options)
+	}
+}
+
+func TestSyncVoteOptionsNeverReusesRemovedIDs(t *testing.T) {
+	config := models.EventConfig{Options: []string{"A", "B", "C"}}
+	syncVoteOptions(&config, nil)
+	known := config.VoteOptions
+	ids := map[string]string{}
+	for _, opt := range known {
+		ids[opt.Label] = opt.ID
+	}
+
+	// Delete B and add D by labels only, echoing the stale voteOptions as the admin UI does
+	config.Options = []string{"A", "C", "D"}
+	syncVoteOptions(&config, known)
+
+	if config.VoteOptions[0].ID != ids["A"] || config.VoteOptions[1].ID != ids["C"] {
+		t.Errorf("unchanged labels lost their IDs: %+v", config.VoteOptions)
+	}
+	for _, old := range ids {
+		if config.VoteOptions[2].ID == old {
+			t.Fatalf("new option D took the old ID %s", old)
+		}
+	}
+
+	// The same through voteOptions: an unknown or missing ID is never honoured
+	config.Options = nil
+	config.VoteOptions = []models.VoteOption{
+		{ID: ids["A"], Label: "A"}, {ID: ids["C"], Label: "C"}, {ID: ids["B"], Label: "D"}, {Label: "E"},
+	}
+	syncVoteOptions(&config, []models.VoteOption{known[0], known[2]})
+	if config.VoteOptions[2].ID == ids["B"] {
+		t.Error("D took the removed option's ID")
+	}
+	if config.VoteOptions[3].ID == "" {
+		t.Error("E got no ID")
+	}
+}
+
+func TestGetVoteResults(t *testing.T) {
+	showVoters := true
+	public := voteEvent("public", 2, "a", "b", "c")
+	public.Config.ShowVoters = &showVoters
+	anonymous := voteEvent("anon", 2, "a", "b", "c")
+	events := newTestEventRepo(public, anonymous)
+	svc, _ := newTestInteractionService(events, newTestUserRepo(
+		profileUser("u1", "name-u1", ""), profileUser("u2", "name-u2", ""), profileUser("u3", "name-u3", "")))
+	ctx := context.Background()
+
+	for _, eventID := range []string{"public", "anon"} {
+		for uid, selected := range map[string][]string{"u1": {"a", "b"}, "u2": {"a"}, "u3": {"b"}} {
+			if _, err := svc.HandleAction(ctx, eventID, &models.Interaction{
+				UserID:          uid,
+				Type:            models.InteractionTypeVote,
+				SelectedOptions: selected,
+			}); err != nil {
+				t.Fatalf("vote %s: %v", uid, err)
+			}
+		}
+	}
+
+	results, err := svc.GetVoteResults(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetVoteResults: %v", err)
+	}
+	if results.TotalVoters != 3 || results.TotalVotes != 4 {
+		t.Errorf("totals = %d voters / %d votes, want 3 / 4", results.TotalVoters, results.TotalVotes)
+	}
+	want := []struct {
+		label string
+		count int
+		pct   float64
+	}{{"a", 2, 50}, {"b", 2, 50}, {"c", 0, 0}}
+	for i, w := range want {
+		got := results.Options[i]
+		if got.Label != w.label || got.Count != w.count || got.Percentage != w.pct {
+			t.Errorf("option %d = %+v, want %+v", i, got, w)
+		}
+		if len(got.Voters) != w.count {
+			t.Errorf("option %s voters = %d, want %d", got.Label, len(got.Voters), w.count)
+		}
+	}
+
+	anonResults, err := svc.GetVoteResults(ctx, "anon")
+	if err != nil {
+		t.Fatalf("GetVoteResults: %v", err)
+	}
+	for _, opt := range anonResults.Options {
+		if opt.Voters != nil {
+			t.Errorf("anonymous event exposed voters for %s", opt.Label)
+		}
+	}
+
+	events.Create(ctx, lineUpEvent("lineup", models.EventConfig{}))
+	if _, err := svc.GetVoteResults(ctx, "lineup"); !errors.Is(err, ErrNotVoteEvent) {
+		t.Errorf("err = %v, want ErrNotVoteEvent", err)
+	}
+}
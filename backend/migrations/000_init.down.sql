@@ -0,0 +1,5 @@
+-- Rollback: Drop the base schema. All data is lost.
+
+DROP TABLE IF EXISTS interactions;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS users;
@@ -1,4 +1,4 @@
--- Event Manager PostgreSQL Schema
+-- Migration: Base schema (events, interactions and users); later migrations add to it
 -- Uses JSONB for flexible configuration storage (similar to Firestore)
 
 -- Events table
@@ -8,7 +8,6 @@ CREATE TABLE IF NOT EXISTS events (
     title           VARCHAR(255) NOT NULL,
     tag             VARCHAR(100),
     is_active       BOOLEAN DEFAULT true,
-    is_archived     BOOLEAN DEFAULT false,
     created_by      VARCHAR(50) NOT NULL,
     created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     config          JSONB NOT NULL DEFAULT '{}'
@@ -17,7 +16,6 @@ CREATE TABLE IF NOT EXISTS events (
 CREATE INDEX IF NOT EXISTS idx_events_created_at ON events(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
 CREATE INDEX IF NOT EXISTS idx_events_is_active ON events(is_active);
-CREATE INDEX IF NOT EXISTS idx_events_is_archived ON events(is_archived);
 CREATE INDEX IF NOT EXISTS idx_events_tag ON events(tag);
 
 
@@ -55,6 +53,7 @@ COMMENT ON COLUMN events.config IS 'JSON structure: {
     "allowMultiSelect": boolean,
     "maxVotes": int,
     "options": string[],
+    "voteOptions": [{"id": string, "label": string}],
     "maxParticipants": int,
     "waitlistLimit": int,
     "maxCountPerUser": int,
@@ -65,7 +64,7 @@ COMMENT ON COLUMN events.config IS 'JSON structure: {
 }';
 
 COMMENT ON COLUMN interactions.payload IS 'JSON structure varies by type:
-VOTE: {"selectedOptions": string[]}
-LINEUP: {"count": int, "note": string, "cancelledAt": timestamp}
+VOTE: {"selectedOptions": string[]} (option IDs from config.voteOptions)
+LINEUP: {"count": int, "note": string, "cancelledAt": timestamp, "cancelledBy": string, "promotedAt": timestamp, "addedBy": string}
 MEMO: {"content": string, "clapCount": int, "reactions": string[]}
 ';
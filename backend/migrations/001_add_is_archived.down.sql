@@ -0,0 +1,4 @@
+-- Rollback: Remove is_archived column from events table
+
+DROP INDEX IF EXISTS idx_events_is_archived;
+ALTER TABLE events DROP COLUMN IF EXISTS is_archived;
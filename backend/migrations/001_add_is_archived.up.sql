@@ -0,0 +1,7 @@
+-- Migration: Add is_archived column to events table (event archiving)
+
+-- Add is_archived column if it doesn't exist
+ALTER TABLE events ADD COLUMN IF NOT EXISTS is_archived BOOLEAN DEFAULT false;
+
+-- Create index for archive filtering
+CREATE INDEX IF NOT EXISTS idx_events_is_archived ON events(is_archived);
@@ -0,0 +1,8 @@
+-- Rollback: Remove per-event revisions
+
+DROP TRIGGER IF EXISTS trg_interactions_revision ON interactions;
+DROP FUNCTION IF EXISTS bump_interaction_revision();
+
+DROP INDEX IF EXISTS idx_interactions_event_revision;
+ALTER TABLE interactions DROP COLUMN IF EXISTS revision;
+ALTER TABLE events DROP COLUMN IF EXISTS revision;
@@ -0,0 +1,22 @@
+-- Migration: Add per-event revisions for incremental status fetches
+-- (GET /events/:id/status?since=<rev>)
+
+-- Revision counter per event and revision stamp per interaction
+ALTER TABLE events ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE interactions ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_interactions_event_revision ON interactions(event_id, revision);
+
+-- Bump the event revision on every interaction write
+CREATE OR REPLACE FUNCTION bump_interaction_revision() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE events SET revision = revision + 1 WHERE event_id = NEW.event_id
+    RETURNING revision INTO NEW.revision;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_interactions_revision ON interactions;
+CREATE TRIGGER trg_interactions_revision
+    BEFORE INSERT OR UPDATE ON interactions
+    FOR EACH ROW EXECUTE FUNCTION bump_interaction_revision();
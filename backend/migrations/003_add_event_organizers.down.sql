@@ -0,0 +1,3 @@
+-- Rollback: Remove per-event co-organizers
+
+DROP TABLE IF EXISTS event_organizers;
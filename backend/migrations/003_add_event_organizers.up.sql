@@ -0,0 +1,11 @@
+-- Migration: Add per-event co-organizers
+
+CREATE TABLE IF NOT EXISTS event_organizers (
+    event_id        VARCHAR(36) NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id         VARCHAR(50) NOT NULL,
+    added_by        VARCHAR(50) NOT NULL,
+    added_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_organizers_user ON event_organizers(user_id);
@@ -0,0 +1,3 @@
+-- Rollback: Remove audit log
+
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,14 @@
+-- Migration: Add audit log of who changed what (GET /events/:id/audit)
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        VARCHAR(36) NOT NULL,
+    record_id       VARCHAR(100),
+    actor           VARCHAR(50) NOT NULL,
+    action          VARCHAR(40) NOT NULL,
+    before          JSONB,
+    after           JSONB,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_event_created ON audit_log(event_id, created_at DESC);
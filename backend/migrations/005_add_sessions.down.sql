@@ -0,0 +1,3 @@
+-- Rollback: Remove login sessions. Refresh tokens stop working; users must log in again.
+
+DROP TABLE IF EXISTS sessions;
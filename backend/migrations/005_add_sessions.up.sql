@@ -0,0 +1,16 @@
+-- Migration: Add login sessions for refresh tokens (/auth/refresh, /auth/logout)
+-- and server-side session revocation
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id                  VARCHAR(36) PRIMARY KEY,
+    user_id             VARCHAR(50) NOT NULL,
+    refresh_token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    previous_token_hash VARCHAR(64),
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at          TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_token ON sessions(previous_token_hash);
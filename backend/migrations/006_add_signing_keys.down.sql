@@ -0,0 +1,3 @@
+-- Rollback: Remove JWT signing keys. Access tokens signed with them stop verifying.
+
+DROP TABLE IF EXISTS signing_keys;
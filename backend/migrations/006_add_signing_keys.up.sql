@@ -0,0 +1,10 @@
+-- Migration: Add JWT signing keys for asymmetric access tokens with key rotation
+-- (/.well-known/jwks.json). Sessions signed with the old shared secret must log in again.
+
+CREATE TABLE IF NOT EXISTS signing_keys (
+    kid             VARCHAR(36) PRIMARY KEY,
+    algorithm       VARCHAR(10) NOT NULL CHECK (algorithm IN ('RS256', 'EdDSA')),
+    private_key     BYTEA NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,4 @@
+-- Rollback: Remove custom display names and per-event nicknames
+
+DROP TABLE IF EXISTS event_nicknames;
+ALTER TABLE users DROP COLUMN IF EXISTS custom_name;
@@ -0,0 +1,13 @@
+-- Migration: Add custom display names and per-event nicknames (GET/PATCH /me)
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS custom_name VARCHAR(100);
+
+CREATE TABLE IF NOT EXISTS event_nicknames (
+    event_id        VARCHAR(36) NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id         VARCHAR(50) NOT NULL,
+    nickname        VARCHAR(100) NOT NULL,
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_nicknames_user ON event_nicknames(user_id);
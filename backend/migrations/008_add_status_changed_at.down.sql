@@ -0,0 +1,3 @@
+-- Rollback: Remove status_changed_at from events
+
+ALTER TABLE events DROP COLUMN IF EXISTS status_changed_at;
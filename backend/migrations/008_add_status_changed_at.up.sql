@@ -0,0 +1,4 @@
+-- Migration: Record when an event's active flag was last set, so the scheduler's
+-- start-up catch-up leaves manual changes alone
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,11 @@
+package migrations
+
+import "embed"
+
+// FS holds the PostgreSQL schema migrations applied by internal/migrate. Version N is a
+// pair of files, NNN_name.up.sql and NNN_name.down.sql. Never edit a released
+// migration: the runner refuses to run when an applied migration's checksum has
+// changed. Add a new version instead.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -6,6 +6,8 @@ export const useAuthStore = defineStore('auth', {
     state: () => ({
         user: null,
         token: null,
+        refreshToken: null,
+        refreshInterceptor: null,
         liffId: import.meta.env.VITE_LIFF_ID,
         isLiffInitialized: false,
         error: null
@@ -56,15 +58,57 @@ export const useAuthStore = defineStore('auth', {
                 console.log('Login attempt with idToken:', idToken ? `${idToken.substring(0, 20)}...` : 'EMPTY!')
                 console.log('idToken length:', idToken ? idToken.length : 0)
                 const response = await axios.post('/api/auth/login', { idToken })
-                this.token = response.data.token
-                this.user = response.data.user
-
-                // Set default auth header
-                axios.defaults.headers.common['Authorization'] = `Bearer ${this.token}`
+                this.setSession(response.data)
+                this.installRefreshInterceptor()
             } catch (err) {
                 this.error = 'Backend Login Failed: ' + err.message
                 console.error(err)
             }
+        },
+        setSession(data) {
+            this.token = data.token
+            this.refreshToken = data.refreshToken
+            this.user = data.user
+
+            // Set default auth header
+            axios.defaults.headers.common['Authorization'] = `Bearer ${this.token}`
+        },
+        // Access tokens are short-lived: on a 401, exchange the refresh token once and retry
+        installRefreshInterceptor() {
+            if (this.refreshInterceptor !== null) return
+            let refreshing = null
+            this.refreshInterceptor = axios.interceptors.response.use(null, async (error) => {
+                const original = error.config
+                if (error.response?.status !== 401 || !this.refreshToken || original._retried || original.url.includes('/auth/')) {
+                    return Promise.reject(error)
+                }
+                original._retried = true
+                try {
+                    refreshing = refreshing || axios.post('/api/auth/refresh', { refreshToken: this.refreshToken })
+                    const response = await refreshing
+                    this.setSession(response.data)
+                } catch (refreshError) {
+                    // Session revoked or expired: log in with LINE again
+                    this.token = null
+                    this.refreshToken = null
+                    liff.login()
+                    return Promise.reject(refreshError)
+                } finally {
+                    refreshing = null
+                }
+                original.headers['Authorization'] = `Bearer ${this.token}`
+                return axios(original)
+            })
+        },
+        async logout() {
+            if (this.refreshToken) {
+                await axios.post('/api/auth/logout', { refreshToken: this.refreshToken }).catch(() => {})
+            }
+            this.token = null
+            this.refreshToken = null
+            this.user = null
+            delete axios.defaults.headers.common['Authorization']
+            liff.logout()
         }
     }
 })
@@ -17,6 +17,7 @@ NC='\033[0m' # No Color
 
 # Configuration
 CONTAINER_NAME="event-postgres"
+BACKEND_CONTAINER="event-backend"
 DB_NAME="${POSTGRES_DB:-eventmanager}"
 DB_USER="${POSTGRES_USER:-eventmanager}"
 DB_PORT="${POSTGRES_PORT:-5433}"
@@ -43,7 +44,7 @@ show_help() {
     echo "  logs          Show PostgreSQL logs"
     echo "  backup        Backup database to file"
     echo "  restore       Restore database from file"
-    echo "  init          Initialize/reset database schema"
+    echo "  init          Apply pending schema migrations"
     echo "  query         Execute SQL query"
     echo "  tables        List all tables"
     echo "  count         Show record counts"
@@ -150,15 +151,16 @@ restore_database() {
 
 init_schema() {
     check_container
-    local init_file="./backend/init.sql"
     
-    if [ ! -f "$init_file" ]; then
-        echo -e "${RED}Error: init.sql not found at ${init_file}${NC}"
+    if ! docker ps --format '{{.Names}}' | grep -q "^${BACKEND_CONTAINER}$"; then
+        echo -e "${RED}Error: Backend container '${BACKEND_CONTAINER}' is not running!${NC}"
+        echo "The schema migrations are embedded in the backend binary"
         exit 1
     fi
     
-    echo -e "${YELLOW}Initializing database schema...${NC}"
-    cat "${init_file}" | docker exec -i ${CONTAINER_NAME} psql -U ${DB_USER} -d ${DB_NAME}
+    echo -e "${YELLOW}Applying schema migrations...${NC}"
+    docker exec ${BACKEND_CONTAINER} ./main migrate up
+    docker exec ${BACKEND_CONTAINER} ./main migrate status
     
     echo -e "${GREEN}✓ Schema initialized${NC}"
 }
@@ -277,9 +279,8 @@ reset_database() {
     
     echo -e "${YELLOW}Resetting database...${NC}"
     docker exec ${CONTAINER_NAME} psql -U ${DB_USER} -d ${DB_NAME} -c "
-        DROP TABLE IF EXISTS interactions CASCADE;
-        DROP TABLE IF EXISTS events CASCADE;
-        DROP TABLE IF EXISTS users CASCADE;
+        DROP SCHEMA public CASCADE;
+        CREATE SCHEMA public;
     "
     
     init_schema